@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"blog-system/services"
+	"blog-system/utils"
+)
+
+func init() {
+	register(command{
+		name:    "create-admin",
+		usage:   "create-admin -username -email",
+		summary: "创建管理员账号（未指定 -password 时生成随机密码）",
+		run:     runCreateAdmin,
+	})
+	register(command{
+		name:    "reset-password",
+		usage:   "reset-password -username",
+		summary: "重置用户密码（未指定 -password 时生成随机密码）",
+		run:     runResetPassword,
+	})
+}
+
+// runCreateAdmin 创建管理员账号
+func runCreateAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := fs.String("username", "admin", "用户名")
+	email := fs.String("email", "", "邮箱（必填）")
+	password := fs.String("password", "", "密码，留空则生成随机密码")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("必须指定 -email")
+	}
+
+	pwd, generated, err := passwordOrRandom(*password)
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	userService := services.NewUserService()
+	if userService.UsernameExists(*username) {
+		return fmt.Errorf("用户名 %s 已存在，如需修改密码请使用 reset-password", *username)
+	}
+	if userService.EmailExists(*email) {
+		return fmt.Errorf("邮箱 %s 已存在", *email)
+	}
+
+	user, err := services.NewAuthService().Register(*username, *email, pwd, "")
+	if err != nil {
+		return fmt.Errorf("创建用户失败: %v", err)
+	}
+	if err := userService.SetUserRole(cliContext(), user.ID, "admin"); err != nil {
+		return fmt.Errorf("设置管理员角色失败: %v", err)
+	}
+
+	fmt.Printf("管理员已创建: id=%d username=%s email=%s\n", user.ID, user.Username, user.Email)
+	if generated {
+		fmt.Printf("初始密码: %s\n请登录后立即修改密码\n", pwd)
+	}
+	return nil
+}
+
+// runResetPassword 重置用户密码
+func runResetPassword(args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	username := fs.String("username", "", "用户名（必填）")
+	password := fs.String("password", "", "新密码，留空则生成随机密码")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("必须指定 -username")
+	}
+
+	pwd, generated, err := passwordOrRandom(*password)
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	userService := services.NewUserService()
+	user, err := userService.GetUserByUsername(*username)
+	if err != nil {
+		return fmt.Errorf("用户 %s 不存在", *username)
+	}
+	if err := userService.ResetPassword(user.ID, pwd); err != nil {
+		return fmt.Errorf("重置密码失败: %v", err)
+	}
+
+	fmt.Printf("用户 %s 的密码已重置\n", user.Username)
+	if generated {
+		fmt.Printf("新密码: %s\n", pwd)
+	}
+	return nil
+}
+
+// passwordOrRandom 校验给定密码，为空时生成随机密码
+func passwordOrRandom(password string) (string, bool, error) {
+	if password == "" {
+		pwd, err := utils.GenerateRandomPassword(16)
+		return pwd, true, err
+	}
+	if err := utils.ValidatePasswordStrength(password); err != nil {
+		return "", false, err
+	}
+	return password, false, nil
+}
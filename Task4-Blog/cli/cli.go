@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/jobs"
+	"blog-system/services"
+)
+
+// command 子命令
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+// commands 所有子命令
+var commands []command
+
+// register 注册子命令
+func register(cmd command) {
+	commands = append(commands, cmd)
+}
+
+// Run 解析命令行参数并执行对应的子命令，未指定子命令时启动服务器
+func Run(args []string) error {
+	if len(args) == 0 {
+		return runServe(nil)
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return nil
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args[1:])
+		}
+	}
+
+	printUsage()
+	return fmt.Errorf("未知命令: %s", name)
+}
+
+// printUsage 打印帮助信息
+func printUsage() {
+	sorted := make([]command, len(commands))
+	copy(sorted, commands)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+
+	fmt.Fprint(os.Stderr, "用法: blog-system <命令> [参数]\n\n可用命令:\n")
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	for _, cmd := range sorted {
+		fmt.Fprintf(w, "  %s\t%s\n", cmd.usage, cmd.summary)
+	}
+	w.Flush()
+}
+
+// cliContext 命令行操作使用的 context，审计日志中的操作者记为 cli
+func cliContext() context.Context {
+	return services.WithActor(context.Background(), services.Actor{Username: "cli"})
+}
+
+// bootstrap 初始化配置和数据库连接，返回释放资源的函数
+func bootstrap() (func(), error) {
+	// 1. 初始化配置
+	if err := config.Init(); err != nil {
+		return nil, fmt.Errorf("配置初始化失败: %v", err)
+	}
+
+	// 2. 初始化数据库连接
+	if _, err := database.InitDB(); err != nil {
+		return nil, fmt.Errorf("数据库初始化失败: %v", err)
+	}
+
+	return func() {
+		// 停止后台任务并刷新缓冲区，使用独立的超时时间，不受服务器关闭耗时的影响
+		if err := jobs.Stop(time.Duration(config.GetConfig().Server.ShutdownTimeout) * time.Second); err != nil {
+			log.Printf("停止后台任务失败: %v", err)
+		}
+
+		if err := database.CloseDB(); err != nil {
+			log.Printf("关闭数据库连接失败: %v", err)
+		} else {
+			log.Println(" 数据库连接已关闭")
+		}
+	}, nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"blog-system/config"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func init() {
+	register(command{
+		name:    "config",
+		usage:   "config print|check|keygen|encrypt|decrypt",
+		summary: "打印或校验生效的配置，管理加密凭证文件",
+		run:     runConfig,
+	})
+}
+
+// runConfig 执行配置子命令
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: config print|check|keygen|encrypt|decrypt")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("config "+action, flag.ContinueOnError)
+	in := fs.String("in", "", "输入文件（encrypt 为明文 YAML，decrypt 为加密凭证文件）")
+	out := fs.String("out", "", "输出文件，为空时输出到标准输出")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch action {
+	case "print":
+		if err := config.Init(); err != nil {
+			return fmt.Errorf("配置初始化失败: %v", err)
+		}
+		data, err := yaml.Marshal(config.Redacted(config.GetConfig()))
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+
+	case "check":
+		if err := config.Init(); err != nil {
+			return err
+		}
+		profile := config.Profile()
+		if profile == "" {
+			profile = "（未设置 BLOG_ENV）"
+		}
+		fmt.Printf("配置校验通过\n环境: %s\n配置文件: %v\n", profile, config.Files())
+
+	case "keygen":
+		key, err := config.GenerateCredentialsKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		fmt.Fprintf(os.Stderr, "请妥善保存密钥，运行时通过 %s 或 %s_FILE 提供\n", config.CredentialsKeyEnv, config.CredentialsKeyEnv)
+
+	case "encrypt", "decrypt":
+		if *in == "" {
+			return fmt.Errorf("用法: config %s -in <文件> [-out <文件>]", action)
+		}
+		data, err := os.ReadFile(*in)
+		if err != nil {
+			return err
+		}
+		key, err := config.CredentialsKey()
+		if err != nil {
+			return err
+		}
+		var result []byte
+		if action == "encrypt" {
+			result, err = config.EncryptCredentials(data, key)
+		} else {
+			result, err = config.DecryptCredentials(data, key)
+		}
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = os.Stdout.Write(result)
+			return err
+		}
+		return os.WriteFile(*out, result, 0600)
+
+	default:
+		return fmt.Errorf("用法: config print|check|keygen|encrypt|decrypt")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/seed"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	register(command{
+		name:    "seed",
+		usage:   "seed [-users -posts -seed ...]",
+		summary: "按随机种子生成可复现的测试数据",
+		run:     runSeed,
+	})
+	register(command{
+		name:    "reset-db",
+		usage:   "reset-db -yes",
+		summary: "回滚全部迁移并重建数据库（release 模式下禁用）",
+		run:     runResetDB,
+	})
+}
+
+// runSeed 写入测试数据
+func runSeed(args []string) error {
+	opts := seed.DefaultOptions()
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Int64Var(&opts.Seed, "seed", opts.Seed, "随机种子，相同种子生成相同的数据")
+	fs.IntVar(&opts.Users, "users", opts.Users, "用户数量")
+	fs.IntVar(&opts.Posts, "posts", opts.Posts, "文章数量")
+	fs.IntVar(&opts.Tags, "tags", opts.Tags, "标签数量")
+	fs.Float64Var(&opts.CommentsPerPost, "comments", opts.CommentsPerPost, "已发布文章的平均评论数")
+	fs.IntVar(&opts.MaxDepth, "max-depth", opts.MaxDepth, "评论最大嵌套层数")
+	fs.IntVar(&opts.BatchSize, "batch", opts.BatchSize, "每批写入的文章数量")
+	fs.IntVar(&opts.Days, "days", opts.Days, "数据时间跨度（天）")
+	until := fs.String("until", "", "数据时间范围的结束日期（YYYY-MM-DD），默认今天")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *until != "" {
+		t, err := time.ParseInLocation("2006-01-02", *until, time.Local)
+		if err != nil {
+			return fmt.Errorf("日期格式不正确: %s", *until)
+		}
+		opts.Until = t
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := database.MigrationsApplied(context.Background()); err != nil {
+		return err
+	}
+	return database.CreateTestData(opts)
+}
+
+// runResetDB 重置数据库
+func runResetDB(args []string) error {
+	fs := flag.NewFlagSet("reset-db", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "确认删除所有数据")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cfg := config.GetConfig()
+	if cfg.Server.Mode == gin.ReleaseMode {
+		return fmt.Errorf("release 模式下禁止重置数据库")
+	}
+	if !*yes {
+		return fmt.Errorf("该操作会删除数据库 %s 中的所有数据，确认请加 -yes 参数", cfg.Database.DBName)
+	}
+
+	return database.ResetDatabase()
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"blog-system/database"
+	"blog-system/importer"
+)
+
+func init() {
+	register(command{
+		name:    "import",
+		usage:   "import [-format -author-map -dry-run ...] <路径>",
+		summary: "从 Hugo/Jekyll Markdown 目录、ZIP 或 WordPress WXR 导入文章",
+		run:     runImport,
+	})
+}
+
+// runImport 导入文章
+func runImport(args []string) error {
+	var opts importer.Options
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", importer.FormatAuto, "导入格式: auto, markdown, wxr")
+	authorMap := fs.String("author-map", "", "作者映射，如 wp_admin=admin,alice=alice2")
+	source := fs.String("source", "", "来源标识，默认由文件名生成；重复导入同一来源时需保持一致")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出报告")
+	fs.StringVar(&opts.DefaultAuthor, "default-author", "", "作者无法匹配时使用的本地用户名")
+	fs.BoolVar(&opts.CreateMissingAuthors, "create-authors", false, "作者无法匹配时自动创建用户")
+	fs.StringVar(&opts.FallbackCommenter, "fallback-commenter", "", "评论者不是本地用户时使用的本地用户名")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "只输出变更预览，不写入数据库")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: import [参数] <目录|.zip|.xml>")
+	}
+
+	var err error
+	if opts.AuthorMap, err = importer.ParseAuthorMap(*authorMap); err != nil {
+		return err
+	}
+
+	doc, err := importer.Load(fs.Arg(0), *format)
+	if err != nil {
+		return err
+	}
+	if *source != "" {
+		doc.Source = *source
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := database.MigrationsApplied(context.Background()); err != nil {
+		return err
+	}
+
+	report, err := importer.Import(database.GetDB(), doc, opts)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printImportReport(report)
+	return nil
+}
+
+// printImportReport 以表格形式输出导入报告
+func printImportReport(report *importer.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tACTION\tKEY\tTITLE\tDETAIL")
+	for _, c := range report.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Type, c.Action, c.Key, c.Title, c.Detail)
+	}
+	w.Flush()
+
+	fmt.Printf("\n来源: %s (%s)\n", report.Source, report.Format)
+	for _, typ := range []string{"user", "tag", "post", "comment"} {
+		if counts, ok := report.Summary[typ]; ok {
+			fmt.Printf("%-8s 新建 %d，更新 %d，跳过 %d，冲突 %d\n", typ, counts.Create, counts.Update, counts.Skip, counts.Conflict)
+		}
+	}
+	if report.DryRun {
+		fmt.Println("预演模式，未写入任何数据")
+	}
+}
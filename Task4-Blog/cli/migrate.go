@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"blog-system/database"
+)
+
+func init() {
+	register(command{
+		name:    "migrate",
+		usage:   "migrate up|down [n]|status|to <版本>",
+		summary: "管理数据库版本化迁移",
+		run:     runMigrate,
+	})
+}
+
+// runMigrate 执行迁移子命令
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("用法: migrate up|down [n]|status|to <版本>")
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	migrator, err := database.NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	action, rest := fs.Arg(0), fs.Args()[1:]
+
+	switch action {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已执行 %d 个迁移\n", count)
+
+	case "down":
+		steps := 1
+		if len(rest) > 0 {
+			if steps, err = strconv.Atoi(rest[0]); err != nil || steps < 1 {
+				return fmt.Errorf("回滚数量必须是正整数: %s", rest[0])
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已回滚 %d 个迁移\n", count)
+
+	case "to":
+		if len(rest) == 0 {
+			return fmt.Errorf("用法: migrate to <版本>")
+		}
+		version, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("版本号必须是非负整数: %s", rest[0])
+		}
+		count, err := migrator.To(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已迁移到版本 %d（变更 %d 个迁移）\n", version, count)
+
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "版本\t名称\t状态\t执行时间")
+		for _, st := range states {
+			status, at := "未执行", "-"
+			if st.Applied {
+				status = "已执行"
+				at = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, status, at)
+		}
+		w.Flush()
+
+	default:
+		return fmt.Errorf("未知的迁移操作: %s", action)
+	}
+
+	return nil
+}
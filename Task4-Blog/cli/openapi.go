@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"blog-system/config"
+	"blog-system/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	register(command{
+		name:    "openapi",
+		usage:   "openapi print|check",
+		summary: "输出 OpenAPI 文档，或检查是否有未写入文档的路由",
+		run:     runOpenAPI,
+	})
+}
+
+// runOpenAPI 执行 OpenAPI 子命令，只需要加载配置，不连接数据库
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch fs.Arg(0) {
+	case "print":
+		out, err := json.MarshalIndent(routes.BuildOpenAPI(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+
+	case "check":
+		if err := config.Init(); err != nil {
+			return fmt.Errorf("配置初始化失败: %v", err)
+		}
+		gin.SetMode(gin.ReleaseMode)
+		r := gin.New()
+		routes.SetupRoutes(r)
+
+		missing, stale := routes.UndocumentedRoutes(r)
+		for _, route := range missing {
+			fmt.Fprintf(os.Stderr, "未写入文档的路由: %s\n", route)
+		}
+		for _, route := range stale {
+			fmt.Fprintf(os.Stderr, "文档中不存在的路由: %s\n", route)
+		}
+		if len(missing) > 0 || len(stale) > 0 {
+			return fmt.Errorf("OpenAPI 文档与路由不一致（缺少 %d 个，多余 %d 个）", len(missing), len(stale))
+		}
+		fmt.Printf("全部 %d 个路由均已写入文档\n", len(r.Routes()))
+		return nil
+
+	default:
+		return fmt.Errorf("用法: openapi print|check")
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/grpcserver"
+	"blog-system/jobs"
+	"blog-system/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	register(command{
+		name:    "serve",
+		usage:   "serve",
+		summary: "启动 HTTP 服务器（默认命令）",
+		run:     runServe,
+	})
+}
+
+// runServe 启动服务器并阻塞直到收到退出信号，返回前释放所有资源
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// 3. 检查数据库版本，按配置自动执行迁移
+	cfg := config.GetConfig()
+	if cfg.Database.AutoMigrate {
+		if err := database.Migrate(); err != nil {
+			return fmt.Errorf("数据库迁移失败: %v", err)
+		}
+	} else if err := database.MigrationsApplied(context.Background()); err != nil {
+		return fmt.Errorf("拒绝启动: %v", err)
+	}
+
+	// 4. 设置 Gin 运行模式
+	gin.SetMode(cfg.Server.Mode)
+
+	// 5. 初始化 Gin
+	r := gin.Default()
+
+	// 6. 设置路由
+	routes.SetupRoutes(r)
+	if missing, _ := routes.UndocumentedRoutes(r); len(missing) > 0 {
+		log.Printf("警告: %d 个路由未写入 OpenAPI 文档: %v", len(missing), missing)
+	}
+
+	// 各组件已注册配置变更监听，开始监听配置文件
+	config.Watch()
+
+	// 7. 监听 gRPC 端口（在启动后台任务前完成，端口被占用时直接退出）
+	var grpcServer *grpcserver.Server
+	var grpcListener net.Listener
+	if cfg.GRPC.Enabled {
+		grpcListener, err = net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			return fmt.Errorf("gRPC 端口监听失败: %v", err)
+		}
+		grpcServer = grpcserver.NewServer(cfg.GRPC)
+	}
+
+	// 8. 启动后台任务
+	jobs.Start()
+
+	// 9. 启动服务器
+	serverConfig := cfg.Server
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", serverConfig.Port),
+		Handler:           r,
+		ReadTimeout:       seconds(serverConfig.ReadTimeout),
+		WriteTimeout:      seconds(serverConfig.WriteTimeout),
+		IdleTimeout:       seconds(serverConfig.IdleTimeout),
+		ReadHeaderTimeout: seconds(serverConfig.ReadHeaderTimeout),
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("服务器启动在 :%d 端口 [%s 模式]", serverConfig.Port, serverConfig.Mode)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	grpcErr := make(chan error, 1)
+	if grpcServer != nil {
+		go func() {
+			log.Printf("gRPC 服务器启动在 :%d 端口", cfg.GRPC.Port)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				grpcErr <- err
+			}
+		}()
+	}
+
+	// 10. 等待退出信号
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var startErr error
+	select {
+	case startErr = <-serverErr:
+		if startErr != nil {
+			startErr = fmt.Errorf("服务器启动失败: %v", startErr)
+		}
+	case startErr = <-grpcErr:
+		startErr = fmt.Errorf("gRPC 服务器异常退出: %v", startErr)
+	case sig := <-quit:
+		log.Printf("收到信号 %s，开始优雅关闭...", sig)
+	}
+
+	// 11. 优雅关闭：停止接收新请求并等待进行中的请求完成
+	ctx, cancel := context.WithTimeout(context.Background(), seconds(serverConfig.ShutdownTimeout))
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("服务器关闭超时，强制关闭: %v", err)
+		srv.Close()
+	} else {
+		log.Println("服务器已停止接收新请求")
+	}
+
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			log.Printf("gRPC 服务器关闭超时，强制关闭: %v", err)
+		} else {
+			log.Println("gRPC 服务器已停止")
+		}
+	}
+
+	// 12. 后台任务和缓冲区由 bootstrap 返回的 cleanup 在关闭数据库前停止和刷新
+	return startErr
+}
+
+// seconds 将以秒为单位的配置值转换为 time.Duration
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
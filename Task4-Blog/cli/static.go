@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/sitegen"
+)
+
+func init() {
+	register(command{
+		name:    "build-static",
+		usage:   "build-static [-out -base-url -theme -full]",
+		summary: "将已发布的公开文章生成为静态站点（默认增量构建）",
+		run:     runBuildStatic,
+	})
+}
+
+// runBuildStatic 生成静态站点
+func runBuildStatic(args []string) error {
+	fs := flag.NewFlagSet("build-static", flag.ContinueOnError)
+	out := fs.String("out", "", "输出目录，默认为 static.output_dir")
+	baseURL := fs.String("base-url", "", "站点根地址，默认为 static.base_url")
+	theme := fs.String("theme", "", "主题目录，默认为 static.theme（为空时使用内置主题）")
+	full := fs.Bool("full", false, "忽略上次构建记录，重新生成全部页面")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := database.MigrationsApplied(context.Background()); err != nil {
+		return err
+	}
+
+	cfg := config.GetConfig().Static
+	opts := sitegen.Options{
+		OutputDir:   cfg.OutputDir,
+		BaseURL:     cfg.BaseURL,
+		Title:       cfg.Title,
+		Description: cfg.Description,
+		Theme:       cfg.Theme,
+		PageSize:    cfg.PageSize,
+		FeedSize:    cfg.FeedSize,
+		Full:        *full,
+	}
+	if *out != "" {
+		opts.OutputDir = *out
+	}
+	if *baseURL != "" {
+		opts.BaseURL = *baseURL
+	}
+	if *theme != "" {
+		opts.Theme = *theme
+	}
+
+	stats, err := sitegen.Build(database.GetDB(), opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已生成静态站点 %s：%d 篇文章，%d 个页面，重新生成 %d，未变化 %d，删除 %d，耗时 %s\n",
+		opts.OutputDir, stats.Posts, stats.Pages, stats.Rendered, stats.Skipped, stats.Removed, stats.Duration.Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"blog-system/services"
+)
+
+func init() {
+	register(command{
+		name:    "user",
+		usage:   "user list|ban|unban [用户名]",
+		summary: "查看用户列表、封禁或解封用户",
+		run:     runUser,
+	})
+}
+
+// runUser 执行用户管理子命令
+func runUser(args []string) error {
+	fs := flag.NewFlagSet("user", flag.ContinueOnError)
+	page := fs.Int("page", 1, "页码（list）")
+	pageSize := fs.Int("page-size", 20, "每页数量（list）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("用法: user list|ban|unban [用户名]")
+	}
+
+	cleanup, err := bootstrap()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	userService := services.NewUserService()
+
+	switch action := fs.Arg(0); action {
+	case "list":
+		users, total, err := userService.GetUsers(*page, *pageSize)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\t用户名\t邮箱\t角色\t状态\t最后登录")
+		for _, u := range users {
+			status := "正常"
+			if !u.IsActive {
+				status = "已封禁"
+			}
+			lastLogin := "-"
+			if u.LastLogin != nil {
+				lastLogin = u.LastLogin.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", u.ID, u.Username, u.Email, u.Role, status, lastLogin)
+		}
+		w.Flush()
+		fmt.Printf("共 %d 个用户，第 %d 页\n", total, *page)
+
+	case "ban", "unban":
+		if fs.NArg() < 2 {
+			return fmt.Errorf("用法: user %s <用户名>", action)
+		}
+		user, err := userService.GetUserByUsername(fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("用户 %s 不存在", fs.Arg(1))
+		}
+		active := action == "unban"
+		if err := userService.SetUserActive(cliContext(), user.ID, active); err != nil {
+			return err
+		}
+		if active {
+			fmt.Printf("用户 %s 已解封\n", user.Username)
+		} else {
+			fmt.Printf("用户 %s 已封禁\n", user.Username)
+		}
+
+	default:
+		return fmt.Errorf("未知的用户操作: %s", action)
+	}
+
+	return nil
+}
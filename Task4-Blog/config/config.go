@@ -4,160 +4,579 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 
 	"github.com/spf13/viper"
 )
 
 // Config 全局配置结构体
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Log       LogConfig       `mapstructure:"log"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Health    HealthConfig    `mapstructure:"health"`
+	Export    ExportConfig    `mapstructure:"export"`
+	Static    StaticConfig    `mapstructure:"static"`
+	GraphQL   GraphQLConfig   `mapstructure:"graphql"`
+	GRPC      GRPCConfig      `mapstructure:"grpc"`
+	Webhook   WebhookConfig   `mapstructure:"webhook"`
+	SIWE      SIWEConfig      `mapstructure:"siwe"`
+	TwoFactor TwoFactorConfig `mapstructure:"two_factor"`
+	Login     LoginConfig     `mapstructure:"login"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	Security  SecurityConfig  `mapstructure:"security"`
+	// 加密凭证文件，解密后与配置文件合并
+	Credentials CredentialsConfig `mapstructure:"credentials"`
+	// 个人访问令牌
+	AccessToken AccessTokenConfig `mapstructure:"access_token"`
+	// 审计日志
+	Audit AuditConfig `mapstructure:"audit"`
+	// 回收站
+	Trash TrashConfig `mapstructure:"trash"`
+	// 评论
+	Comment CommentConfig `mapstructure:"comment"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port         int    `mapstructure:"port"`
-	Mode         string `mapstructure:"mode"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`
-	WriteTimeout int    `mapstructure:"write_timeout"`
+	Port              int    `mapstructure:"port"`
+	Mode              string `mapstructure:"mode"`
+	ReadTimeout       int    `mapstructure:"read_timeout"`        // 读取超时（秒）
+	WriteTimeout      int    `mapstructure:"write_timeout"`       // 写入超时（秒）
+	IdleTimeout       int    `mapstructure:"idle_timeout"`        // 空闲连接超时（秒）
+	ReadHeaderTimeout int    `mapstructure:"read_header_timeout"` // 读取请求头超时（秒）
+	ShutdownTimeout   int    `mapstructure:"shutdown_timeout"`    // 优雅关闭等待时间（秒）
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Level string `mapstructure:"level"` // debug, info, warn, error
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
+	Driver   string `mapstructure:"driver"` // mysql, postgres
 	Host     string `mapstructure:"host"`
 	Port     int    `mapstructure:"port"`
 	User     string `mapstructure:"user"`
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	Charset  string `mapstructure:"charset"`
+	SSLMode  string `mapstructure:"sslmode"` // 仅 postgres 使用
 	// 连接池配置
 	MaxIdleConns int `mapstructure:"max_idle_conns"`
 	MaxOpenConns int `mapstructure:"max_open_conns"`
+	// 启动时自动执行未应用的迁移，关闭时若数据库版本落后则拒绝启动
+	AutoMigrate bool `mapstructure:"auto_migrate"`
 }
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret string `mapstructure:"secret"`
-	Expire int    `mapstructure:"expire"` // 过期时间（小时）
-	Issuer string `mapstructure:"issuer"`
+	Secret       string `mapstructure:"secret"` // HS256 签名密钥，algorithm 为 RS256 / EdDSA 时不使用
+	Expire       int    `mapstructure:"expire"` // 过期时间（小时）
+	Issuer       string `mapstructure:"issuer"`
+	Algorithm    string `mapstructure:"algorithm"`     // 签名算法：HS256（共享密钥）、RS256、EdDSA（密钥保存在数据库中，公钥通过 JWKS 发布）
+	KeySecret    string `mapstructure:"key_secret"`    // 加密数据库中签名私钥的密钥（base64 编码的 32 字节，config keygen 生成），RS256 / EdDSA 时必填
+	RSABits      int    `mapstructure:"rsa_bits"`      // RS256 密钥长度
+	RotationDays int    `mapstructure:"rotation_days"` // 签名密钥轮换周期（天），0 表示不自动轮换
+	GraceHours   int    `mapstructure:"grace_hours"`   // 被替换的密钥继续用于验证的时间（小时），不能小于 expire
+	PublishAhead int    `mapstructure:"publish_ahead"` // 新密钥提前发布到 JWKS 的时间（分钟），便于其他服务刷新缓存
 }
 
-// GlobalConfig 全局配置实例
-var GlobalConfig *Config
+// StorageConfig 文件存储配置
+type StorageConfig struct {
+	UploadDir     string `mapstructure:"upload_dir"`      // 上传文件目录，用户媒体位于 users/<用户ID>/ 下
+	ExportDir     string `mapstructure:"export_dir"`      // 导出归档目录
+	MaxImportSize int    `mapstructure:"max_import_size"` // 导入文件大小上限（MB）
+}
 
-// Init 初始化配置
-func Init() error {
-	// 设置配置文件路径
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath(".")
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	CheckTimeout int `mapstructure:"check_timeout"` // 单项检查超时（秒）
+	WorkerGrace  int `mapstructure:"worker_grace"`  // 后台任务心跳允许的额外延迟（秒）
+}
 
-	// 设置环境变量前缀
-	viper.SetEnvPrefix("BLOG")
-	viper.AutomaticEnv()
+// ExportConfig 导出配置
+type ExportConfig struct {
+	SyncMaxPosts   int `mapstructure:"sync_max_posts"`  // 文章数不超过该值时直接下载，否则异步生成
+	RetentionHours int `mapstructure:"retention_hours"` // 异步生成的归档保留时间（小时）
+}
 
-	// 设置默认值
-	setDefaults()
-
-	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			log.Println("配置文件未找到，使用默认配置和环境变量")
-		} else {
-			return fmt.Errorf("读取配置文件失败: %v", err)
-		}
-	} else {
-		log.Printf("加载配置文件: %s", viper.ConfigFileUsed())
-	}
+// StaticConfig 静态站点生成配置
+type StaticConfig struct {
+	OutputDir   string `mapstructure:"output_dir"`  // 输出目录
+	BaseURL     string `mapstructure:"base_url"`    // 站点根地址，用于 feed 和 sitemap 中的绝对链接
+	Title       string `mapstructure:"title"`       // 站点标题
+	Description string `mapstructure:"description"` // 站点描述
+	Theme       string `mapstructure:"theme"`       // 主题目录，为空时使用内置主题
+	PageSize    int    `mapstructure:"page_size"`   // 列表页每页文章数
+	FeedSize    int    `mapstructure:"feed_size"`   // feed 中的文章数
+}
+
+// GraphQLConfig GraphQL 接口配置
+type GraphQLConfig struct {
+	MaxDepth        int `mapstructure:"max_depth"`         // 查询最大嵌套深度
+	MaxComplexity   int `mapstructure:"max_complexity"`    // 查询最大复杂度，连接字段按 first 成倍计算
+	DefaultPageSize int `mapstructure:"default_page_size"` // 连接字段未指定 first 时返回的条数
+	MaxPageSize     int `mapstructure:"max_page_size"`     // first 参数上限
+}
+
+// GRPCConfig gRPC 服务配置
+type GRPCConfig struct {
+	Enabled    bool `mapstructure:"enabled"`    // 是否启动 gRPC 服务
+	Port       int  `mapstructure:"port"`       // 监听端口，需与 HTTP 端口不同
+	Reflection bool `mapstructure:"reflection"` // 是否开启服务反射（便于 grpcurl 等工具调试）
+}
+
+// WebhookConfig Webhook 投递配置
+type WebhookConfig struct {
+	Timeout       int  `mapstructure:"timeout"`        // 单次投递超时（秒）
+	MaxAttempts   int  `mapstructure:"max_attempts"`   // 每个事件最多投递次数（含首次）
+	RetryBase     int  `mapstructure:"retry_base"`     // 首次重试间隔（秒），之后每次翻倍
+	RetryMax      int  `mapstructure:"retry_max"`      // 重试间隔上限（秒）
+	DisableAfter  int  `mapstructure:"disable_after"`  // 连续失败多少次后自动停用端点
+	MaxPerUser    int  `mapstructure:"max_per_user"`   // 每个用户最多注册的端点数
+	RetentionDays int  `mapstructure:"retention_days"` // 投递记录保留天数
+	AllowPrivate  bool `mapstructure:"allow_private"`  // 允许投递到内网和本机地址（仅用于开发测试）
+}
+
+// SIWEConfig 以太坊钱包登录（Sign-In with Ethereum, EIP-4361）配置
+type SIWEConfig struct {
+	Enabled  bool    `mapstructure:"enabled"`   // 是否开启钱包登录
+	Domain   string  `mapstructure:"domain"`    // 前端站点域名（可带端口），签名消息中的 domain 必须与之一致
+	ChainIDs []int64 `mapstructure:"chain_ids"` // 允许的链 ID，为空时不限制
+	NonceTTL int     `mapstructure:"nonce_ttl"` // nonce 有效期（秒）
+}
+
+// TwoFactorConfig 两步验证（TOTP）配置
+type TwoFactorConfig struct {
+	Issuer         string   `mapstructure:"issuer"`          // 验证器应用中显示的发行方名称
+	RequiredRoles  []string `mapstructure:"required_roles"`  // 必须开启两步验证才能使用其权限的角色，如 ["admin"]
+	ChallengeTTL   int      `mapstructure:"challenge_ttl"`   // 登录时输入验证码的时限（秒）
+	MaxAttempts    int      `mapstructure:"max_attempts"`    // 连续输错多少次后锁定
+	LockoutMinutes int      `mapstructure:"lockout_minutes"` // 锁定时长（分钟）
+	RecoveryCodes  int      `mapstructure:"recovery_codes"`  // 每次生成的恢复码数量
+}
 
-	// 解析配置到结构体
-	if err := viper.Unmarshal(&GlobalConfig); err != nil {
-		return fmt.Errorf("解析配置失败: %v", err)
+// LoginConfig 登录防暴力破解配置，失败次数在 failure_window 内按账号和 IP 分别统计
+type LoginConfig struct {
+	FailureWindow    int `mapstructure:"failure_window"`     // 统计失败次数的时间窗口（分钟）
+	DelayAfter       int `mapstructure:"delay_after"`        // 账号连续失败多少次后开始要求等待
+	DelaySeconds     int `mapstructure:"delay_seconds"`      // 首次等待时间（秒），之后每次失败翻倍
+	MaxFailures      int `mapstructure:"max_failures"`       // 账号连续失败多少次后锁定
+	LockoutMinutes   int `mapstructure:"lockout_minutes"`    // 账号锁定时长（分钟）
+	IPMaxFailures    int `mapstructure:"ip_max_failures"`    // 同一 IP 失败多少次后锁定该 IP
+	IPLockoutMinutes int `mapstructure:"ip_lockout_minutes"` // IP 锁定时长（分钟）
+	RetentionDays    int `mapstructure:"retention_days"`     // 登录记录保留天数
+}
+
+// AccessTokenConfig 个人访问令牌配置
+type AccessTokenConfig struct {
+	MaxPerUser    int `mapstructure:"max_per_user"`    // 每个用户最多持有的有效令牌数
+	MaxExpireDays int `mapstructure:"max_expire_days"` // 有效期上限（天），0 表示允许永不过期
+}
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	RetentionDays int `mapstructure:"retention_days"` // 审计日志保留天数，0 表示永久保留
+}
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	RetentionDays int `mapstructure:"retention_days"` // 删除的文章和评论在回收站中保留的天数，0 表示不自动清除
+}
+
+// CommentConfig 评论配置
+type CommentConfig struct {
+	MaxDepth      int `mapstructure:"max_depth"`      // 评论列表一次最多展开的回复层数，更深的回复通过游标继续加载
+	InlineReplies int `mapstructure:"inline_replies"` // 评论树中每条评论内嵌的回复数，其余回复通过游标继续加载
+	EditWindow    int `mapstructure:"edit_window"`    // 发表后允许修改的时间（分钟），0 表示不限制
+	// 包含这些词（不区分大小写）的评论在发表或修改后进入待审核
+	BlockedWords []string `mapstructure:"blocked_words"`
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Enabled  bool                       `mapstructure:"enabled"`  // 是否开启限流
+	Store    string                     `mapstructure:"store"`    // 计数存储：memory（单实例）或 redis（多实例共享）
+	Redis    RedisConfig                `mapstructure:"redis"`    // store 为 redis 时使用
+	Policies map[string]RateLimitPolicy `mapstructure:"policies"` // 各路由分组的配额：public, auth, user, admin, graphql
+}
+
+// RateLimitPolicy 限流配额，登录后按用户ID计数，否则按 IP 计数
+type RateLimitPolicy struct {
+	Limit  int `mapstructure:"limit"`  // 窗口内允许的请求数，0 表示不限制
+	Window int `mapstructure:"window"` // 窗口长度（秒）
+}
+
+// RedisConfig Redis 连接配置
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	Prefix   string `mapstructure:"prefix"` // key 前缀
+}
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`   // 允许的来源，如 https://example.com；https://*.example.com 匹配任意子域名，* 匹配所有来源
+	AllowedMethods   []string `mapstructure:"allowed_methods"`   // 允许的请求方法
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`   // 允许的请求头
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`   // 允许前端读取的响应头
+	AllowCredentials bool     `mapstructure:"allow_credentials"` // 是否允许携带凭证（cookies），allowed_origins 为 * 时无效
+	MaxAge           int      `mapstructure:"max_age"`           // 预检请求缓存时间（秒）
+}
+
+// SecurityConfig 安全响应头配置
+type SecurityConfig struct {
+	CSP            map[string][]string `mapstructure:"csp"`             // Content-Security-Policy 指令及其来源列表，为空时不发送
+	FrameOptions   string              `mapstructure:"frame_options"`   // X-Frame-Options，为空时不发送
+	ReferrerPolicy string              `mapstructure:"referrer_policy"` // Referrer-Policy，为空时不发送
+	HSTS           HSTSConfig          `mapstructure:"hsts"`
+}
+
+// HSTSConfig Strict-Transport-Security 配置，只应在全站使用 HTTPS 时开启
+type HSTSConfig struct {
+	Enabled           bool `mapstructure:"enabled"`
+	MaxAge            int  `mapstructure:"max_age"`            // 秒
+	IncludeSubdomains bool `mapstructure:"include_subdomains"` // 同时作用于所有子域名
+	Preload           bool `mapstructure:"preload"`            // 允许加入浏览器的 HSTS 预加载列表
+}
+
+// CredentialsConfig 加密凭证文件配置，解密密钥只能通过 BLOG_CREDENTIALS_KEY 或 BLOG_CREDENTIALS_KEY_FILE 提供
+type CredentialsConfig struct {
+	File string `mapstructure:"file"` // 加密凭证文件路径，为空时不使用
+}
+
+// configDirs 配置文件搜索目录
+var configDirs = []string{"./config", "."}
+
+// envAliases 兼容旧版本的环境变量名
+var envAliases = map[string]string{
+	"database.host":     "BLOG_DB_HOST",
+	"database.port":     "BLOG_DB_PORT",
+	"database.user":     "BLOG_DB_USER",
+	"database.password": "BLOG_DB_PASSWORD",
+	"database.dbname":   "BLOG_DB_NAME",
+}
+
+var (
+	// current 当前生效的配置，热加载时整体替换
+	current atomic.Pointer[Config]
+	// profile 当前环境，来自 BLOG_ENV
+	profile string
+	// configFiles 启动时找到的配置文件，按合并顺序排列，热加载时重新读取
+	configFiles []string
+)
+
+// Init 初始化配置。依次合并默认值、config.yaml、config.{BLOG_ENV}.yaml、加密凭证文件和环境变量，
+// 校验不通过时返回所有错误
+func Init() error {
+	profile = os.Getenv("BLOG_ENV")
+	files, err := findConfigFiles(profile)
+	if err != nil {
+		return err
+	}
+	configFiles = files
+	if len(files) == 0 {
+		log.Println("配置文件未找到，使用默认配置和环境变量")
+	}
+	for _, file := range files {
+		log.Printf("加载配置文件: %s", file)
 	}
 
-	// 从环境变量覆盖配置（如果存在）
-	overrideFromEnv()
+	cfg, err := load()
+	if err != nil {
+		return err
+	}
+	current.Store(cfg)
 
 	log.Println("配置初始化完成")
 	return nil
 }
 
-// setDefaults 设置默认配置
-func setDefaults() {
-	// 服务器配置默认值
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("server.mode", "debug")
-	viper.SetDefault("server.read_timeout", 30)
-	viper.SetDefault("server.write_timeout", 30)
-
-	// 数据库配置默认值
-	viper.SetDefault("database.host", "localhost")
-	viper.SetDefault("database.port", 3306)
-	viper.SetDefault("database.user", "root")
-	viper.SetDefault("database.password", "password")
-	viper.SetDefault("database.dbname", "blog_system")
-	viper.SetDefault("database.charset", "utf8mb4")
-	viper.SetDefault("database.max_idle_conns", 10)
-	viper.SetDefault("database.max_open_conns", 100)
+// Profile 当前环境名，未设置 BLOG_ENV 时为空
+func Profile() string {
+	return profile
+}
 
-	// JWT配置默认值
-	viper.SetDefault("jwt.secret", "your-secret-key-change-in-production")
-	viper.SetDefault("jwt.expire", 24) // 24小时
-	viper.SetDefault("jwt.issuer", "blog-system")
+// Files 生效的配置文件，按合并顺序排列
+func Files() []string {
+	return configFiles
 }
 
-// overrideFromEnv 从环境变量覆盖配置
-func overrideFromEnv() {
-	// 服务器配置环境变量
-	if port := os.Getenv("BLOG_SERVER_PORT"); port != "" {
-		if p, err := strconv.Atoi(port); err == nil {
-			GlobalConfig.Server.Port = p
+// findConfigFiles 查找基础配置文件和环境配置文件。指定了环境但找不到对应配置文件时返回错误
+func findConfigFiles(profile string) ([]string, error) {
+	var files []string
+	dir := ""
+	for _, d := range configDirs {
+		if file := filepath.Join(d, "config.yaml"); fileExists(file) {
+			files = append(files, file)
+			dir = d
+			break
 		}
 	}
-	if mode := os.Getenv("BLOG_SERVER_MODE"); mode != "" {
-		GlobalConfig.Server.Mode = mode
+	if profile == "" {
+		return files, nil
 	}
 
-	// 数据库配置环境变量
-	if host := os.Getenv("BLOG_DB_HOST"); host != "" {
-		GlobalConfig.Database.Host = host
+	name := fmt.Sprintf("config.%s.yaml", profile)
+	dirs := configDirs
+	if dir != "" {
+		dirs = []string{dir}
 	}
-	if port := os.Getenv("BLOG_DB_PORT"); port != "" {
-		if p, err := strconv.Atoi(port); err == nil {
-			GlobalConfig.Database.Port = p
+	for _, d := range dirs {
+		if file := filepath.Join(d, name); fileExists(file) {
+			return append(files, file), nil
 		}
 	}
-	if user := os.Getenv("BLOG_DB_USER"); user != "" {
-		GlobalConfig.Database.User = user
+	return nil, fmt.Errorf("BLOG_ENV=%s，但未找到配置文件 %s", profile, name)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// load 读取并合并所有配置来源，解析为结构体并校验
+func load() (*Config, error) {
+	v := newViper()
+	for i, file := range configFiles {
+		v.SetConfigFile(file)
+		read := v.MergeInConfig
+		if i == 0 {
+			read = v.ReadInConfig
+		}
+		if err := read(); err != nil {
+			return nil, fmt.Errorf("读取配置文件 %s 失败: %v", file, err)
+		}
 	}
-	if password := os.Getenv("BLOG_DB_PASSWORD"); password != "" {
-		GlobalConfig.Database.Password = password
+
+	if err := mergeCredentials(v); err != nil {
+		return nil, err
 	}
-	if dbname := os.Getenv("BLOG_DB_NAME"); dbname != "" {
-		GlobalConfig.Database.DBName = dbname
+	if err := applySecretFiles(v); err != nil {
+		return nil, err
 	}
+	return decode(v)
+}
 
-	// JWT配置环境变量
-	if secret := os.Getenv("BLOG_JWT_SECRET"); secret != "" {
-		GlobalConfig.JWT.Secret = secret
+// newViper 创建设置好环境变量和默认值的 viper 实例。
+// 环境变量名为 BLOG_ 加上大写的配置项，点号换成下划线，如 BLOG_RATE_LIMIT_STORE
+func newViper() *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	// 设置环境变量前缀
+	v.SetEnvPrefix("BLOG")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for key, alias := range envAliases {
+		_ = v.BindEnv(key, envName(key), alias)
 	}
-	if expire := os.Getenv("BLOG_JWT_EXPIRE"); expire != "" {
-		if e, err := strconv.Atoi(expire); err == nil {
-			GlobalConfig.JWT.Expire = e
-		}
+
+	// 设置默认值
+	setDefaults(v)
+	return v
+}
+
+// envName 配置项对应的环境变量名
+func envName(key string) string {
+	return "BLOG_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// decode 解析配置到结构体并校验
+func decode(v *viper.Viper) (*Config, error) {
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %v", err)
+	}
+
+	if err := validate(&cfg); err != nil {
+		return nil, fmt.Errorf("配置校验失败:\n%v", err)
 	}
+	return &cfg, nil
+}
+
+// setDefaults 设置默认配置
+func setDefaults(v *viper.Viper) {
+	// 服务器配置默认值
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.mode", "debug")
+	v.SetDefault("server.read_timeout", 30)
+	v.SetDefault("server.write_timeout", 30)
+	v.SetDefault("server.idle_timeout", 60)
+	v.SetDefault("server.read_header_timeout", 10)
+	v.SetDefault("server.shutdown_timeout", 15)
+
+	// 日志配置默认值
+	v.SetDefault("log.level", "info")
+
+	// 数据库配置默认值
+	v.SetDefault("database.driver", "mysql")
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 3306)
+	v.SetDefault("database.user", "root")
+	v.SetDefault("database.password", "password")
+	v.SetDefault("database.dbname", "blog_system")
+	v.SetDefault("database.charset", "utf8mb4")
+	v.SetDefault("database.max_idle_conns", 10)
+	v.SetDefault("database.max_open_conns", 100)
+	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.auto_migrate", false)
+
+	// JWT配置默认值
+	v.SetDefault("jwt.secret", "your-secret-key-change-in-production")
+	v.SetDefault("jwt.expire", 24) // 24小时
+	v.SetDefault("jwt.issuer", "blog-system")
+	v.SetDefault("jwt.algorithm", "HS256")
+	v.SetDefault("jwt.key_secret", "")
+	v.SetDefault("jwt.rsa_bits", 2048)
+	v.SetDefault("jwt.rotation_days", 30)
+	v.SetDefault("jwt.grace_hours", 48)
+	v.SetDefault("jwt.publish_ahead", 60)
+
+	// 存储配置默认值
+	v.SetDefault("storage.upload_dir", "./uploads")
+	v.SetDefault("storage.export_dir", "./exports")
+	v.SetDefault("storage.max_import_size", 50)
+
+	// 导出配置默认值
+	v.SetDefault("export.sync_max_posts", 200)
+	v.SetDefault("export.retention_hours", 24)
+
+	// 静态站点配置默认值
+	v.SetDefault("static.output_dir", "./public")
+	v.SetDefault("static.base_url", "http://localhost:8080")
+	v.SetDefault("static.title", "Blog System")
+	v.SetDefault("static.description", "")
+	v.SetDefault("static.theme", "")
+	v.SetDefault("static.page_size", 10)
+	v.SetDefault("static.feed_size", 20)
+
+	// GraphQL 配置默认值
+	v.SetDefault("graphql.max_depth", 12)
+	v.SetDefault("graphql.max_complexity", 5000)
+	v.SetDefault("graphql.default_page_size", 10)
+	v.SetDefault("graphql.max_page_size", 50)
+
+	// gRPC 配置默认值
+	v.SetDefault("grpc.enabled", true)
+	v.SetDefault("grpc.port", 9090)
+	v.SetDefault("grpc.reflection", false)
+
+	// Webhook 配置默认值
+	v.SetDefault("webhook.timeout", 10)
+	v.SetDefault("webhook.max_attempts", 8)
+	v.SetDefault("webhook.retry_base", 30)
+	v.SetDefault("webhook.retry_max", 3600)
+	v.SetDefault("webhook.disable_after", 20)
+	v.SetDefault("webhook.max_per_user", 10)
+	v.SetDefault("webhook.retention_days", 30)
+	v.SetDefault("webhook.allow_private", false)
+
+	// 钱包登录配置默认值
+	v.SetDefault("siwe.enabled", true)
+	v.SetDefault("siwe.domain", "localhost:8080")
+	v.SetDefault("siwe.chain_ids", []int64{})
+	v.SetDefault("siwe.nonce_ttl", 300)
+
+	// 两步验证配置默认值
+	v.SetDefault("two_factor.issuer", "Blog System")
+	v.SetDefault("two_factor.required_roles", []string{})
+	v.SetDefault("two_factor.challenge_ttl", 300)
+	v.SetDefault("two_factor.max_attempts", 5)
+	v.SetDefault("two_factor.lockout_minutes", 15)
+	v.SetDefault("two_factor.recovery_codes", 10)
+
+	// 登录防暴力破解配置默认值
+	v.SetDefault("login.failure_window", 30)
+	v.SetDefault("login.delay_after", 3)
+	v.SetDefault("login.delay_seconds", 2)
+	v.SetDefault("login.max_failures", 5)
+	v.SetDefault("login.lockout_minutes", 15)
+	v.SetDefault("login.ip_max_failures", 20)
+	v.SetDefault("login.ip_lockout_minutes", 15)
+	v.SetDefault("login.retention_days", 90)
+
+	// 个人访问令牌配置默认值
+	v.SetDefault("access_token.max_per_user", 20)
+	v.SetDefault("access_token.max_expire_days", 0)
+
+	// 审计日志配置默认值
+	v.SetDefault("audit.retention_days", 365)
+
+	// 回收站配置默认值
+	v.SetDefault("trash.retention_days", 30)
+
+	// 评论配置默认值
+	v.SetDefault("comment.max_depth", 5)
+	v.SetDefault("comment.inline_replies", 3)
+	v.SetDefault("comment.edit_window", 30)
+	v.SetDefault("comment.blocked_words", []string{})
+
+	// 限流配置默认值
+	v.SetDefault("rate_limit.enabled", true)
+	v.SetDefault("rate_limit.store", "memory")
+	v.SetDefault("rate_limit.redis.addr", "localhost:6379")
+	v.SetDefault("rate_limit.redis.password", "")
+	v.SetDefault("rate_limit.redis.db", 0)
+	v.SetDefault("rate_limit.redis.prefix", "blog:ratelimit:")
+	v.SetDefault("rate_limit.policies.public.limit", 120)
+	v.SetDefault("rate_limit.policies.public.window", 60)
+	v.SetDefault("rate_limit.policies.auth.limit", 20)
+	v.SetDefault("rate_limit.policies.auth.window", 60)
+	v.SetDefault("rate_limit.policies.user.limit", 300)
+	v.SetDefault("rate_limit.policies.user.window", 60)
+	v.SetDefault("rate_limit.policies.admin.limit", 600)
+	v.SetDefault("rate_limit.policies.admin.window", 60)
+	v.SetDefault("rate_limit.policies.graphql.limit", 120)
+	v.SetDefault("rate_limit.policies.graphql.window", 60)
+
+	// 跨域配置默认值（默认不允许跨域请求）
+	v.SetDefault("cors.allowed_origins", []string{})
+	v.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE"})
+	v.SetDefault("cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"})
+	v.SetDefault("cors.exposed_headers", []string{"Content-Length", "Content-Disposition", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "X-Request-ID"})
+	v.SetDefault("cors.allow_credentials", false)
+	v.SetDefault("cors.max_age", 600)
+
+	// 安全响应头配置默认值
+	v.SetDefault("security.csp", map[string][]string{
+		"default-src":     {"'self'"},
+		"frame-ancestors": {"'none'"},
+	})
+	v.SetDefault("security.frame_options", "DENY")
+	v.SetDefault("security.referrer_policy", "strict-origin-when-cross-origin")
+	v.SetDefault("security.hsts.enabled", false)
+	v.SetDefault("security.hsts.max_age", 31536000)
+	v.SetDefault("security.hsts.include_subdomains", true)
+	v.SetDefault("security.hsts.preload", false)
+
+	// 健康检查配置默认值
+	v.SetDefault("health.check_timeout", 2)
+	v.SetDefault("health.worker_grace", 30)
+
+	// 加密凭证文件默认值
+	v.SetDefault("credentials.file", "")
 }
 
 // GetDSN 获取数据库连接字符串
 func (d *DatabaseConfig) GetDSN() string {
+	if d.Driver == "postgres" {
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=Local",
+			d.Host, d.Port, d.User, d.Password, d.DBName, d.SSLMode)
+	}
 	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
 		d.User, d.Password, d.Host, d.Port, d.DBName, d.Charset)
 }
 
 // GetConfig 获取全局配置实例
 func GetConfig() *Config {
-	return GlobalConfig
-}
\ No newline at end of file
+	return current.Load()
+}
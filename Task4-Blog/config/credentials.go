@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// credentialsHeader 加密凭证文件的格式标识
+const credentialsHeader = "blog-credentials:v1:"
+
+// CredentialsKeyEnv 加密凭证文件的密钥环境变量，加上 _FILE 后缀时从文件读取
+const CredentialsKeyEnv = "BLOG_CREDENTIALS_KEY"
+
+// GenerateCredentialsKey 生成加密凭证文件使用的密钥（base64 编码的 32 字节 AES-256 密钥）
+func GenerateCredentialsKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptCredentials 使用 AES-256-GCM 加密凭证，plaintext 为与 config.yaml 结构相同的 YAML
+func EncryptCredentials(plaintext []byte, key string) ([]byte, error) {
+	var probe map[string]interface{}
+	if err := yaml.Unmarshal(plaintext, &probe); err != nil {
+		return nil, fmt.Errorf("凭证内容不是合法的 YAML: %v", err)
+	}
+
+	sealed, err := Seal(plaintext, []byte(credentialsHeader), key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(credentialsHeader + base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// DecryptCredentials 解密 EncryptCredentials 生成的凭证文件
+func DecryptCredentials(data []byte, key string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(string(data)), credentialsHeader)
+	if !ok {
+		return nil, errors.New("不是加密凭证文件")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("加密凭证文件已损坏: %v", err)
+	}
+
+	plaintext, err := Open(sealed, []byte(credentialsHeader), key)
+	if err != nil {
+		return nil, fmt.Errorf("%v或文件已被修改", err)
+	}
+	return plaintext, nil
+}
+
+// Seal 使用 AES-256-GCM 加密数据，key 的格式与加密凭证文件的密钥相同。
+// 返回 nonce 和密文，aad 不加密但参与认证，解密时必须相同
+func Seal(plaintext, aad []byte, key string) ([]byte, error) {
+	gcm, err := credentialsCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// Open 解密 Seal 加密的数据
+func Open(sealed, aad []byte, key string) ([]byte, error) {
+	gcm, err := credentialsCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("密文已损坏")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], aad)
+	if err != nil {
+		return nil, errors.New("解密失败，密钥错误")
+	}
+	return plaintext, nil
+}
+
+// ValidKey 检查密钥是否为 base64 编码的 32 字节数据
+func ValidKey(key string) bool {
+	_, err := credentialsCipher(key)
+	return err == nil
+}
+
+// CredentialsKey 从环境变量或密钥文件读取加密凭证文件的密钥
+func CredentialsKey() (string, error) {
+	key, err := envOrFile(CredentialsKeyEnv)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", fmt.Errorf("未设置 %s 或 %s_FILE", CredentialsKeyEnv, CredentialsKeyEnv)
+	}
+	return key, nil
+}
+
+func credentialsCipher(key string) (cipher.AEAD, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("密钥必须是 base64 编码的 32 字节数据，可使用 config keygen 生成")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// mergeCredentials 解密 credentials.file 并合并到配置中，优先级高于配置文件、低于环境变量
+func mergeCredentials(v *viper.Viper) error {
+	path := v.GetString("credentials.file")
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取加密凭证文件失败: %v", err)
+	}
+	key, err := CredentialsKey()
+	if err != nil {
+		return fmt.Errorf("无法解密 %s: %v", path, err)
+	}
+	plaintext, err := DecryptCredentials(data, key)
+	if err != nil {
+		return fmt.Errorf("无法解密 %s: %v", path, err)
+	}
+	if err := v.MergeConfig(bytes.NewReader(plaintext)); err != nil {
+		return fmt.Errorf("解析加密凭证文件失败: %v", err)
+	}
+	return nil
+}
+
+// applySecretFiles 从 *_FILE 环境变量指向的文件读取敏感配置，如 BLOG_JWT_SECRET_FILE=/run/secrets/jwt，
+// 便于配合 Docker / Kubernetes secrets 使用，优先级最高
+func applySecretFiles(v *viper.Viper) error {
+	for _, key := range v.AllKeys() {
+		parts := strings.Split(key, ".")
+		if !IsSecretKey(parts[len(parts)-1]) {
+			continue
+		}
+		names := []string{envName(key)}
+		if alias, ok := envAliases[key]; ok {
+			names = append(names, alias)
+		}
+		for _, name := range names {
+			value, err := readSecretFile(name + "_FILE")
+			if err != nil {
+				return err
+			}
+			if value != "" {
+				v.Set(key, value)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// envOrFile 读取环境变量 name，未设置时读取 name_FILE 指向的文件
+func envOrFile(name string) (string, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+	return readSecretFile(name + "_FILE")
+}
+
+// readSecretFile 读取环境变量 name 指向的文件内容，去掉末尾换行，未设置时返回空字符串
+func readSecretFile(name string) (string, error) {
+	path := os.Getenv(name)
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 指向的文件失败: %v", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
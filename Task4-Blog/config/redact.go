@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// redactedValue 脱敏后显示的值
+const redactedValue = "******"
+
+// secretKeyParts 字段名包含这些片段时视为敏感信息
+var secretKeyParts = []string{"password", "secret", "token", "private_key", "dsn"}
+
+// IsSecretKey 判断配置项是否为敏感信息
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted 将配置转换为以 mapstructure 标签为键的 map，并对敏感信息脱敏
+func Redacted(cfg *Config) map[string]interface{} {
+	return redactStruct(reflect.ValueOf(cfg).Elem())
+}
+
+// redactStruct 递归转换结构体
+func redactStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{})
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if key == "" || key == "-" {
+			key = strings.ToLower(field.Name)
+		}
+		out[key] = redactValue(key, v.Field(i))
+	}
+	return out
+}
+
+// redactValue 转换单个字段值
+func redactValue(key string, v reflect.Value) interface{} {
+	if IsSecretKey(key) {
+		if v.IsZero() {
+			return ""
+		}
+		return redactedValue
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return redactStruct(v)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(key, v.Elem())
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			out[k] = redactValue(k, iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redactValue(key, v.Index(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// ChangeListener 配置变更监听函数，old 为变更前的配置，cfg 为新配置，两者都不应被修改
+type ChangeListener func(old, cfg *Config)
+
+var (
+	// reloadMu 保证同一时间只有一次热加载
+	reloadMu  sync.Mutex
+	listeners []ChangeListener
+)
+
+// reloadDebounce 配置文件变化后等待的时间，编辑器保存时往往连续触发多次写事件
+const reloadDebounce = 200 * time.Millisecond
+
+// OnChange 注册配置变更监听，热加载成功并且配置确有变化后按注册顺序调用
+func OnChange(fn ChangeListener) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// staticField 运行期间不能修改的配置项，value 为指向该配置项的指针
+type staticField struct {
+	key   string
+	value interface{}
+}
+
+// staticFields 运行期间不能修改的配置项，热加载时保留原值并输出警告
+func staticFields(cfg *Config) []staticField {
+	return []staticField{
+		{"server", &cfg.Server},
+		{"database", &cfg.Database},
+		{"grpc", &cfg.GRPC},
+		{"health", &cfg.Health},
+		{"rate_limit.store", &cfg.RateLimit.Store},
+		{"rate_limit.redis", &cfg.RateLimit.Redis},
+	}
+}
+
+// Watch 监听配置文件（包括环境配置文件）变化并自动热加载，未使用配置文件时不做任何事
+func Watch() {
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+	onChange := func(fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDebounce, func() {
+			if err := Reload(); err != nil {
+				log.Printf("配置热加载失败，继续使用原配置: %v", err)
+			}
+		})
+	}
+
+	for _, file := range configFiles {
+		w := viper.New()
+		w.SetConfigFile(file)
+		w.OnConfigChange(onChange)
+		w.WatchConfig()
+		log.Printf("监听配置文件变化: %s", file)
+	}
+}
+
+// Reload 重新读取配置文件，校验通过后整体替换当前配置并通知监听者。
+// 不能在运行期间修改的配置项保留原值
+func Reload() error {
+	if len(configFiles) == 0 {
+		return fmt.Errorf("未使用配置文件")
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	// 编辑器先清空再写入时会读到空文件，此时不应回退为默认配置
+	for _, file := range configFiles {
+		if info, err := os.Stat(file); err != nil {
+			return fmt.Errorf("读取配置文件失败: %v", err)
+		} else if info.Size() == 0 {
+			return fmt.Errorf("配置文件 %s 为空", file)
+		}
+	}
+
+	cfg, err := load()
+	if err != nil {
+		return err
+	}
+
+	old := current.Load()
+	oldFields := staticFields(old)
+	for i, field := range staticFields(cfg) {
+		prev := reflect.ValueOf(oldFields[i].value).Elem()
+		next := reflect.ValueOf(field.value).Elem()
+		if !reflect.DeepEqual(prev.Interface(), next.Interface()) {
+			log.Printf("警告: %s 不支持热加载，重启后生效", field.key)
+			next.Set(prev)
+		}
+	}
+
+	if reflect.DeepEqual(old, cfg) {
+		return nil
+	}
+	current.Store(cfg)
+	log.Println("配置已重新加载")
+
+	for _, fn := range listeners {
+		fn(old, cfg)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// insecureSecrets 默认值和示例配置中出现过的敏感信息，release 模式下禁止使用
+var insecureSecrets = map[string][]string{
+	"jwt.secret": {
+		"your-secret-key-change-in-production",
+		"blog-system-secret-key-change-in-production",
+		"your-super-secret-jwt-key-change-in-production",
+	},
+	"database.password": {"password", "st123456", "your_mysql_password"},
+}
+
+// minJWTSecretLength release 模式下 jwt.secret 的最小长度
+const minJWTSecretLength = 32
+
+// validate 校验配置取值，启动和热加载时都会执行，校验失败的配置不会生效。
+// 返回的错误包含所有不合法的配置项，每项一行
+func validate(cfg *Config) error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(cfg.Server.Port), "server.port 必须在 1-65535 之间")
+	check(oneOf(cfg.Server.Mode, "debug", "release", "test"), "server.mode 必须是 debug、release、test 之一")
+	check(cfg.Server.ReadTimeout >= 0 && cfg.Server.WriteTimeout >= 0 && cfg.Server.IdleTimeout >= 0 &&
+		cfg.Server.ReadHeaderTimeout >= 0 && cfg.Server.ShutdownTimeout >= 0, "server 中的超时时间不能为负数")
+	check(oneOf(cfg.Log.Level, "debug", "info", "warn", "error"), "log.level 必须是 debug、info、warn、error 之一")
+
+	check(oneOf(cfg.Database.Driver, "mysql", "postgres"), "database.driver 必须是 mysql 或 postgres")
+	check(cfg.Database.Host != "", "database.host 不能为空")
+	check(validPort(cfg.Database.Port), "database.port 必须在 1-65535 之间")
+	check(cfg.Database.DBName != "", "database.dbname 不能为空")
+	check(cfg.Database.MaxOpenConns >= 0 && cfg.Database.MaxIdleConns >= 0, "database 连接池大小不能为负数")
+
+	check(oneOf(cfg.JWT.Algorithm, "HS256", "RS256", "EdDSA"), "jwt.algorithm 必须是 HS256、RS256、EdDSA 之一")
+	check(cfg.JWT.Algorithm != "HS256" || cfg.JWT.Secret != "", "jwt.secret 不能为空")
+	check(cfg.JWT.Expire > 0, "jwt.expire 必须大于 0")
+	if cfg.JWT.Algorithm != "HS256" {
+		check(ValidKey(cfg.JWT.KeySecret), "jwt.key_secret 必须是 base64 编码的 32 字节数据（可使用 config keygen 生成），用于加密数据库中的签名私钥")
+		check(cfg.JWT.Algorithm != "RS256" || cfg.JWT.RSABits >= 2048, "jwt.rsa_bits 不能小于 2048")
+		check(cfg.JWT.RotationDays >= 0, "jwt.rotation_days 不能为负数")
+		check(cfg.JWT.GraceHours >= cfg.JWT.Expire, "jwt.grace_hours 不能小于 jwt.expire，否则轮换后已签发的 token 会提前失效")
+		check(cfg.JWT.PublishAhead >= 0, "jwt.publish_ahead 不能为负数")
+	}
+
+	check(cfg.Storage.UploadDir != "", "storage.upload_dir 不能为空")
+	check(cfg.Storage.ExportDir != "", "storage.export_dir 不能为空")
+
+	if cfg.GRPC.Enabled {
+		check(validPort(cfg.GRPC.Port), "grpc.port 必须在 1-65535 之间")
+		check(cfg.GRPC.Port != cfg.Server.Port, "grpc.port 不能与 server.port 相同")
+	}
+	check(cfg.GraphQL.MaxDepth > 0 && cfg.GraphQL.MaxComplexity > 0, "graphql.max_depth 和 graphql.max_complexity 必须大于 0")
+	check(cfg.GraphQL.DefaultPageSize > 0 && cfg.GraphQL.DefaultPageSize <= cfg.GraphQL.MaxPageSize,
+		"graphql.default_page_size 必须大于 0 且不超过 graphql.max_page_size")
+
+	check(cfg.Webhook.Timeout > 0, "webhook.timeout 必须大于 0")
+	check(cfg.Webhook.MaxAttempts > 0, "webhook.max_attempts 必须大于 0")
+	check(cfg.TwoFactor.ChallengeTTL > 0, "two_factor.challenge_ttl 必须大于 0")
+	check(cfg.Login.FailureWindow > 0, "login.failure_window 必须大于 0")
+	check(cfg.AccessToken.MaxPerUser > 0, "access_token.max_per_user 必须大于 0")
+	check(cfg.AccessToken.MaxExpireDays >= 0, "access_token.max_expire_days 不能为负数")
+	check(cfg.Audit.RetentionDays >= 0, "audit.retention_days 不能为负数")
+	check(cfg.Trash.RetentionDays >= 0, "trash.retention_days 不能为负数")
+	check(cfg.Comment.MaxDepth >= 0, "comment.max_depth 不能为负数")
+	check(cfg.Comment.InlineReplies > 0, "comment.inline_replies 必须大于 0")
+	check(cfg.Comment.EditWindow >= 0, "comment.edit_window 不能为负数")
+
+	check(oneOf(cfg.RateLimit.Store, "memory", "redis"), "rate_limit.store 必须是 memory 或 redis")
+	for name, p := range cfg.RateLimit.Policies {
+		check(p.Limit >= 0, "rate_limit.policies.%s.limit 不能为负数", name)
+		check(p.Limit == 0 || p.Window > 0, "rate_limit.policies.%s.window 必须大于 0", name)
+	}
+
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		check(validOrigin(origin), "cors.allowed_origins 中的 %q 不是合法的来源", origin)
+	}
+	check(cfg.CORS.MaxAge >= 0, "cors.max_age 不能为负数")
+	check(cfg.Security.HSTS.MaxAge >= 0, "security.hsts.max_age 不能为负数")
+
+	// release 模式下拒绝使用默认或示例中的敏感信息
+	if cfg.Server.Mode == "release" {
+		values := map[string]string{"database.password": cfg.Database.Password}
+		// 非对称签名不使用 jwt.secret
+		if cfg.JWT.Algorithm == "HS256" {
+			values["jwt.secret"] = cfg.JWT.Secret
+			check(len(cfg.JWT.Secret) >= minJWTSecretLength, "release 模式下 jwt.secret 长度不能少于 %d 个字符", minJWTSecretLength)
+		}
+		for _, key := range []string{"jwt.secret", "database.password"} {
+			value, ok := values[key]
+			if !ok {
+				continue
+			}
+			for _, insecure := range insecureSecrets[key] {
+				check(value != insecure, "release 模式下 %s 不能使用默认值或示例值，请通过 %s、%s_FILE 或加密凭证文件设置", key, envName(key), envName(key))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// validOrigin 检查来源格式：*、scheme://host[:port] 或 scheme://*.domain[:port]
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || scheme == "" || host == "" || strings.ContainsAny(host, "/?#@") {
+		return false
+	}
+	if strings.Contains(host, "*") {
+		return strings.HasPrefix(host, "*.") && len(host) > 2 && !strings.Contains(host[2:], "*")
+	}
+	return true
+}
+
+// oneOf 判断 value 是否为 options 之一
+func oneOf(value string, options ...string) bool {
+	for _, option := range options {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"blog-system/models"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController 审计日志控制器（管理员功能）
+type AuditController struct {
+	auditService *services.AuditService
+}
+
+// NewAuditController 创建审计日志控制器实例
+func NewAuditController(auditService *services.AuditService) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogs 查询审计日志，可按操作者、操作、对象和时间范围筛选
+func (ac *AuditController) GetAuditLogs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filter := services.AuditLogFilter{
+		Action:     models.AuditAction(c.Query("action")),
+		TargetType: c.Query("target_type"),
+	}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := strconv.ParseUint(actorID, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "操作者ID格式不正确")
+			return
+		}
+		filter.ActorID = uint(id)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		id, err := strconv.ParseUint(targetID, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "对象ID格式不正确")
+			return
+		}
+		filter.TargetID = uint(id)
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "from 必须是 RFC 3339 格式的时间")
+			return
+		}
+		filter.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "to 必须是 RFC 3339 格式的时间")
+			return
+		}
+		filter.To = &t
+	}
+
+	logs, total, err := ac.auditService.ListLogs(filter, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取审计日志失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取审计日志成功", gin.H{
+		"logs": logs,
+		"pagination": gin.H{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
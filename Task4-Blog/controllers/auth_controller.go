@@ -1,14 +1,20 @@
 package controllers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
+	"blog-system/config"
 	"blog-system/models"
 	"blog-system/services"
+	"blog-system/siwe"
 	"blog-system/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AuthController 认证控制器
@@ -35,16 +41,43 @@ type RegisterRequest struct {
 
 // LoginRequest 登录请求结构
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string `json:"username" binding:"required,max=100"` // 用户名或邮箱
+	Password string `json:"password" binding:"required,max=128"`
+}
+
+// SIWERequest 钱包登录请求结构
+type SIWERequest struct {
+	Message   string `json:"message" binding:"required,max=4096"` // 钱包签名的 EIP-4361 消息原文
+	Signature string `json:"signature" binding:"required"`        // personal_sign 签名（0x 开头的十六进制）
+}
+
+// SIWENonceResponse 钱包登录 nonce 响应结构，客户端用这些值构造 EIP-4361 消息
+type SIWENonceResponse struct {
+	Nonce     string    `json:"nonce"`
+	Domain    string    `json:"domain"`
+	ChainIDs  []int64   `json:"chain_ids,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UpdateProfileRequest 更新个人资料请求结构
+type UpdateProfileRequest struct {
+	Bio    string `json:"bio,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
 }
 
 // AuthResponse 认证响应结构
 type AuthResponse struct {
-	Token string             `json:"token"`
+	Token string              `json:"token"`
 	User  models.UserResponse `json:"user"`
 }
 
+// TwoFactorChallengeResponse 需要两步验证时的登录响应，客户端用 challenge_token 调用 /auth/2fa/verify 完成登录
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
 // Register 用户注册
 func (ac *AuthController) Register(c *gin.Context) {
 	var req RegisterRequest
@@ -73,7 +106,7 @@ func (ac *AuthController) Register(c *gin.Context) {
 	}
 
 	// 生成 JWT token
-	token, err := ac.authService.GenerateToken(user)
+	token, err := ac.authService.GenerateToken(user, false)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "生成token失败", err.Error())
 		return
@@ -94,18 +127,32 @@ func (ac *AuthController) Login(c *gin.Context) {
 	}
 
 	// 验证用户凭证
-	user, err := ac.authService.Login(req.Username, req.Password)
+	user, err := ac.authService.Login(req.Username, req.Password, c.ClientIP())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", "用户名或密码错误")
+		var blocked *services.LoginBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "登录失败", blocked.Error())
+		case errors.Is(err, services.ErrInvalidCredentials):
+			utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "登录失败", err.Error())
+		}
 		return
 	}
 
-	// 更新最后登录时间
-	now := time.Now()
-	ac.userService.UpdateLastLogin(user.ID, &now)
+	// 开启了两步验证的用户先返回挑战令牌
+	if user.TwoFactorEnabled {
+		ac.respondChallenge(c, user)
+		return
+	}
+
+	// 更新最后登录时间并记入审计日志
+	ac.userService.RecordLogin(c.Request.Context(), user, "password")
 
 	// 生成 JWT token
-	token, err := ac.authService.GenerateToken(user)
+	token, err := ac.authService.GenerateToken(user, false)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "生成token失败", err.Error())
 		return
@@ -143,11 +190,7 @@ func (ac *AuthController) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	var updateData struct {
-		Bio    string `json:"bio,omitempty"`
-		Avatar string `json:"avatar,omitempty"`
-	}
-
+	var updateData UpdateProfileRequest
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
 		return
@@ -160,4 +203,115 @@ func (ac *AuthController) UpdateProfile(c *gin.Context) {
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "更新用户信息成功", user.ToResponse())
-}
\ No newline at end of file
+}
+
+// GetJWKS 发布验证 token 使用的公钥（JWKS），其他服务据此验证本服务签发的 token，
+// 响应为标准 JWKS 格式，不使用统一响应结构
+func (ac *AuthController) GetJWKS(c *gin.Context) {
+	jwks, err := ac.authService.JWKS()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取公钥失败", err.Error())
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwks)
+}
+
+// GetSIWENonce 获取钱包登录使用的一次性 nonce
+func (ac *AuthController) GetSIWENonce(c *gin.Context) {
+	nonce, err := ac.authService.CreateNonce()
+	if err != nil {
+		if errors.Is(err, services.ErrSIWEDisabled) {
+			utils.ErrorResponse(c, http.StatusNotFound, "钱包登录未开启", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成nonce失败", err.Error())
+		return
+	}
+
+	cfg := config.GetConfig().SIWE
+	utils.SuccessResponse(c, http.StatusOK, "获取nonce成功", SIWENonceResponse{
+		Nonce:     nonce.Nonce,
+		Domain:    cfg.Domain,
+		ChainIDs:  cfg.ChainIDs,
+		ExpiresAt: nonce.ExpiresAt,
+	})
+}
+
+// SignInWithEthereum 以太坊钱包登录（EIP-4361）。
+// 钱包首次登录时创建新用户；携带 token 调用时将钱包绑定到当前用户
+func (ac *AuthController) SignInWithEthereum(c *gin.Context) {
+	var req SIWERequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	// 认证信息来自 OptionalAuth 中间件
+	var currentUserID uint
+	if userID, exists := c.Get("userID"); exists {
+		currentUserID = userID.(uint)
+	}
+
+	user, created, err := ac.authService.SignInWithEthereum(req.Message, req.Signature, currentUserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSIWEDisabled):
+			utils.ErrorResponse(c, http.StatusNotFound, "钱包登录未开启", err.Error())
+		case errors.Is(err, siwe.ErrInvalidMessage):
+			utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		case errors.Is(err, services.ErrWalletLinked), errors.Is(err, services.ErrAccountHasWallet):
+			utils.ErrorResponse(c, http.StatusConflict, "绑定钱包失败", err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", "账号不存在或已停用")
+		case errors.Is(err, services.ErrSIWENonce), errors.Is(err, siwe.ErrDomainMismatch), errors.Is(err, siwe.ErrChainNotAllowed),
+			errors.Is(err, siwe.ErrExpired), errors.Is(err, siwe.ErrNotYetValid),
+			errors.Is(err, siwe.ErrInvalidSignature), errors.Is(err, siwe.ErrAddressMismatch):
+			utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "登录失败", err.Error())
+		}
+		return
+	}
+
+	// 开启了两步验证的用户先返回挑战令牌，绑定钱包时也一样：
+	// 调用方持有的 token 可能是开启两步验证之前签发的，不能据此签发通过两步验证的 token
+	if user.TwoFactorEnabled {
+		ac.respondChallenge(c, user)
+		return
+	}
+
+	// 更新最后登录时间并记入审计日志
+	ac.userService.RecordLogin(c.Request.Context(), user, "siwe")
+
+	// 生成 JWT token
+	token, err := ac.authService.GenerateToken(user, false)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成token失败", err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	utils.SuccessResponse(c, status, "登录成功", AuthResponse{
+		Token: token,
+		User:  user.ToResponse(),
+	})
+}
+
+// respondChallenge 返回两步验证挑战令牌
+func (ac *AuthController) respondChallenge(c *gin.Context, user *models.User) {
+	challenge, expiresAt, err := ac.authService.GenerateChallengeToken(user)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成token失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "请输入两步验证码", TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+		ExpiresAt:         expiresAt,
+	})
+}
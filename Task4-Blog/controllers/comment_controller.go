@@ -1,13 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"blog-system/models"
 	"blog-system/services"
 	"blog-system/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // CommentController 评论控制器
@@ -29,6 +32,16 @@ type CreateCommentRequest struct {
 	ParentID *uint  `json:"parent_id,omitempty"`
 }
 
+// UpdateCommentRequest 修改评论请求结构
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=1000"`
+}
+
+// ModerateCommentRequest 审核评论请求结构
+type ModerateCommentRequest struct {
+	IsApproved *bool `json:"is_approved" binding:"required"`
+}
+
 // CreateComment 创建评论
 func (cc *CommentController) CreateComment(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -43,16 +56,23 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 		return
 	}
 
-	comment, err := cc.commentService.CreateComment(userID.(uint), req.PostID, req.Content, req.ParentID)
+	comment, err := cc.commentService.CreateComment(c.Request.Context(), userID.(uint), req.PostID, req.Content, req.ParentID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "创建评论失败", err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "文章不存在", err.Error())
+		case errors.Is(err, services.ErrCommentParentNotFound), errors.Is(err, services.ErrCommentParentMismatch):
+			utils.ErrorResponse(c, http.StatusBadRequest, "创建评论失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "创建评论失败", err.Error())
+		}
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusCreated, "创建评论成功", comment.ToResponse())
 }
 
-// GetPostComments 获取文章评论列表
+// GetPostComments 获取文章评论树，sort/reply_sort 分别指定顶级评论和回复的排序，depth 指定展开的回复层数
 func (cc *CommentController) GetPostComments(c *gin.Context) {
 	postIDStr := c.Param("postId")
 	postID, err := strconv.ParseUint(postIDStr, 10, 32)
@@ -64,9 +84,29 @@ func (cc *CommentController) GetPostComments(c *gin.Context) {
 	// 获取分页参数
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	opts := services.CommentThreadOptions{
+		Sort:      models.CommentSort(c.Query("sort")),
+		ReplySort: models.CommentSort(c.Query("reply_sort")),
+	}
+	depth, ok := commentDepthParam(c)
+	if !ok {
+		return
+	}
+	opts.Depth = depth
 
-	comments, total, err := cc.commentService.GetPostComments(uint(postID), page, pageSize)
+	comments, total, err := cc.commentService.GetPostComments(uint(postID), page, pageSize, opts)
 	if err != nil {
+		if errors.Is(err, services.ErrCommentSort) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "获取评论列表失败", err.Error())
 		return
 	}
@@ -82,6 +122,177 @@ func (cc *CommentController) GetPostComments(c *gin.Context) {
 	})
 }
 
+// GetCommentReplies 分页获取评论的回复（加载更多），cursor 取自评论树的 replies_cursor 或上一页的 next_cursor
+func (cc *CommentController) GetCommentReplies(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "评论ID格式不正确")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	depth, ok := commentDepthParam(c)
+	if !ok {
+		return
+	}
+
+	replies, err := cc.commentService.GetCommentReplies(uint(commentID), models.CommentSort(c.Query("sort")), c.Query("cursor"), limit, depth)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "评论不存在", err.Error())
+		case errors.Is(err, services.ErrCommentSort), errors.Is(err, services.ErrCommentCursor):
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "获取回复列表失败", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取回复列表成功", replies)
+}
+
+// commentDepthParam 解析 depth 参数，未指定时返回 nil
+func commentDepthParam(c *gin.Context) (*int, bool) {
+	value := c.Query("depth")
+	if value == "" {
+		return nil, true
+	}
+	depth, err := strconv.Atoi(value)
+	if err != nil || depth < 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "depth 必须是非负整数")
+		return nil, false
+	}
+	return &depth, true
+}
+
+// GetMyComments 获取当前用户发表的评论
+func (cc *CommentController) GetMyComments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	comments, total, err := cc.commentService.GetUserComments(userID.(uint), page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取评论列表失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取评论列表成功", gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// UpdateComment 修改评论，只能在发表后 comment.edit_window 分钟内修改自己的评论
+func (cc *CommentController) UpdateComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "评论ID格式不正确")
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	// 检查用户是否有权限修改这条评论
+	if !cc.commentService.IsCommentOwner(uint(commentID), userID.(uint)) {
+		utils.ErrorResponse(c, http.StatusForbidden, "权限不足", "只能修改自己的评论")
+		return
+	}
+
+	comment, err := cc.commentService.UpdateComment(c.Request.Context(), uint(commentID), req.Content)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "评论不存在", err.Error())
+		case errors.Is(err, services.ErrCommentEditWindow):
+			utils.ErrorResponse(c, http.StatusForbidden, "更新评论失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "更新评论失败", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "更新评论成功", comment.ToResponse())
+}
+
+// GetCommentHistory 获取评论的修改记录（管理员功能）
+func (cc *CommentController) GetCommentHistory(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "评论ID格式不正确")
+		return
+	}
+
+	history, err := cc.commentService.GetCommentHistory(uint(commentID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "评论不存在", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取修改记录失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取修改记录成功", history)
+}
+
+// ModerateComment 通过或驳回评论（管理员功能）
+func (cc *CommentController) ModerateComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "评论ID格式不正确")
+		return
+	}
+
+	var req ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	comment, err := cc.commentService.ModerateComment(c.Request.Context(), uint(commentID), *req.IsApproved)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "评论不存在", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "审核评论失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "审核评论成功", comment.ToResponse())
+}
+
 // DeleteComment 删除评论
 func (cc *CommentController) DeleteComment(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -103,7 +314,7 @@ func (cc *CommentController) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	if err := cc.commentService.DeleteComment(uint(commentID)); err != nil {
+	if err := cc.commentService.DeleteComment(c.Request.Context(), uint(commentID)); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "删除评论失败", err.Error())
 		return
 	}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blog-system/models"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportController 导出控制器
+type ExportController struct {
+	exportService *services.ExportService
+}
+
+// NewExportController 创建导出控制器实例
+func NewExportController(exportService *services.ExportService) *ExportController {
+	return &ExportController{
+		exportService: exportService,
+	}
+}
+
+// ExportJobResponse 导出任务响应结构
+type ExportJobResponse struct {
+	Job         *models.ExportJob `json:"job"`
+	StatusURL   string            `json:"status_url"`
+	DownloadURL string            `json:"download_url,omitempty"`
+}
+
+// ExportMyBlog 导出当前用户的博客，内容较少时直接下载，否则创建异步任务
+func (ec *ExportController) ExportMyBlog(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	async, err := ec.exportService.ShouldExportAsync(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "导出失败", err.Error())
+		return
+	}
+
+	if async || c.Query("async") == "true" {
+		job, err := ec.exportService.CreateJob(models.ExportScopeUser, userID.(uint))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "创建导出任务失败", err.Error())
+			return
+		}
+		utils.SuccessResponse(c, http.StatusAccepted, "导出任务已创建", ec.jobResponse(job, "/api/v1/users/my/exports"))
+		return
+	}
+
+	filename := fmt.Sprintf("blog-export-%s.zip", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	// 响应已开始写入，出错时只能记录日志
+	if err := ec.exportService.WriteUserArchive(c.Request.Context(), c.Writer, userID.(uint)); err != nil {
+		log.Printf("导出用户 %d 的博客失败: %v", userID, err)
+	}
+}
+
+// GetMyExport 查询当前用户的导出任务状态
+func (ec *ExportController) GetMyExport(c *gin.Context) {
+	job, ok := ec.ownedJob(c)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "获取导出任务成功", ec.jobResponse(job, "/api/v1/users/my/exports"))
+}
+
+// DownloadMyExport 下载当前用户的导出归档
+func (ec *ExportController) DownloadMyExport(c *gin.Context) {
+	job, ok := ec.ownedJob(c)
+	if !ok {
+		return
+	}
+	ec.download(c, job)
+}
+
+// CreateSiteExport 创建全站导出任务（管理员功能）
+func (ec *ExportController) CreateSiteExport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	job, err := ec.exportService.CreateJob(models.ExportScopeSite, userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "创建导出任务失败", err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusAccepted, "导出任务已创建", ec.jobResponse(job, "/api/v1/admin/exports"))
+}
+
+// GetSiteExport 查询导出任务状态（管理员功能）
+func (ec *ExportController) GetSiteExport(c *gin.Context) {
+	job, ok := ec.findJob(c)
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "获取导出任务成功", ec.jobResponse(job, "/api/v1/admin/exports"))
+}
+
+// DownloadSiteExport 下载导出归档（管理员功能）
+func (ec *ExportController) DownloadSiteExport(c *gin.Context) {
+	job, ok := ec.findJob(c)
+	if !ok {
+		return
+	}
+	ec.download(c, job)
+}
+
+// findJob 根据路径参数查找导出任务
+func (ec *ExportController) findJob(c *gin.Context) (*models.ExportJob, bool) {
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "任务ID格式不正确")
+		return nil, false
+	}
+
+	job, err := ec.exportService.GetJob(uint(jobID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "导出任务不存在", err.Error())
+		return nil, false
+	}
+	return job, true
+}
+
+// ownedJob 查找属于当前用户的个人导出任务
+func (ec *ExportController) ownedJob(c *gin.Context) (*models.ExportJob, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return nil, false
+	}
+
+	job, ok := ec.findJob(c)
+	if !ok {
+		return nil, false
+	}
+	if job.UserID != userID.(uint) || job.Scope != models.ExportScopeUser {
+		utils.ErrorResponse(c, http.StatusNotFound, "导出任务不存在", "record not found")
+		return nil, false
+	}
+	return job, true
+}
+
+// download 发送已完成的归档文件
+func (ec *ExportController) download(c *gin.Context, job *models.ExportJob) {
+	switch job.Status {
+	case models.ExportStatusCompleted:
+		filename := fmt.Sprintf("%s-export-%d.zip", job.Scope, job.ID)
+		c.FileAttachment(job.FilePath, filename)
+	case models.ExportStatusPending, models.ExportStatusRunning:
+		utils.ErrorResponse(c, http.StatusConflict, "导出尚未完成", "请稍后再试")
+	case models.ExportStatusExpired:
+		utils.ErrorResponse(c, http.StatusGone, "导出文件已过期", "请重新发起导出")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "导出失败", job.Error)
+	}
+}
+
+// jobResponse 构造带状态和下载链接的任务响应
+func (ec *ExportController) jobResponse(job *models.ExportJob, base string) ExportJobResponse {
+	resp := ExportJobResponse{
+		Job:       job,
+		StatusURL: fmt.Sprintf("%s/%d", base, job.ID),
+	}
+	if job.Status == models.ExportStatusCompleted {
+		resp.DownloadURL = fmt.Sprintf("%s/%d/download", base, job.ID)
+	}
+	return resp
+}
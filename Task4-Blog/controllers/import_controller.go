@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"blog-system/config"
+	"blog-system/importer"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportController 导入控制器
+type ImportController struct {
+	importService *services.ImportService
+}
+
+// NewImportController 创建导入控制器实例
+func NewImportController(importService *services.ImportService) *ImportController {
+	return &ImportController{
+		importService: importService,
+	}
+}
+
+// ImportSite 导入 Markdown 压缩包或 WordPress WXR 文件（管理员功能）
+func (ic *ImportController) ImportSite(c *gin.Context) {
+	doc, ok := ic.parseUpload(c)
+	if !ok {
+		return
+	}
+
+	authorMap, err := importer.ParseAuthorMap(c.PostForm("author_map"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+	if source := c.PostForm("source"); source != "" {
+		doc.Source = source
+	}
+
+	report, err := ic.importService.Import(doc, importer.Options{
+		DryRun:               c.PostForm("dry_run") == "true",
+		AuthorMap:            authorMap,
+		DefaultAuthor:        c.PostForm("default_author"),
+		CreateMissingAuthors: c.PostForm("create_authors") == "true",
+		FallbackCommenter:    c.PostForm("fallback_commenter"),
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "导入失败", err.Error())
+		return
+	}
+	ic.respond(c, report)
+}
+
+// ImportMyBlog 导入到当前用户名下，所有文章归属当前用户
+func (ic *ImportController) ImportMyBlog(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	doc, ok := ic.parseUpload(c)
+	if !ok {
+		return
+	}
+
+	report, err := ic.importService.ImportForUser(userID.(uint), doc, c.PostForm("dry_run") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "导入失败", err.Error())
+		return
+	}
+	ic.respond(c, report)
+}
+
+// parseUpload 读取并解析上传的文件（表单字段 file，可选 format）
+func (ic *ImportController) parseUpload(c *gin.Context) (*importer.Document, bool) {
+	maxSize := int64(config.GetConfig().Storage.MaxImportSize) << 20
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", "请上传文件（字段 file）")
+		return nil, false
+	}
+	if fh.Size > maxSize {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "文件过大",
+			fmt.Sprintf("导入文件不能超过 %d MB", config.GetConfig().Storage.MaxImportSize))
+		return nil, false
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "读取文件失败", err.Error())
+		return nil, false
+	}
+	defer f.Close()
+
+	doc, err := importer.Parse(fh.Filename, f, fh.Size, c.DefaultPostForm("format", importer.FormatAuto))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "解析导入文件失败", err.Error())
+		return nil, false
+	}
+	return doc, true
+}
+
+// respond 返回导入报告
+func (ic *ImportController) respond(c *gin.Context, report *importer.Report) {
+	message := "导入完成"
+	if report.DryRun {
+		message = "预演完成，未写入任何数据"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, report)
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-system/models"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptController 登录记录控制器（管理员功能）
+type LoginAttemptController struct {
+	loginAttemptService *services.LoginAttemptService
+}
+
+// NewLoginAttemptController 创建登录记录控制器实例
+func NewLoginAttemptController(loginAttemptService *services.LoginAttemptService) *LoginAttemptController {
+	return &LoginAttemptController{
+		loginAttemptService: loginAttemptService,
+	}
+}
+
+// GetLoginAttempts 查询登录记录，可按用户、IP 和结果筛选
+func (lc *LoginAttemptController) GetLoginAttempts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filter := services.LoginAttemptFilter{
+		IP:     c.Query("ip"),
+		Result: models.LoginResult(c.Query("result")),
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "用户ID格式不正确")
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+
+	attempts, total, err := lc.loginAttemptService.ListAttempts(filter, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取登录记录失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取登录记录成功", gin.H{
+		"attempts": attempts,
+		"pagination": gin.H{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// UnlockUser 解除用户的登录锁定和两步验证锁定
+func (lc *LoginAttemptController) UnlockUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "用户ID格式不正确")
+		return
+	}
+
+	if err := lc.loginAttemptService.Unlock(uint(userID), c.GetUint("userID"), c.ClientIP()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "用户不存在", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "解除锁定失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "解除锁定成功", nil)
+}
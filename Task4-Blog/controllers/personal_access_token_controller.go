@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blog-system/models"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PersonalAccessTokenController 个人访问令牌控制器
+type PersonalAccessTokenController struct {
+	tokenService *services.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenController 创建个人访问令牌控制器实例
+func NewPersonalAccessTokenController(tokenService *services.PersonalAccessTokenService) *PersonalAccessTokenController {
+	return &PersonalAccessTokenController{
+		tokenService: tokenService,
+	}
+}
+
+// CreateAccessTokenRequest 创建令牌请求结构
+type CreateAccessTokenRequest struct {
+	Name      string             `json:"name" binding:"required,max=100"`
+	Scopes    models.TokenScopes `json:"scopes" binding:"required,min=1,unique,dive,oneof=posts:read posts:write comments:read comments:write profile:read profile:write webhooks:read webhooks:write"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"` // 为空表示永不过期
+}
+
+// CreateAccessTokenResponse 创建令牌响应结构，明文令牌只在创建时返回一次
+type CreateAccessTokenResponse struct {
+	AccessToken *models.PersonalAccessToken `json:"access_token"`
+	Token       string                      `json:"token"`
+}
+
+// CreateMyToken 为当前用户创建个人访问令牌
+func (tc *PersonalAccessTokenController) CreateMyToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	var req CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	token, plain, err := tc.tokenService.CreateToken(userID.(uint), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccessTokenExpiry):
+			utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		case errors.Is(err, services.ErrAccessTokenLimit):
+			utils.ErrorResponse(c, http.StatusConflict, "创建令牌失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "创建令牌失败", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "创建令牌成功，令牌只显示这一次，请妥善保存", CreateAccessTokenResponse{
+		AccessToken: token,
+		Token:       plain,
+	})
+}
+
+// GetMyTokens 获取当前用户的个人访问令牌列表
+func (tc *PersonalAccessTokenController) GetMyTokens(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	tokens, err := tc.tokenService.GetUserTokens(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取令牌列表失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取令牌列表成功", gin.H{
+		"access_tokens": tokens,
+	})
+}
+
+// RevokeMyToken 撤销当前用户的个人访问令牌
+func (tc *PersonalAccessTokenController) RevokeMyToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	tokenID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "令牌ID格式不正确")
+		return
+	}
+
+	token, err := tc.tokenService.RevokeToken(userID.(uint), uint(tokenID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "令牌不存在", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "撤销令牌失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "撤销令牌成功", token)
+}
@@ -57,7 +57,7 @@ func (pc *PostController) CreatePost(c *gin.Context) {
 		return
 	}
 
-	post, err := pc.postService.CreatePost(userID.(uint), req.Title, req.Content, req.Summary, req.Slug, req.Status, req.IsPublic)
+	post, err := pc.postService.CreatePost(c.Request.Context(), userID.(uint), req.Title, req.Content, req.Summary, req.Slug, req.Status, req.IsPublic)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "创建文章失败", err.Error())
 		return
@@ -138,7 +138,7 @@ func (pc *PostController) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	post, err := pc.postService.UpdatePost(uint(postID), req.Title, req.Content, req.Summary, req.Slug, req.Status, req.IsPublic)
+	post, err := pc.postService.UpdatePost(c.Request.Context(), uint(postID), req.Title, req.Content, req.Summary, req.Slug, req.Status, req.IsPublic)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "更新文章失败", err.Error())
 		return
@@ -168,7 +168,7 @@ func (pc *PostController) DeletePost(c *gin.Context) {
 		return
 	}
 
-	if err := pc.postService.DeletePost(uint(postID)); err != nil {
+	if err := pc.postService.DeletePost(c.Request.Context(), uint(postID)); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "删除文章失败", err.Error())
 		return
 	}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashController 回收站控制器。作者管理自己删除的内容，管理员管理所有用户删除的内容
+type TrashController struct {
+	trashService *services.TrashService
+}
+
+// NewTrashController 创建回收站控制器实例
+func NewTrashController(trashService *services.TrashService) *TrashController {
+	return &TrashController{
+		trashService: trashService,
+	}
+}
+
+// GetMyDeletedPosts 获取当前用户回收站中的文章
+func (tc *TrashController) GetMyDeletedPosts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+	tc.listPosts(c, userID.(uint))
+}
+
+// GetMyDeletedComments 获取当前用户回收站中的评论
+func (tc *TrashController) GetMyDeletedComments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+	tc.listComments(c, userID.(uint))
+}
+
+// RestoreMyPost 恢复当前用户删除的文章
+func (tc *TrashController) RestoreMyPost(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+	tc.restorePost(c, userID.(uint))
+}
+
+// RestoreMyComment 恢复当前用户删除的评论
+func (tc *TrashController) RestoreMyComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+	tc.restoreComment(c, userID.(uint))
+}
+
+// PurgeMyPost 永久删除当前用户回收站中的文章
+func (tc *TrashController) PurgeMyPost(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+	tc.purgePost(c, userID.(uint))
+}
+
+// PurgeMyComment 永久删除当前用户回收站中的评论
+func (tc *TrashController) PurgeMyComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+	tc.purgeComment(c, userID.(uint))
+}
+
+// GetDeletedPosts 获取所有用户回收站中的文章（管理员功能），可按 user_id 筛选
+func (tc *TrashController) GetDeletedPosts(c *gin.Context) {
+	userID, ok := trashUserFilter(c)
+	if !ok {
+		return
+	}
+	tc.listPosts(c, userID)
+}
+
+// GetDeletedComments 获取所有用户回收站中的评论（管理员功能），可按 user_id 筛选
+func (tc *TrashController) GetDeletedComments(c *gin.Context) {
+	userID, ok := trashUserFilter(c)
+	if !ok {
+		return
+	}
+	tc.listComments(c, userID)
+}
+
+// RestorePost 恢复任意用户删除的文章（管理员功能）
+func (tc *TrashController) RestorePost(c *gin.Context) {
+	tc.restorePost(c, 0)
+}
+
+// RestoreComment 恢复任意用户删除的评论（管理员功能）
+func (tc *TrashController) RestoreComment(c *gin.Context) {
+	tc.restoreComment(c, 0)
+}
+
+// PurgePost 永久删除回收站中的文章（管理员功能）
+func (tc *TrashController) PurgePost(c *gin.Context) {
+	tc.purgePost(c, 0)
+}
+
+// PurgeComment 永久删除回收站中的评论（管理员功能）
+func (tc *TrashController) PurgeComment(c *gin.Context) {
+	tc.purgeComment(c, 0)
+}
+
+// listPosts 分页返回回收站中的文章，userID 为 0 时不限作者
+func (tc *TrashController) listPosts(c *gin.Context, userID uint) {
+	page, pageSize := trashPagination(c)
+	posts, total, err := tc.trashService.GetDeletedPosts(userID, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取回收站失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取回收站成功", gin.H{
+		"posts":      posts,
+		"pagination": trashPaginationResponse(page, pageSize, total),
+	})
+}
+
+// listComments 分页返回回收站中的评论，userID 为 0 时不限作者
+func (tc *TrashController) listComments(c *gin.Context, userID uint) {
+	page, pageSize := trashPagination(c)
+	comments, total, err := tc.trashService.GetDeletedComments(userID, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取回收站失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取回收站成功", gin.H{
+		"comments":   comments,
+		"pagination": trashPaginationResponse(page, pageSize, total),
+	})
+}
+
+// restorePost 恢复文章，userID 不为 0 时只能恢复该用户的文章
+func (tc *TrashController) restorePost(c *gin.Context, userID uint) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "文章ID格式不正确")
+		return
+	}
+
+	post, err := tc.trashService.RestorePost(c.Request.Context(), userID, uint(postID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中没有这篇文章", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "恢复文章失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "恢复文章成功", post.ToResponse())
+}
+
+// restoreComment 恢复评论，userID 不为 0 时只能恢复该用户的评论
+func (tc *TrashController) restoreComment(c *gin.Context, userID uint) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "评论ID格式不正确")
+		return
+	}
+
+	comment, err := tc.trashService.RestoreComment(c.Request.Context(), userID, uint(commentID))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中没有这条评论", err.Error())
+		case errors.Is(err, services.ErrTrashPostDeleted), errors.Is(err, services.ErrTrashParentDeleted):
+			utils.ErrorResponse(c, http.StatusConflict, "恢复评论失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "恢复评论失败", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "恢复评论成功", comment.ToResponse())
+}
+
+// purgePost 永久删除文章，userID 不为 0 时只能删除该用户的文章
+func (tc *TrashController) purgePost(c *gin.Context, userID uint) {
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "文章ID格式不正确")
+		return
+	}
+
+	if err := tc.trashService.PurgePost(c.Request.Context(), userID, uint(postID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中没有这篇文章", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "永久删除文章失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "永久删除文章成功", nil)
+}
+
+// purgeComment 永久删除评论，userID 不为 0 时只能删除该用户的评论
+func (tc *TrashController) purgeComment(c *gin.Context, userID uint) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "评论ID格式不正确")
+		return
+	}
+
+	if err := tc.trashService.PurgeComment(c.Request.Context(), userID, uint(commentID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中没有这条评论", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "永久删除评论失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "永久删除评论成功", nil)
+}
+
+// trashUserFilter 解析管理员查询的 user_id 参数，未指定时返回 0
+func trashUserFilter(c *gin.Context) (uint, bool) {
+	value := c.Query("user_id")
+	if value == "" {
+		return 0, true
+	}
+	userID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || userID == 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "用户ID格式不正确")
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// trashPagination 解析分页参数
+func trashPagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// trashPaginationResponse 分页信息
+func trashPaginationResponse(page, pageSize int, total int64) gin.H {
+	return gin.H{
+		"page":       page,
+		"page_size":  pageSize,
+		"total":      total,
+		"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+	}
+}
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"blog-system/models"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TwoFactorController 两步验证控制器
+type TwoFactorController struct {
+	twoFactorService *services.TwoFactorService
+	authService      *services.AuthService
+	userService      *services.UserService
+}
+
+// NewTwoFactorController 创建两步验证控制器实例
+func NewTwoFactorController(twoFactorService *services.TwoFactorService, authService *services.AuthService, userService *services.UserService) *TwoFactorController {
+	return &TwoFactorController{
+		twoFactorService: twoFactorService,
+		authService:      authService,
+		userService:      userService,
+	}
+}
+
+// TwoFactorCodeRequest 验证码请求结构，code 可以是验证器生成的 6 位验证码或恢复码
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// VerifyTwoFactorRequest 登录第二步请求结构
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+}
+
+// RecoveryCodesResponse 恢复码响应结构，恢复码只在生成时返回一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ConfirmTwoFactorResponse 开启两步验证响应结构，返回的新 token 已完成两步验证
+type ConfirmTwoFactorResponse struct {
+	Token         string              `json:"token"`
+	User          models.UserResponse `json:"user"`
+	RecoveryCodes []string            `json:"recovery_codes"`
+}
+
+// GetStatus 获取当前用户的两步验证状态
+func (tc *TwoFactorController) GetStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	status, err := tc.twoFactorService.GetStatus(userID.(uint))
+	if err != nil {
+		tc.handleError(c, "获取两步验证状态失败", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取两步验证状态成功", status)
+}
+
+// BeginEnrollment 生成验证器密钥和二维码
+func (tc *TwoFactorController) BeginEnrollment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	enrollment, err := tc.twoFactorService.BeginEnrollment(userID.(uint))
+	if err != nil {
+		tc.handleError(c, "生成验证器密钥失败", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "请使用验证器扫描二维码，然后提交验证码完成开启", enrollment)
+}
+
+// ConfirmEnrollment 提交验证码，开启两步验证
+func (tc *TwoFactorController) ConfirmEnrollment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	user, codes, err := tc.twoFactorService.ConfirmEnrollment(userID.(uint), req.Code)
+	if err != nil {
+		tc.handleError(c, "开启两步验证失败", err)
+		return
+	}
+
+	// 刚刚提交了正确的验证码，新 token 视为通过了两步验证
+	token, err := tc.authService.GenerateToken(user, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成token失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "两步验证已开启，请妥善保存恢复码", ConfirmTwoFactorResponse{
+		Token:         token,
+		User:          user.ToResponse(),
+		RecoveryCodes: codes,
+	})
+}
+
+// Disable 关闭两步验证
+func (tc *TwoFactorController) Disable(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	if err := tc.twoFactorService.Disable(userID.(uint), req.Code); err != nil {
+		tc.handleError(c, "关闭两步验证失败", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "两步验证已关闭", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (tc *TwoFactorController) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	codes, err := tc.twoFactorService.RegenerateRecoveryCodes(userID.(uint), req.Code)
+	if err != nil {
+		tc.handleError(c, "生成恢复码失败", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "恢复码已重新生成，旧恢复码已失效", RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// Verify 登录第二步：提交挑战令牌和验证码，换取正式 token
+func (tc *TwoFactorController) Verify(c *gin.Context) {
+	var req VerifyTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	userID, err := tc.authService.ParseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", "挑战令牌无效或已过期")
+		return
+	}
+
+	user, err := tc.twoFactorService.Verify(userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTwoFactorLocked):
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "登录失败", err.Error())
+		case errors.Is(err, services.ErrTwoFactorCode), errors.Is(err, services.ErrTwoFactorNotEnabled):
+			utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusUnauthorized, "登录失败", "账号不存在或已停用")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "登录失败", err.Error())
+		}
+		return
+	}
+
+	// 更新最后登录时间并记入审计日志
+	tc.userService.RecordLogin(c.Request.Context(), user, "two_factor")
+
+	token, err := tc.authService.GenerateToken(user, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成token失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "登录成功", AuthResponse{
+		Token: token,
+		User:  user.ToResponse(),
+	})
+}
+
+// handleError 将两步验证服务的错误映射为响应
+func (tc *TwoFactorController) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorCode):
+		utils.ErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	case errors.Is(err, services.ErrTwoFactorLocked):
+		utils.ErrorResponse(c, http.StatusTooManyRequests, message, err.Error())
+	case errors.Is(err, services.ErrTwoFactorEnabled), errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorNotPending):
+		utils.ErrorResponse(c, http.StatusConflict, message, err.Error())
+	case errors.Is(err, services.ErrTwoFactorRequired):
+		utils.ErrorResponse(c, http.StatusForbidden, message, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "用户不存在", err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
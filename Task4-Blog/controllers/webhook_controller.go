@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-system/models"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController Webhook 控制器
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController 创建 Webhook 控制器实例
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhookRequest 注册端点请求结构
+type CreateWebhookRequest struct {
+	URL         string               `json:"url" binding:"required,url,max=500"`
+	Events      models.WebhookEvents `json:"events" binding:"required,min=1,dive,oneof=post.published post.updated post.deleted comment.created"`
+	Description string               `json:"description,omitempty" binding:"max=255"`
+}
+
+// UpdateWebhookRequest 更新端点请求结构
+type UpdateWebhookRequest struct {
+	URL         string               `json:"url,omitempty" binding:"omitempty,url,max=500"`
+	Events      models.WebhookEvents `json:"events,omitempty" binding:"omitempty,min=1,dive,oneof=post.published post.updated post.deleted comment.created"`
+	Description *string              `json:"description,omitempty" binding:"omitempty,max=255"`
+	IsActive    *bool                `json:"is_active,omitempty"`
+}
+
+// CreateWebhookResponse 注册端点响应结构，签名密钥只在创建时返回一次
+type CreateWebhookResponse struct {
+	Webhook *models.Webhook `json:"webhook"`
+	Secret  string          `json:"secret"`
+}
+
+// CreateMyWebhook 注册当前用户的端点，只接收自己内容的事件
+func (wc *WebhookController) CreateMyWebhook(c *gin.Context) {
+	wc.create(c, models.WebhookScopeUser)
+}
+
+// GetMyWebhooks 获取当前用户的端点列表
+func (wc *WebhookController) GetMyWebhooks(c *gin.Context) {
+	wc.list(c, models.WebhookScopeUser)
+}
+
+// GetMyWebhook 获取当前用户的端点详情
+func (wc *WebhookController) GetMyWebhook(c *gin.Context) {
+	if webhook, ok := wc.findWebhook(c, models.WebhookScopeUser); ok {
+		utils.SuccessResponse(c, http.StatusOK, "获取端点成功", webhook)
+	}
+}
+
+// UpdateMyWebhook 更新当前用户的端点
+func (wc *WebhookController) UpdateMyWebhook(c *gin.Context) {
+	wc.update(c, models.WebhookScopeUser)
+}
+
+// DeleteMyWebhook 删除当前用户的端点
+func (wc *WebhookController) DeleteMyWebhook(c *gin.Context) {
+	wc.delete(c, models.WebhookScopeUser)
+}
+
+// GetMyWebhookDeliveries 获取当前用户端点的投递记录
+func (wc *WebhookController) GetMyWebhookDeliveries(c *gin.Context) {
+	wc.deliveries(c, models.WebhookScopeUser)
+}
+
+// GetMyWebhookDelivery 获取当前用户端点的投递详情
+func (wc *WebhookController) GetMyWebhookDelivery(c *gin.Context) {
+	wc.delivery(c, models.WebhookScopeUser)
+}
+
+// CreateSiteWebhook 注册全站端点，接收所有内容的事件（管理员功能）
+func (wc *WebhookController) CreateSiteWebhook(c *gin.Context) {
+	wc.create(c, models.WebhookScopeSite)
+}
+
+// GetSiteWebhooks 获取全站端点列表（管理员功能）
+func (wc *WebhookController) GetSiteWebhooks(c *gin.Context) {
+	wc.list(c, models.WebhookScopeSite)
+}
+
+// GetSiteWebhook 获取全站端点详情（管理员功能）
+func (wc *WebhookController) GetSiteWebhook(c *gin.Context) {
+	if webhook, ok := wc.findWebhook(c, models.WebhookScopeSite); ok {
+		utils.SuccessResponse(c, http.StatusOK, "获取端点成功", webhook)
+	}
+}
+
+// UpdateSiteWebhook 更新全站端点（管理员功能）
+func (wc *WebhookController) UpdateSiteWebhook(c *gin.Context) {
+	wc.update(c, models.WebhookScopeSite)
+}
+
+// DeleteSiteWebhook 删除全站端点（管理员功能）
+func (wc *WebhookController) DeleteSiteWebhook(c *gin.Context) {
+	wc.delete(c, models.WebhookScopeSite)
+}
+
+// GetSiteWebhookDeliveries 获取全站端点的投递记录（管理员功能）
+func (wc *WebhookController) GetSiteWebhookDeliveries(c *gin.Context) {
+	wc.deliveries(c, models.WebhookScopeSite)
+}
+
+// GetSiteWebhookDelivery 获取全站端点的投递详情（管理员功能）
+func (wc *WebhookController) GetSiteWebhookDelivery(c *gin.Context) {
+	wc.delivery(c, models.WebhookScopeSite)
+}
+
+// create 注册端点
+func (wc *WebhookController) create(c *gin.Context, scope models.WebhookScope) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	webhook, err := wc.webhookService.CreateWebhook(userID.(uint), scope, req.URL, req.Events, req.Description)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookURL):
+			utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		case errors.Is(err, services.ErrWebhookLimit):
+			utils.ErrorResponse(c, http.StatusConflict, "注册端点失败", err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "注册端点失败", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "注册端点成功，请妥善保存签名密钥", CreateWebhookResponse{
+		Webhook: webhook,
+		Secret:  webhook.Secret,
+	})
+}
+
+// list 获取端点列表
+func (wc *WebhookController) list(c *gin.Context, scope models.WebhookScope) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return
+	}
+
+	webhooks, err := wc.webhookService.ListWebhooks(userID.(uint), scope)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取端点列表失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取端点列表成功", gin.H{
+		"webhooks": webhooks,
+	})
+}
+
+// update 更新端点
+func (wc *WebhookController) update(c *gin.Context, scope models.WebhookScope) {
+	webhook, ok := wc.findWebhook(c, scope)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+		return
+	}
+
+	updated, err := wc.webhookService.UpdateWebhook(webhook.ID, req.URL, req.Events, req.Description, req.IsActive)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookURL) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "请求参数错误", err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "更新端点失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "更新端点成功", updated)
+}
+
+// delete 删除端点
+func (wc *WebhookController) delete(c *gin.Context, scope models.WebhookScope) {
+	webhook, ok := wc.findWebhook(c, scope)
+	if !ok {
+		return
+	}
+
+	if err := wc.webhookService.DeleteWebhook(webhook.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "删除端点失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "删除端点成功", nil)
+}
+
+// deliveries 获取端点的投递记录
+func (wc *WebhookController) deliveries(c *gin.Context, scope models.WebhookScope) {
+	webhook, ok := wc.findWebhook(c, scope)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	deliveries, total, err := wc.webhookService.GetDeliveries(webhook.ID, page, pageSize)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "获取投递记录失败", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取投递记录成功", gin.H{
+		"deliveries": deliveries,
+		"pagination": gin.H{
+			"page":       page,
+			"page_size":  pageSize,
+			"total":      total,
+			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// delivery 获取投递详情，包含每次尝试的状态码、错误和响应
+func (wc *WebhookController) delivery(c *gin.Context, scope models.WebhookScope) {
+	webhook, ok := wc.findWebhook(c, scope)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "投递ID格式不正确")
+		return
+	}
+
+	delivery, err := wc.webhookService.GetDelivery(webhook.ID, uint(deliveryID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "投递记录不存在", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "获取投递详情成功", delivery)
+}
+
+// findWebhook 根据路径参数查找端点，个人端点只能由所有者访问
+func (wc *WebhookController) findWebhook(c *gin.Context, scope models.WebhookScope) (*models.Webhook, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未授权", "用户信息不存在")
+		return nil, false
+	}
+
+	webhookID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "参数错误", "端点ID格式不正确")
+		return nil, false
+	}
+
+	webhook, err := wc.webhookService.GetWebhook(uint(webhookID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "端点不存在", err.Error())
+		return nil, false
+	}
+	if webhook.Scope != scope || (scope == models.WebhookScopeUser && webhook.UserID != userID.(uint)) {
+		utils.ErrorResponse(c, http.StatusNotFound, "端点不存在", "record not found")
+		return nil, false
+	}
+	return webhook, true
+}
@@ -1,13 +1,14 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"blog-system/config"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -15,6 +16,9 @@ import (
 // DB 全局数据库实例
 var DB *gorm.DB
 
+// currentDialect 当前使用的数据库方言
+var currentDialect *Dialect
+
 // InitDB 初始化数据库连接
 func InitDB() (*gorm.DB, error) {
 	cfg := config.GetConfig()
@@ -24,15 +28,28 @@ func InitDB() (*gorm.DB, error) {
 	
 	// GORM 配置
 	gormConfig := &gorm.Config{
-		// 在 debug 模式下显示详细的 SQL 日志
-		Logger: logger.Default.LogMode(getLogLevel(cfg.Server.Mode)),
+		// 日志级别为 debug 时显示详细的 SQL 日志，随配置热加载调整
+		Logger: sqlLogger,
 		// 禁用默认的事务
 		SkipDefaultTransaction: false,
 	}
 
+	// 根据驱动选择方言
+	dialect, err := GetDialect(cfg.Database.Driver)
+	if err != nil {
+		return nil, err
+	}
+	currentDialect = dialect
+
+	sqlLogger.setLevel(cfg.Log.Level)
+	config.OnChange(func(old, cfg *config.Config) {
+		if cfg.Log.Level != old.Log.Level {
+			sqlLogger.setLevel(cfg.Log.Level)
+		}
+	})
+
 	// 连接数据库
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), gormConfig)
+	DB, err = gorm.Open(dialect.Open(dsn), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("数据库连接失败: %v", err)
 	}
@@ -56,18 +73,56 @@ func InitDB() (*gorm.DB, error) {
 	return DB, nil
 }
 
-// getLogLevel 根据运行模式获取 GORM 日志级别
-func getLogLevel(mode string) logger.LogLevel {
-	switch mode {
-	case "release":
-		return logger.Silent
-	case "test":
-		return logger.Warn
-	default: // debug
-		return logger.Info
+// sqlLogger GORM 日志，级别随 log.level 调整
+var sqlLogger = &levelLogger{}
+
+// levelLogger 可在运行期间调整级别的 GORM 日志
+type levelLogger struct {
+	level atomic.Int32
+}
+
+// setLevel 按 log.level 设置 GORM 日志级别：debug 记录所有 SQL，info、warn 记录慢查询和错误，error 只记录错误
+func (l *levelLogger) setLevel(level string) {
+	switch level {
+	case "debug":
+		l.level.Store(int32(logger.Info))
+	case "error":
+		l.level.Store(int32(logger.Error))
+	default:
+		l.level.Store(int32(logger.Warn))
 	}
 }
 
+func (l *levelLogger) current() logger.Interface {
+	return logger.Default.LogMode(logger.LogLevel(l.level.Load()))
+}
+
+// LogMode 返回固定级别的日志，用于 db.Debug() 等场景
+func (l *levelLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return logger.Default.LogMode(level)
+}
+
+func (l *levelLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.current().Info(ctx, msg, data...)
+}
+
+func (l *levelLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.current().Warn(ctx, msg, data...)
+}
+
+func (l *levelLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.current().Error(ctx, msg, data...)
+}
+
+func (l *levelLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	l.current().Trace(ctx, begin, fc, err)
+}
+
+// CurrentDialect 获取当前使用的数据库方言
+func CurrentDialect() *Dialect {
+	return currentDialect
+}
+
 // GetDB 获取数据库实例
 func GetDB() *gorm.DB {
 	return DB
@@ -86,7 +141,7 @@ func CloseDB() error {
 }
 
 // HealthCheck 数据库健康检查
-func HealthCheck() error {
+func HealthCheck(ctx context.Context) error {
 	if DB == nil {
 		return fmt.Errorf("数据库连接未初始化")
 	}
@@ -96,5 +151,5 @@ func HealthCheck() error {
 		return err
 	}
 	
-	return sqlDB.Ping()
+	return sqlDB.PingContext(ctx)
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Dialect 数据库方言，封装不同驱动之间的差异
+type Dialect struct {
+	Name string
+	// Open 根据 DSN 创建 GORM 方言
+	Open func(dsn string) gorm.Dialector
+	// CreateVersionTable 创建 schema_migrations 表的语句
+	CreateVersionTable string
+	// ListTables 列出当前库中所有表名的查询
+	ListTables string
+	// Lock/Unlock 迁移期间的全局锁，防止多个实例同时迁移
+	Lock   func(ctx context.Context, conn *sql.Conn) error
+	Unlock func(ctx context.Context, conn *sql.Conn) error
+	// Placeholder 第 n 个（从 1 开始）绑定参数的占位符
+	Placeholder func(n int) string
+}
+
+// migrationLockName 迁移锁名称
+const migrationLockName = "blog_system_migrate"
+
+// dialects 已支持的方言
+var dialects = map[string]*Dialect{
+	"mysql": {
+		Name: "mysql",
+		Open: mysql.Open,
+		CreateVersionTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at DATETIME(3) NOT NULL
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+		ListTables: "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE()",
+		Lock: func(ctx context.Context, conn *sql.Conn) error {
+			var got sql.NullInt64
+			if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 30)", migrationLockName).Scan(&got); err != nil {
+				return err
+			}
+			if !got.Valid || got.Int64 != 1 {
+				return fmt.Errorf("获取迁移锁超时，可能有其他实例正在迁移")
+			}
+			return nil
+		},
+		Unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName)
+			return err
+		},
+		Placeholder: func(n int) string { return "?" },
+	},
+	"postgres": {
+		Name: "postgres",
+		Open: postgres.Open,
+		CreateVersionTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`,
+		ListTables: "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema()",
+		Lock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", migrationLockName)
+			return err
+		},
+		Unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", migrationLockName)
+			return err
+		},
+		Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+	},
+}
+
+// GetDialect 根据驱动名称获取方言
+func GetDialect(driver string) (*Dialect, error) {
+	if driver == "" {
+		driver = "mysql"
+	}
+	d, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+	return d, nil
+}
@@ -1,31 +1,29 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
-	"blog-system/models"
+	"blog-system/seed"
 )
 
-// Migrate 执行数据库迁移
+// Migrate 执行所有未执行的数据库迁移
 func Migrate() error {
-	if DB == nil {
-		return fmt.Errorf("数据库连接未初始化")
+	migrator, err := NewMigrator()
+	if err != nil {
+		return err
 	}
 
 	log.Println("开始数据库迁移...")
 
-	// 自动迁移所有模型
-	err := DB.AutoMigrate(
-		&models.User{},
-		&models.Post{},
-		&models.Comment{},
-	)
+	count, err := migrator.Up(context.Background())
 	if err != nil {
 		return fmt.Errorf("数据库迁移失败: %v", err)
 	}
 
-	log.Println("数据库迁移完成!")
+	log.Printf("数据库迁移完成! 本次执行 %d 个迁移，当前版本 %d", count, migrator.Latest())
 	
 	// 显示创建的表信息
 	showTableInfo()
@@ -33,44 +31,68 @@ func Migrate() error {
 	return nil
 }
 
+// MigrationsApplied 检查所有迁移是否都已执行
+func MigrationsApplied(ctx context.Context) error {
+	migrator, err := NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("数据库版本落后 %d 个迁移（最早未执行: %04d_%s），请先执行 migrate up",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
 // showTableInfo 显示表信息
 func showTableInfo() {
-	var tableInfo []struct {
-		TableName string `gorm:"column:TABLE_NAME"`
-	}
-	
-	DB.Raw("SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = DATABASE()").Scan(&tableInfo)
+	var tables []string
+	DB.Raw(currentDialect.ListTables).Scan(&tables)
 	
 	log.Println("数据库表列表:")
-	for _, table := range tableInfo {
-		log.Printf("   - %s", table.TableName)
+	for _, table := range tables {
+		log.Printf("   - %s", table)
 	}
 }
 
-// CreateTestData 创建测试数据
-func CreateTestData() error {
-	log.Println("创建测试数据...")
-	
-	// 这里可以添加一些初始测试数据
-	
-	log.Println("测试数据创建完成")
+// CreateTestData 按选项生成测试数据
+func CreateTestData(opts seed.Options) error {
+	if DB == nil {
+		return fmt.Errorf("数据库连接未初始化")
+	}
+
+	log.Printf("创建测试数据（种子 %d）...", opts.Seed)
+
+	stats, err := seed.Run(DB, opts)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("测试数据创建完成: 用户 %d, 文章 %d, 标签 %d, 评论 %d, 耗时 %v",
+		stats.Users, stats.Posts, stats.Tags, stats.Comments, stats.Elapsed.Round(time.Millisecond))
 	return nil
 }
 
-// ResetDatabase 重置数据库（开发环境使用）
+// ResetDatabase 重置数据库（开发环境使用），回滚全部迁移后重新执行
 func ResetDatabase() error {
-	if DB == nil {
-		return fmt.Errorf("数据库连接未初始化")
+	migrator, err := NewMigrator()
+	if err != nil {
+		return err
 	}
 
 	log.Println("⚠️  重置数据库...")
 
-	// 删除所有表（按依赖顺序）
-	tables := []string{"comments", "posts", "users"}
-	for _, table := range tables {
-		if err := DB.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table)).Error; err != nil {
-			return fmt.Errorf("删除表 %s 失败: %v", table, err)
-		}
+	ctx := context.Background()
+	if _, err := migrator.To(ctx, 0); err != nil {
+		return fmt.Errorf("回滚迁移失败: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("重新执行迁移失败: %v", err)
 	}
 
 	log.Println("数据库重置完成")
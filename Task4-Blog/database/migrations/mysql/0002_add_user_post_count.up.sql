@@ -0,0 +1,6 @@
+-- Post 的钩子函数维护 users.post_count，但初始表结构中缺少该列
+ALTER TABLE users ADD COLUMN post_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE users SET post_count = (
+    SELECT COUNT(*) FROM posts WHERE posts.user_id = users.id AND posts.deleted_at IS NULL
+);
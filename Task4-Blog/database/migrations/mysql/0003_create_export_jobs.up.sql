@@ -0,0 +1,18 @@
+CREATE TABLE export_jobs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    scope VARCHAR(20) NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_path VARCHAR(500),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at DATETIME(3) NULL,
+    completed_at DATETIME(3) NULL,
+    expires_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_export_jobs_user_id (user_id),
+    INDEX idx_export_jobs_status (status),
+    CONSTRAINT fk_export_jobs_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+-- 记录导入来源与本地数据的对应关系，保证重复导入时幂等
+CREATE TABLE import_records (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    source VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id BIGINT UNSIGNED NOT NULL,
+    checksum VARCHAR(64),
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_import_records_key (source, entity_type, external_id),
+    INDEX idx_import_records_entity (entity_type, entity_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
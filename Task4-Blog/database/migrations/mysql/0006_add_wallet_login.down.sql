@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS auth_nonces;
+DROP INDEX idx_users_wallet_address ON users;
+ALTER TABLE users DROP COLUMN wallet_address;
@@ -0,0 +1,11 @@
+-- 以太坊钱包登录（EIP-4361）：用户绑定的钱包地址和一次性 nonce
+ALTER TABLE users ADD COLUMN wallet_address VARCHAR(42) NULL;
+CREATE UNIQUE INDEX idx_users_wallet_address ON users (wallet_address);
+
+CREATE TABLE auth_nonces (
+    nonce VARCHAR(32) NOT NULL,
+    expires_at DATETIME(3) NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (nonce),
+    INDEX idx_auth_nonces_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
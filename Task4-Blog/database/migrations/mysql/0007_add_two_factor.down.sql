@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS recovery_codes;
+ALTER TABLE users
+    DROP COLUMN two_factor_enabled,
+    DROP COLUMN totp_secret,
+    DROP COLUMN totp_last_step,
+    DROP COLUMN two_factor_failures,
+    DROP COLUMN two_factor_locked_until;
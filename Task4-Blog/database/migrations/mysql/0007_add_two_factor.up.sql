@@ -0,0 +1,18 @@
+-- TOTP 两步验证：用户的验证器密钥和状态，以及一次性恢复码
+ALTER TABLE users
+    ADD COLUMN two_factor_enabled TINYINT(1) NOT NULL DEFAULT 0,
+    ADD COLUMN totp_secret VARCHAR(64) NULL,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN two_factor_failures INT NOT NULL DEFAULT 0,
+    ADD COLUMN two_factor_locked_until DATETIME(3) NULL;
+
+CREATE TABLE recovery_codes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_recovery_codes_user_id (user_id),
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,14 @@
+-- 登录尝试记录：失败次数统计、渐进延迟和锁定，同时用于审计
+CREATE TABLE login_attempts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id BIGINT UNSIGNED NULL,
+    identifier VARCHAR(100) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    reason VARCHAR(50) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_login_attempts_user_created (user_id, created_at),
+    INDEX idx_login_attempts_identifier_created (identifier, created_at),
+    INDEX idx_login_attempts_ip_created (ip, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
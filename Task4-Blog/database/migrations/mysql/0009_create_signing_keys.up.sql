@@ -0,0 +1,11 @@
+-- JWT 签名密钥（RS256 / EdDSA），按 activates_at 轮换
+CREATE TABLE signing_keys (
+    id VARCHAR(64) NOT NULL,
+    algorithm VARCHAR(16) NOT NULL,
+    private_key TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    activates_at DATETIME(3) NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_signing_keys_activates_at (activates_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
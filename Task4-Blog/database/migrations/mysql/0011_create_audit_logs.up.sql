@@ -0,0 +1,20 @@
+-- 审计日志：只追加，超过保留期后由后台任务删除
+CREATE TABLE audit_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    actor_id BIGINT UNSIGNED NULL,
+    actor_name VARCHAR(50) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30) NOT NULL,
+    target_id BIGINT UNSIGNED NOT NULL,
+    `before` LONGTEXT NULL,
+    `after` LONGTEXT NULL,
+    ip VARCHAR(45) NULL,
+    request_id VARCHAR(64) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_audit_logs_actor_created (actor_id, created_at),
+    INDEX idx_audit_logs_action (action),
+    INDEX idx_audit_logs_target (target_type, target_id),
+    INDEX idx_audit_logs_request_id (request_id),
+    INDEX idx_audit_logs_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
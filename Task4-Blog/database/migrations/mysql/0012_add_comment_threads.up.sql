@@ -0,0 +1,25 @@
+-- 评论树：path 保存祖先评论ID路径（如 3/17/，顶级评论为空），reply_count 为审核通过的直接回复数
+ALTER TABLE comments
+    ADD COLUMN path TEXT NULL,
+    ADD COLUMN path_anchor BIGINT UNSIGNED NULL,
+    ADD COLUMN reply_count BIGINT NOT NULL DEFAULT 0;
+
+-- 兼容 MySQL 5.7（不支持递归 CTE）：path 先只包含父评论，path_anchor 指向尚未展开的祖先，
+-- 每次更新把祖先已知的路径拼到前面并跳到祖先的 path_anchor，已知长度翻倍，7 次可覆盖 128 层
+UPDATE comments SET path = IF(parent_id IS NULL, '', CONCAT(parent_id, '/')), path_anchor = parent_id;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+UPDATE comments c JOIN comments a ON c.path_anchor = a.id SET c.path = CONCAT(a.path, c.path), c.path_anchor = a.path_anchor;
+
+ALTER TABLE comments DROP COLUMN path_anchor, MODIFY path TEXT NOT NULL;
+
+UPDATE comments c JOIN (
+    SELECT parent_id, COUNT(*) AS total FROM comments
+    WHERE parent_id IS NOT NULL AND is_approved = 1 AND deleted_at IS NULL
+    GROUP BY parent_id
+) r ON r.parent_id = c.id
+SET c.reply_count = r.total;
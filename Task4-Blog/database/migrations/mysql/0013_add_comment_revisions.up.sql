@@ -0,0 +1,13 @@
+-- 评论修改：edited_at 为最后一次修改的时间，comment_revisions 保存每次修改前的版本
+ALTER TABLE comments ADD COLUMN edited_at DATETIME(3) NULL;
+
+CREATE TABLE comment_revisions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    comment_id BIGINT UNSIGNED NOT NULL,
+    content TEXT NOT NULL,
+    is_approved TINYINT(1) NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_comment_revisions_comment_id (comment_id),
+    CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES comments (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+-- 评论审核：moderated_at 为管理员最后一次审核的时间，管理员驳回的评论修改后仍然待审核
+ALTER TABLE comments ADD COLUMN moderated_at DATETIME(3) NULL;
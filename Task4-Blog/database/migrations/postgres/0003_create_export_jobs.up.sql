@@ -0,0 +1,17 @@
+CREATE TABLE export_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    scope VARCHAR(20) NOT NULL,
+    user_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_path VARCHAR(500),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMPTZ NULL,
+    completed_at TIMESTAMPTZ NULL,
+    expires_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL,
+    CONSTRAINT fk_export_jobs_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_export_jobs_user_id ON export_jobs (user_id);
+CREATE INDEX idx_export_jobs_status ON export_jobs (status);
@@ -0,0 +1,13 @@
+-- 记录导入来源与本地数据的对应关系，保证重复导入时幂等
+CREATE TABLE import_records (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(255) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    checksum VARCHAR(64),
+    created_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NULL
+);
+CREATE UNIQUE INDEX idx_import_records_key ON import_records (source, entity_type, external_id);
+CREATE INDEX idx_import_records_entity ON import_records (entity_type, entity_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS auth_nonces;
+DROP INDEX IF EXISTS idx_users_wallet_address;
+ALTER TABLE users DROP COLUMN wallet_address;
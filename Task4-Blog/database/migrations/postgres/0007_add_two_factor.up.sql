@@ -0,0 +1,17 @@
+-- TOTP 两步验证：用户的验证器密钥和状态，以及一次性恢复码
+ALTER TABLE users
+    ADD COLUMN two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_secret VARCHAR(64) NULL,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN two_factor_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN two_factor_locked_until TIMESTAMPTZ NULL;
+
+CREATE TABLE recovery_codes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NULL,
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
@@ -0,0 +1,13 @@
+-- 登录尝试记录：失败次数统计、渐进延迟和锁定，同时用于审计
+CREATE TABLE login_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NULL,
+    identifier VARCHAR(100) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    reason VARCHAR(50) NULL,
+    created_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_login_attempts_user_created ON login_attempts (user_id, created_at);
+CREATE INDEX idx_login_attempts_identifier_created ON login_attempts (identifier, created_at);
+CREATE INDEX idx_login_attempts_ip_created ON login_attempts (ip, created_at);
@@ -0,0 +1,10 @@
+-- JWT 签名密钥（RS256 / EdDSA），按 activates_at 轮换
+CREATE TABLE signing_keys (
+    id VARCHAR(64) PRIMARY KEY,
+    algorithm VARCHAR(16) NOT NULL,
+    private_key TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    activates_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_signing_keys_activates_at ON signing_keys (activates_at);
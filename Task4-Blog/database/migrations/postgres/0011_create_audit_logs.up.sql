@@ -0,0 +1,19 @@
+-- 审计日志：只追加，超过保留期后由后台任务删除
+CREATE TABLE audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NULL,
+    actor_name VARCHAR(50) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30) NOT NULL,
+    target_id BIGINT NOT NULL,
+    before TEXT NULL,
+    after TEXT NULL,
+    ip VARCHAR(45) NULL,
+    request_id VARCHAR(64) NULL,
+    created_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_audit_logs_actor_created ON audit_logs (actor_id, created_at);
+CREATE INDEX idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX idx_audit_logs_target ON audit_logs (target_type, target_id);
+CREATE INDEX idx_audit_logs_request_id ON audit_logs (request_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
@@ -0,0 +1 @@
+ALTER TABLE comments DROP COLUMN path, DROP COLUMN reply_count;
@@ -0,0 +1,18 @@
+-- 评论树：path 保存祖先评论ID路径（如 3/17/，顶级评论为空），reply_count 为审核通过的直接回复数
+ALTER TABLE comments
+    ADD COLUMN path TEXT NOT NULL DEFAULT '',
+    ADD COLUMN reply_count BIGINT NOT NULL DEFAULT 0;
+
+WITH RECURSIVE tree AS (
+    SELECT id, ''::TEXT AS path FROM comments WHERE parent_id IS NULL
+    UNION ALL
+    SELECT c.id, tree.path || tree.id || '/' FROM comments c JOIN tree ON c.parent_id = tree.id
+)
+UPDATE comments SET path = tree.path FROM tree WHERE comments.id = tree.id;
+
+UPDATE comments SET reply_count = r.total FROM (
+    SELECT parent_id, COUNT(*) AS total FROM comments
+    WHERE parent_id IS NOT NULL AND is_approved = TRUE AND deleted_at IS NULL
+    GROUP BY parent_id
+) r
+WHERE r.parent_id = comments.id;
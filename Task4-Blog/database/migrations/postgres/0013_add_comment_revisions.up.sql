@@ -0,0 +1,12 @@
+-- 评论修改：edited_at 为最后一次修改的时间，comment_revisions 保存每次修改前的版本
+ALTER TABLE comments ADD COLUMN edited_at TIMESTAMPTZ NULL;
+
+CREATE TABLE comment_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL,
+    content TEXT NOT NULL,
+    is_approved BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NULL,
+    CONSTRAINT fk_comment_revisions_comment FOREIGN KEY (comment_id) REFERENCES comments (id)
+);
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions (comment_id);
@@ -0,0 +1,399 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations
+var migrationFS embed.FS
+
+// migrationFileRe 迁移文件名格式：0001_create_users.up.sql
+var migrationFileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 版本化迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState 迁移状态
+type MigrationState struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Migrator 迁移执行器
+type Migrator struct {
+	db         *sql.DB
+	dialect    *Dialect
+	migrations []Migration
+}
+
+// NewMigrator 基于当前数据库连接创建迁移执行器
+func NewMigrator() (*Migrator, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("数据库连接未初始化")
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := LoadMigrations(currentDialect.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         sqlDB,
+		dialect:    currentDialect,
+		migrations: migrations,
+	}, nil
+}
+
+// LoadMigrations 读取内嵌的迁移文件并按版本排序
+func LoadMigrations(driver string) ([]Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录 %s 失败: %v", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := migrationFileRe.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("迁移文件名格式不正确: %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("迁移版本 %d 存在多个名称: %s, %s", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("迁移 %04d_%s 缺少 up 或 down 文件", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest 最新的迁移版本
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status 获取所有迁移的状态
+func (m *Migrator) Status(ctx context.Context) ([]MigrationState, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		state := MigrationState{Version: mig.Version, Name: mig.Name}
+		if at, ok := applied[mig.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = &at
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// Pending 获取尚未执行的迁移
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Version 当前已执行的最高版本
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// Up 执行所有未执行的迁移，返回执行的数量
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.To(ctx, m.Latest())
+}
+
+// Down 回滚最近的 steps 个迁移，返回回滚的数量
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, nil
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.rollback(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// To 迁移到指定版本：高于当前版本时执行迁移，低于时回滚，0 表示回滚全部
+func (m *Migrator) To(ctx context.Context, target int64) (int, error) {
+	if target != 0 && !m.hasVersion(target) {
+		return 0, fmt.Errorf("迁移版本 %d 不存在", target)
+	}
+
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for v := range applied {
+			if !m.hasVersion(v) {
+				return fmt.Errorf("数据库中的迁移版本 %d 在当前程序中不存在，请升级程序", v)
+			}
+		}
+
+		// 回滚高于目标版本的迁移（从新到旧）
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > target {
+				if err := m.rollback(ctx, conn, mig); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+
+		// 执行不高于目标版本的迁移（从旧到新）
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= target {
+				if err := m.apply(ctx, conn, mig); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// hasVersion 是否存在指定版本的迁移
+func (m *Migrator) hasVersion(version int64) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock 在独占连接上持有迁移锁执行 fn
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.CreateVersionTable); err != nil {
+		return fmt.Errorf("创建 schema_migrations 表失败: %v", err)
+	}
+
+	if err := m.dialect.Lock(ctx, conn); err != nil {
+		return fmt.Errorf("获取迁移锁失败: %v", err)
+	}
+	defer m.dialect.Unlock(context.Background(), conn)
+
+	return fn(conn)
+}
+
+// querier 可执行查询的连接
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// applied 查询已执行的迁移版本及执行时间
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]time.Time, error) {
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		// 表不存在时视为没有执行过任何迁移
+		if !m.versionTableExists(ctx) {
+			return map[int64]time.Time{}, nil
+		}
+		return nil, fmt.Errorf("查询迁移记录失败: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// versionTableExists schema_migrations 表是否存在
+func (m *Migrator) versionTableExists(ctx context.Context) bool {
+	return DB.WithContext(ctx).Migrator().HasTable("schema_migrations")
+}
+
+// apply 执行单个迁移
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	start := time.Now()
+	insert := fmt.Sprintf("INSERT INTO schema_migrations (version, name, applied_at) VALUES (%s, %s, %s)",
+		m.dialect.Placeholder(1), m.dialect.Placeholder(2), m.dialect.Placeholder(3))
+
+	err := m.execInTx(ctx, conn, mig.Up, insert, mig.Version, mig.Name, time.Now())
+	if err != nil {
+		return fmt.Errorf("执行迁移 %04d_%s 失败: %v", mig.Version, mig.Name, err)
+	}
+
+	log.Printf("已执行迁移 %04d_%s (%v)", mig.Version, mig.Name, time.Since(start))
+	return nil
+}
+
+// rollback 回滚单个迁移
+func (m *Migrator) rollback(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	start := time.Now()
+	del := fmt.Sprintf("DELETE FROM schema_migrations WHERE version = %s", m.dialect.Placeholder(1))
+
+	if err := m.execInTx(ctx, conn, mig.Down, del, mig.Version); err != nil {
+		return fmt.Errorf("回滚迁移 %04d_%s 失败: %v", mig.Version, mig.Name, err)
+	}
+
+	log.Printf("已回滚迁移 %04d_%s (%v)", mig.Version, mig.Name, time.Since(start))
+	return nil
+}
+
+// execInTx 在事务中执行迁移脚本并更新版本记录
+// 注意：MySQL 的 DDL 语句会隐式提交事务，迁移中途失败时可能需要手动修复
+func (m *Migrator) execInTx(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%v\n语句: %s", err, stmt)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements 按行尾分号拆分 SQL 脚本，忽略 -- 注释行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			stmt := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, stmt)
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
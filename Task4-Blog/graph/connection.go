@@ -0,0 +1,286 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-system/config"
+	"blog-system/models"
+
+	"github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+)
+
+// cursor 连接中的位置：按 (created_at, id) 排序时两者都有效，只按 id 排序时 Time 为零值
+type cursor struct {
+	Time time.Time
+	ID   uint
+}
+
+// encodeCursor 将位置编码为不透明的游标字符串
+func encodeCursor(t time.Time, id uint) string {
+	var nanos int64
+	if !t.IsZero() {
+		nanos = t.UnixNano()
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", nanos, id)))
+}
+
+// decodeCursor 解析游标字符串
+func decodeCursor(s string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errBadInput("游标格式不正确")
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, errBadInput("游标格式不正确")
+	}
+	nanos, err1 := strconv.ParseInt(parts[0], 10, 64)
+	id, err2 := strconv.ParseUint(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, errBadInput("游标格式不正确")
+	}
+	c := &cursor{ID: uint(id)}
+	if nanos != 0 {
+		c.Time = time.Unix(0, nanos)
+	}
+	return c, nil
+}
+
+// ordering 连接的排序方式，游标分页依赖排序列唯一且稳定
+type ordering struct {
+	table  string
+	byTime bool // 先按 created_at 再按 id 排序，否则只按 id
+	desc   bool
+}
+
+// newestFirst 按创建时间倒序
+func newestFirst(table string) ordering { return ordering{table: table, byTime: true, desc: true} }
+
+// oldestFirst 按创建时间正序
+func oldestFirst(table string) ordering { return ordering{table: table, byTime: true} }
+
+// byID 按 ID 正序
+func byID(table string) ordering { return ordering{table: table} }
+
+// apply 为查询加上游标条件和排序
+func (o ordering) apply(q *gorm.DB, after *cursor) *gorm.DB {
+	dir, cmp := "ASC", ">"
+	if o.desc {
+		dir, cmp = "DESC", "<"
+	}
+	id := o.table + ".id"
+	created := o.table + ".created_at"
+
+	if after != nil {
+		if o.byTime {
+			q = q.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", created, cmp, created, id, cmp), after.Time, after.Time, after.ID)
+		} else {
+			q = q.Where(fmt.Sprintf("%s %s ?", id, cmp), after.ID)
+		}
+	}
+	if o.byTime {
+		q = q.Order(fmt.Sprintf("%s %s", created, dir))
+	}
+	return q.Order(fmt.Sprintf("%s %s", id, dir))
+}
+
+// cursorOf 返回记录在该排序下的游标
+func (o ordering) cursorOf(createdAt time.Time, id uint) string {
+	if !o.byTime {
+		createdAt = time.Time{}
+	}
+	return encodeCursor(createdAt, id)
+}
+
+// page 分页参数
+type page struct {
+	First int
+	After string
+}
+
+// pageFrom 读取 first/after 参数，未指定 first 时使用默认条数，超过上限时截断
+func (h *Handler) pageFrom(args map[string]interface{}) (page, *cursor, error) {
+	p := page{First: h.clampFirst(args["first"])}
+	if p.First < 0 {
+		return p, nil, errBadInput("first 不能为负数")
+	}
+
+	var after *cursor
+	if s, ok := args["after"].(string); ok && s != "" {
+		c, err := decodeCursor(s)
+		if err != nil {
+			return p, nil, err
+		}
+		p.After, after = s, c
+	}
+	return p, after, nil
+}
+
+// clampFirst 计算实际返回条数，深度/复杂度分析使用同样的规则
+func (h *Handler) clampFirst(v interface{}) int {
+	cfg := config.GetConfig().GraphQL
+	first, ok := v.(int)
+	switch {
+	case !ok:
+		return cfg.DefaultPageSize
+	case first > cfg.MaxPageSize:
+		return cfg.MaxPageSize
+	}
+	return first
+}
+
+// edge 连接中的一条记录
+type edge struct {
+	cursor string
+	node   interface{}
+}
+
+// connection Relay 风格的分页结果
+type connection struct {
+	edges       []edge
+	hasNextPage bool
+	count       func() (interface{}, error) // totalCount 被请求时才执行
+}
+
+// newConnection 由多取一条的查询结果构造连接
+func newConnection[T any](rows []T, first int, cursorOf func(*T) string, count func() (interface{}, error)) *connection {
+	conn := &connection{count: count}
+	if len(rows) > first {
+		rows = rows[:first]
+		conn.hasNextPage = true
+	}
+	conn.edges = make([]edge, len(rows))
+	for i := range rows {
+		conn.edges[i] = edge{cursor: cursorOf(&rows[i]), node: &rows[i]}
+	}
+	return conn
+}
+
+// connectionType 生成 XxxConnection 和 XxxEdge 类型
+func connectionType(name string, node *graphql.Object) *graphql.Object {
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(edge).cursor, nil
+			}},
+			"node": &graphql.Field{Type: graphql.NewNonNull(node), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(edge).node, nil
+			}},
+		},
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Connection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*connection).edges, nil
+			}},
+			"nodes": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(node))), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				edges := p.Source.(*connection).edges
+				nodes := make([]interface{}, len(edges))
+				for i, e := range edges {
+					nodes[i] = e.node
+				}
+				return nodes, nil
+			}},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*connection), nil
+			}},
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*connection).count, nil
+			}},
+		},
+	})
+}
+
+// pageInfoType 分页信息
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*connection).hasNextPage, nil
+		}},
+		"endCursor": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			edges := p.Source.(*connection).edges
+			if len(edges) == 0 {
+				return nil, nil
+			}
+			return edges[len(edges)-1].cursor, nil
+		}},
+	},
+})
+
+// batchKey 嵌套连接的加载键：父对象 ID、分页参数和过滤条件
+type batchKey struct {
+	Parent uint
+	Page   page
+	Filter string
+}
+
+// batchRow 带批次序号的查询结果，用于把 UNION ALL 的结果分配回各个键。
+// 需要为每种模型定义具名类型：GORM 无法为泛型类型解析多对多关联
+type batchRow[T any] interface {
+	row() T
+	batch() int
+}
+
+type postRow struct {
+	models.Post
+	BatchIdx int
+}
+
+func (r postRow) row() models.Post { return r.Post }
+func (r postRow) batch() int       { return r.BatchIdx }
+
+type commentRow struct {
+	models.Comment
+	BatchIdx int
+}
+
+func (r commentRow) row() models.Comment { return r.Comment }
+func (r commentRow) batch() int          { return r.BatchIdx }
+
+// fetchPages 为每个键取一页数据（多取一条用于判断是否有下一页），所有键合并为一条 UNION ALL 查询。
+// 不使用窗口函数，以兼容 MySQL 5.7；UNION 不保证顺序，结果按 ord 重新排序
+func fetchPages[T any, R batchRow[T]](db *gorm.DB, ord ordering, keys []batchKey, position func(*T) (time.Time, uint),
+	build func(q *gorm.DB, key batchKey) (*gorm.DB, error)) (map[batchKey][]T, error) {
+	parts := make([]string, 0, len(keys))
+	args := make([]interface{}, 0, len(keys))
+	for i, key := range keys {
+		q, err := build(db.Session(&gorm.Session{NewDB: true}), key)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, fmt.Sprintf("SELECT * FROM (?) AS page_%d", i))
+		args = append(args, q.Select(fmt.Sprintf("%s.*, %d AS batch_idx", ord.table, i)).Limit(key.Page.First+1))
+	}
+
+	var rows []R
+	if err := db.Raw(strings.Join(parts, " UNION ALL "), args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[batchKey][]T, len(keys))
+	for _, row := range rows {
+		key := keys[row.batch()]
+		result[key] = append(result[key], row.row())
+	}
+	for _, list := range result {
+		sort.SliceStable(list, func(i, j int) bool {
+			ti, idi := position(&list[i])
+			tj, idj := position(&list[j])
+			if ord.byTime && !ti.Equal(tj) {
+				return ti.Before(tj) != ord.desc
+			}
+			return (idi < idj) != ord.desc
+		})
+	}
+	return result, nil
+}
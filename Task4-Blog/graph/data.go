@@ -0,0 +1,251 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"blog-system/models"
+
+	"gorm.io/gorm"
+)
+
+// viewer 发起请求的用户，未登录时 ID 为 0
+type viewer struct {
+	ID     uint
+	Role   string
+	Scopes []string // 个人访问令牌的权限范围，登录 token 为 nil
+}
+
+func (v viewer) loggedIn() bool {
+	return v.ID != 0
+}
+
+// can 是否拥有权限范围，登录 token 拥有全部权限
+func (v viewer) can(scope models.TokenScope) bool {
+	if v.Scopes == nil {
+		return true
+	}
+	for _, s := range v.Scopes {
+		if s == string(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v viewer) admin() bool {
+	return v.Role == "admin"
+}
+
+// visiblePosts 只保留当前用户可见的文章：公开且已发布的文章和自己的文章，管理员可见全部。
+// 个人访问令牌需要 posts:read 权限才能看到自己未公开的文章
+func (v viewer) visiblePosts(q *gorm.DB) *gorm.DB {
+	switch {
+	case v.admin():
+		return q
+	case v.loggedIn() && v.can(models.ScopePostsRead):
+		return q.Where("((posts.status = ? AND posts.is_public = ?) OR posts.user_id = ?)", models.PostStatusPublished, true, v.ID)
+	default:
+		return q.Where("posts.status = ? AND posts.is_public = ?", models.PostStatusPublished, true)
+	}
+}
+
+// visibleComments 只保留审核通过的评论，管理员可见全部
+func (v viewer) visibleComments(q *gorm.DB) *gorm.DB {
+	if v.admin() {
+		return q
+	}
+	return q.Where("comments.is_approved = ?", true)
+}
+
+// loaders 一次请求内的批量加载器
+type loaders struct {
+	users    *loader[uint, *models.User]
+	posts    *loader[uint, *models.Post]
+	comments *loader[uint, *models.Comment]
+	postTags *loader[uint, []models.Tag]
+
+	userPosts    *loader[batchKey, []models.Post]
+	tagPosts     *loader[batchKey, []models.Post]
+	postComments *loader[batchKey, []models.Comment]
+	replies      *loader[batchKey, []models.Comment]
+
+	userPostCount    *loader[batchKey, int64]
+	tagPostCount     *loader[batchKey, int64]
+	postCommentCount *loader[batchKey, int64]
+	replyCount       *loader[batchKey, int64]
+}
+
+// requestState 请求级状态，通过 context 传给解析函数
+type requestState struct {
+	viewer  viewer
+	loaders *loaders
+}
+
+type stateKey struct{}
+
+// stateFrom 从 context 取出请求级状态
+func stateFrom(ctx context.Context) *requestState {
+	return ctx.Value(stateKey{}).(*requestState)
+}
+
+// newLoaders 创建批量加载器，可见性规则在加载时应用
+func newLoaders(db *gorm.DB, v viewer) *loaders {
+	return &loaders{
+		users: newLoader(func(ids []uint) (map[uint]*models.User, error) {
+			var users []models.User
+			if err := db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint]*models.User, len(users))
+			for i := range users {
+				result[users[i].ID] = &users[i]
+			}
+			return result, nil
+		}),
+
+		posts: newLoader(func(ids []uint) (map[uint]*models.Post, error) {
+			var posts []models.Post
+			if err := v.visiblePosts(db.Model(&models.Post{})).Where("posts.id IN ?", ids).Find(&posts).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint]*models.Post, len(posts))
+			for i := range posts {
+				result[posts[i].ID] = &posts[i]
+			}
+			return result, nil
+		}),
+
+		comments: newLoader(func(ids []uint) (map[uint]*models.Comment, error) {
+			var comments []models.Comment
+			if err := v.visibleComments(db.Model(&models.Comment{})).Where("comments.id IN ?", ids).Find(&comments).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint]*models.Comment, len(comments))
+			for i := range comments {
+				result[comments[i].ID] = &comments[i]
+			}
+			return result, nil
+		}),
+
+		postTags: newLoader(func(ids []uint) (map[uint][]models.Tag, error) {
+			var rows []struct {
+				Tag    models.Tag `gorm:"embedded"`
+				PostID uint
+			}
+			if err := db.Table("tags").
+				Select("tags.*, post_tags.post_id AS post_id").
+				Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+				Where("post_tags.post_id IN ?", ids).
+				Order("tags.name ASC").
+				Scan(&rows).Error; err != nil {
+				return nil, err
+			}
+			result := make(map[uint][]models.Tag, len(ids))
+			for _, row := range rows {
+				result[row.PostID] = append(result[row.PostID], row.Tag)
+			}
+			return result, nil
+		}),
+
+		userPosts:        newLoader(pagesOf[models.Post, postRow](db, userPosts(v), postPosition)),
+		userPostCount:    newLoader(countsOf(db, userPosts(v))),
+		tagPosts:         newLoader(pagesOf[models.Post, postRow](db, tagPosts(v), postPosition)),
+		tagPostCount:     newLoader(countsOf(db, tagPosts(v))),
+		postComments:     newLoader(pagesOf[models.Comment, commentRow](db, postComments(v), commentPosition)),
+		postCommentCount: newLoader(countsOf(db, postComments(v))),
+		replies:          newLoader(pagesOf[models.Comment, commentRow](db, replies(v), commentPosition)),
+		replyCount:       newLoader(countsOf(db, replies(v))),
+	}
+}
+
+// relation 嵌套连接的查询方式
+type relation struct {
+	order  ordering
+	parent string                                   // 父对象 ID 所在的列
+	base   func(q *gorm.DB, filter string) *gorm.DB // 不含父对象条件的查询
+}
+
+// userPosts 用户的文章，filter 为文章状态
+func userPosts(v viewer) relation {
+	return relation{order: newestFirst("posts"), parent: "posts.user_id", base: func(q *gorm.DB, filter string) *gorm.DB {
+		q = v.visiblePosts(q.Model(&models.Post{}))
+		if filter != "" {
+			q = q.Where("posts.status = ?", filter)
+		}
+		return q
+	}}
+}
+
+// tagPosts 标签下的文章，通过关联表按标签分组
+func tagPosts(v viewer) relation {
+	return relation{order: newestFirst("posts"), parent: "post_tags.tag_id", base: func(q *gorm.DB, filter string) *gorm.DB {
+		return v.visiblePosts(q.Model(&models.Post{}).Joins("JOIN post_tags ON post_tags.post_id = posts.id"))
+	}}
+}
+
+// postComments 文章的顶级评论，最新的在前
+func postComments(v viewer) relation {
+	return relation{order: newestFirst("comments"), parent: "comments.post_id", base: func(q *gorm.DB, filter string) *gorm.DB {
+		return v.visibleComments(q.Model(&models.Comment{})).Where("comments.parent_id IS NULL")
+	}}
+}
+
+// replies 评论的直接回复，按时间正序
+func replies(v viewer) relation {
+	return relation{order: oldestFirst("comments"), parent: "comments.parent_id", base: func(q *gorm.DB, filter string) *gorm.DB {
+		return v.visibleComments(q.Model(&models.Comment{}))
+	}}
+}
+
+// pagesOf 返回按父对象分页加载的查询函数
+func pagesOf[T any, R batchRow[T]](db *gorm.DB, rel relation, position func(*T) (time.Time, uint)) func(keys []batchKey) (map[batchKey][]T, error) {
+	return func(keys []batchKey) (map[batchKey][]T, error) {
+		return fetchPages[T, R](db, rel.order, keys, position, func(q *gorm.DB, key batchKey) (*gorm.DB, error) {
+			var after *cursor
+			if key.Page.After != "" {
+				c, err := decodeCursor(key.Page.After)
+				if err != nil {
+					return nil, err
+				}
+				after = c
+			}
+			q = rel.base(q, key.Filter).Where(rel.parent+" = ?", key.Parent)
+			return rel.order.apply(q, after), nil
+		})
+	}
+}
+
+// countsOf 返回按父对象计数的查询函数，键中的分页参数被忽略
+func countsOf(db *gorm.DB, rel relation) func(keys []batchKey) (map[batchKey]int64, error) {
+	return func(keys []batchKey) (map[batchKey]int64, error) {
+		return countBy(db, rel, keys)
+	}
+}
+
+// countBy 按父对象分组计数，过滤条件相同的键合并为一条查询
+func countBy(db *gorm.DB, rel relation, keys []batchKey) (map[batchKey]int64, error) {
+	groups := make(map[string][]uint)
+	for _, key := range keys {
+		groups[key.Filter] = append(groups[key.Filter], key.Parent)
+	}
+
+	result := make(map[batchKey]int64, len(keys))
+	for filter, parents := range groups {
+		var rows []struct {
+			Parent uint
+			Total  int64
+		}
+		if err := rel.base(db.Session(&gorm.Session{NewDB: true}), filter).
+			Select(rel.parent+" AS parent, COUNT(*) AS total").
+			Where(rel.parent+" IN ?", parents).
+			Group(rel.parent).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			result[batchKey{Parent: row.Parent, Filter: filter}] = row.Total
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package graph
+
+import "fmt"
+
+// 错误码，输出在 errors[].extensions.code 中
+const (
+	CodeBadInput        = "BAD_USER_INPUT"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeNotFound        = "NOT_FOUND"
+	CodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+	CodeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+// Error 带错误码的 GraphQL 错误
+type Error struct {
+	Message string
+	Code    string
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions 由 graphql-go 写入错误的 extensions 字段
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+func errBadInput(message string) error {
+	return &Error{Message: message, Code: CodeBadInput}
+}
+
+func errUnauthenticated() error {
+	return &Error{Message: "未授权: 请先登录", Code: CodeUnauthenticated}
+}
+
+func errForbidden(message string) error {
+	return &Error{Message: "权限不足: " + message, Code: CodeForbidden}
+}
+
+func errNotFound(message string) error {
+	return &Error{Message: message, Code: CodeNotFound}
+}
+
+func errInternal(message string, err error) error {
+	return &Error{Message: fmt.Sprintf("%s: %v", message, err), Code: CodeInternal}
+}
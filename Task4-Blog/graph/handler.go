@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"blog-system/database"
+	"blog-system/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"gorm.io/gorm"
+)
+
+// Request GraphQL 请求
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response GraphQL 响应，错误不使用统一响应结构
+type Response struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// Handler GraphQL 接口
+type Handler struct {
+	db             *gorm.DB
+	postService    *services.PostService
+	commentService *services.CommentService
+	userService    *services.UserService
+	schema         graphql.Schema
+}
+
+// NewHandler 创建 GraphQL 接口实例
+func NewHandler(postService *services.PostService, commentService *services.CommentService, userService *services.UserService) *Handler {
+	h := &Handler{
+		db:             database.GetDB(),
+		postService:    postService,
+		commentService: commentService,
+		userService:    userService,
+	}
+	schema, err := h.buildSchema()
+	if err != nil {
+		panic(fmt.Sprintf("GraphQL schema 定义错误: %v", err))
+	}
+	h.schema = schema
+	return h
+}
+
+// Serve 执行 GraphQL 请求。GET 请求只能执行查询，变更必须使用 POST
+func (h *Handler) Serve(c *gin.Context) {
+	var req Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if vars := c.Query("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				h.fail(c, http.StatusBadRequest, errBadInput("variables 不是合法的 JSON"))
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		h.fail(c, http.StatusBadRequest, errBadInput("请求参数错误: "+err.Error()))
+		return
+	}
+	if req.Query == "" {
+		h.fail(c, http.StatusBadRequest, errBadInput("缺少 query"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	if result := graphql.ValidateDocument(&h.schema, doc, nil); !result.IsValid {
+		c.JSON(http.StatusBadRequest, Response{Errors: result.Errors})
+		return
+	}
+
+	op, err := operation(doc, req.OperationName)
+	if err != nil {
+		h.fail(c, http.StatusBadRequest, err)
+		return
+	}
+	if c.Request.Method == http.MethodGet && op.Operation != ast.OperationTypeQuery {
+		h.fail(c, http.StatusMethodNotAllowed, errBadInput("GET 请求只能执行查询，变更请使用 POST"))
+		return
+	}
+	if err := h.checkLimits(doc, op, req.Variables); err != nil {
+		h.fail(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// 认证信息来自 OptionalAuth 中间件
+	var v viewer
+	if userID, exists := c.Get("userID"); exists {
+		v.ID = userID.(uint)
+		v.Role = c.GetString("role")
+		// 角色要求两步验证但登录时未通过的，按普通用户处理
+		if services.TwoFactorRequired(v.Role) && !c.GetBool("twoFactor") {
+			v.Role = ""
+		}
+		// 个人访问令牌不能行使角色的权限，变更按权限范围检查
+		if scopes, ok := c.Get("scopes"); ok {
+			v.Role = ""
+			v.Scopes = scopes.([]string)
+		}
+	}
+	ctx := context.WithValue(c.Request.Context(), stateKey{}, &requestState{viewer: v, loaders: newLoaders(h.db, v)})
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	c.JSON(http.StatusOK, Response{Data: result.Data, Errors: result.Errors})
+}
+
+// fail 返回只包含错误的响应
+func (h *Handler) fail(c *gin.Context, status int, err error) {
+	c.JSON(status, Response{Errors: gqlerrors.FormatErrors(&gqlerrors.Error{Message: err.Error(), Locations: []location.SourceLocation{}, OriginalError: err})})
+}
+
+// operation 按名称选出要执行的操作，文档只有一个操作时可以不指定名称
+func operation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil, errBadInput("文档包含多个操作时必须指定 operationName")
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			found = op
+		}
+	}
+	if found == nil {
+		return nil, errBadInput(fmt.Sprintf("未找到操作 %q", name))
+	}
+	return found, nil
+}
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"blog-system/config"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// analysis 在执行前估算查询的深度和复杂度。
+// 每个字段计 1；连接字段（返回 XxxConnection）的子选择按实际返回条数 first 成倍计算，
+// first 的默认值和上限与解析函数一致。内省字段（__schema 等）不计入
+type analysis struct {
+	h         *Handler
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	defaults  map[string]ast.Value
+}
+
+// checkLimits 检查操作是否超过深度和复杂度限制
+func (h *Handler) checkLimits(doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) error {
+	a := &analysis{
+		h:         h,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		defaults:  make(map[string]ast.Value),
+	}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			a.fragments[frag.Name.Value] = frag
+		}
+	}
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil {
+			a.defaults[def.Variable.Name.Value] = def.DefaultValue
+		}
+	}
+
+	root := h.schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = h.schema.MutationType()
+	}
+
+	depth, complexity := a.selectionSet(root, op.SelectionSet, 1)
+	cfg := config.GetConfig().GraphQL
+	if depth > cfg.MaxDepth {
+		return &Error{Message: fmt.Sprintf("查询嵌套深度 %d 超过上限 %d", depth, cfg.MaxDepth), Code: CodeQueryTooComplex}
+	}
+	if complexity > cfg.MaxComplexity {
+		return &Error{Message: fmt.Sprintf("查询复杂度 %d 超过上限 %d", complexity, cfg.MaxComplexity), Code: CodeQueryTooComplex}
+	}
+	return nil
+}
+
+// selectionSet 返回选择集的最大深度和复杂度，depth 为其中字段所在的层级
+func (a *analysis) selectionSet(parent *graphql.Object, set *ast.SelectionSet, depth int) (int, int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	maxDepth, complexity := 0, 0
+	merge := func(d, c int) {
+		if d > maxDepth {
+			maxDepth = d
+		}
+		complexity += c
+	}
+
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			merge(a.field(parent, s, depth))
+		case *ast.InlineFragment:
+			merge(a.selectionSet(a.fragmentType(parent, s.TypeCondition), s.SelectionSet, depth))
+		case *ast.FragmentSpread:
+			// 文档已通过校验，不会出现循环引用
+			if frag, ok := a.fragments[s.Name.Value]; ok {
+				merge(a.selectionSet(a.fragmentType(parent, frag.TypeCondition), frag.SelectionSet, depth))
+			}
+		}
+	}
+	return maxDepth, complexity
+}
+
+// field 计算单个字段的深度和复杂度
+func (a *analysis) field(parent *graphql.Object, f *ast.Field, depth int) (int, int) {
+	if strings.HasPrefix(f.Name.Value, "__") {
+		return 0, 0
+	}
+	def, ok := parent.Fields()[f.Name.Value]
+	if !ok {
+		return depth, 1
+	}
+
+	child, _ := graphql.GetNamed(def.Type).(*graphql.Object)
+	childDepth, childComplexity := a.selectionSet(child, f.SelectionSet, depth+1)
+	if childDepth < depth {
+		childDepth = depth
+	}
+
+	multiplier := 1
+	if child != nil && strings.HasSuffix(child.Name(), "Connection") {
+		multiplier = a.first(f)
+	}
+	return childDepth, 1 + multiplier*childComplexity
+}
+
+// first 连接字段实际返回的条数
+func (a *analysis) first(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		value := arg.Value
+		if v, ok := value.(*ast.Variable); ok {
+			if raw, ok := a.variables[v.Name.Value]; ok {
+				return a.h.clampFirst(intValue(raw))
+			}
+			value = a.defaults[v.Name.Value]
+		}
+		if iv, ok := value.(*ast.IntValue); ok {
+			n, _ := strconv.Atoi(iv.Value)
+			return a.h.clampFirst(n)
+		}
+	}
+	return a.h.clampFirst(nil)
+}
+
+// fragmentType 片段的类型条件对应的对象类型
+func (a *analysis) fragmentType(parent *graphql.Object, cond *ast.Named) *graphql.Object {
+	if cond == nil {
+		return parent
+	}
+	if t, ok := a.h.schema.Type(cond.Name.Value).(*graphql.Object); ok {
+		return t
+	}
+	return parent
+}
+
+// intValue 将 JSON 解码得到的变量值转换为 int，无法转换时返回 nil
+func intValue(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return int(i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package graph
+
+import "sync"
+
+// loader 按请求合并同一层级字段的查询。
+// Load 只登记键并返回 thunk；graphql-go 按层级广度优先地对 thunk 求值，
+// 因此第一个 thunk 被调用时，同层所有兄弟字段的键都已登记，可以一次查询完成
+type loader[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(keys []K) (map[K]V, error)
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+// newLoader 创建加载器，fetch 返回的结果中缺少的键视为零值
+func newLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: make(map[K]bool),
+		values: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load 登记键，返回的函数在首次调用时批量查询所有已登记但未加载的键
+func (l *loader[K, V]) Load(key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, done := l.values[key]; !done {
+			if _, failed := l.errs[key]; !failed {
+				l.flush()
+			}
+		}
+		return l.values[key], l.errs[key]
+	}
+}
+
+// flush 查询所有待加载的键
+func (l *loader[K, V]) flush() {
+	keys := l.pending
+	l.pending = nil
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.values[key] = values[key]
+	}
+}
+
+// thunk 将加载结果转换为 graphql-go 识别的延迟求值函数
+func thunk[V any](load func() (V, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		return load()
+	}
+}
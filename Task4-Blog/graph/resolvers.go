@@ -0,0 +1,439 @@
+package graph
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"blog-system/controllers"
+	"blog-system/models"
+	"blog-system/services"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+)
+
+// parseID 解析 ID 参数
+func parseID(v interface{}) (uint, error) {
+	s, _ := v.(string)
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || id == 0 {
+		return 0, errBadInput("ID格式不正确")
+	}
+	return uint(id), nil
+}
+
+// optionalID 解析可选的 ID 参数，未提供时返回 nil
+func optionalID(v interface{}) (*uint, error) {
+	if v == nil {
+		return nil, nil
+	}
+	id, err := parseID(v)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// validate 使用与 REST 接口相同的 binding 规则校验请求
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return errBadInput("请求参数错误: " + err.Error())
+	}
+	return nil
+}
+
+// listPage 执行顶层连接查询，totalCount 被请求时才计数
+func listPage[T any](base func() *gorm.DB, ord ordering, pg page, after *cursor, position func(*T) (time.Time, uint)) (*connection, error) {
+	var rows []T
+	if err := ord.apply(base(), after).Limit(pg.First + 1).Find(&rows).Error; err != nil {
+		return nil, errInternal("查询失败", err)
+	}
+	cursorOf := func(row *T) string { return ord.cursorOf(position(row)) }
+	return newConnection(rows, pg.First, cursorOf, func() (interface{}, error) {
+		var total int64
+		if err := base().Count(&total).Error; err != nil {
+			return nil, errInternal("查询失败", err)
+		}
+		return total, nil
+	}), nil
+}
+
+// 连接中记录的排序位置
+func postPosition(p *models.Post) (time.Time, uint)       { return p.CreatedAt, p.ID }
+func commentPosition(c *models.Comment) (time.Time, uint) { return c.CreatedAt, c.ID }
+func userPosition(u *models.User) (time.Time, uint)       { return u.CreatedAt, u.ID }
+func tagPosition(t *models.Tag) (time.Time, uint)         { return t.CreatedAt, t.ID }
+
+// nestedConnection 登记父对象的分页和计数请求，返回延迟求值的连接
+func nestedConnection[T any](h *Handler, p graphql.ResolveParams, parent uint, filter string,
+	pages *loader[batchKey, []T], counts *loader[batchKey, int64], ord ordering, position func(*T) (time.Time, uint)) (interface{}, error) {
+	pg, _, err := h.pageFrom(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	load := pages.Load(batchKey{Parent: parent, Page: pg, Filter: filter})
+	countKey := batchKey{Parent: parent, Filter: filter}
+
+	return func() (interface{}, error) {
+		rows, err := load()
+		if err != nil {
+			return nil, errInternal("查询失败", err)
+		}
+		cursorOf := func(row *T) string { return ord.cursorOf(position(row)) }
+		return newConnection(rows, pg.First, cursorOf, thunk(counts.Load(countKey))), nil
+	}, nil
+}
+
+// 查询
+
+func (h *Handler) resolveViewer(p graphql.ResolveParams) (interface{}, error) {
+	state := stateFrom(p.Context)
+	if !state.viewer.loggedIn() {
+		return nil, nil
+	}
+	return thunk(state.loaders.users.Load(state.viewer.ID)), nil
+}
+
+func (h *Handler) resolveUser(p graphql.ResolveParams) (interface{}, error) {
+	if username, ok := p.Args["username"].(string); ok {
+		user, err := h.userService.GetUserByUsername(username)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return user, err
+	}
+	if p.Args["id"] == nil {
+		return nil, errBadInput("需要提供 id 或 username")
+	}
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	return thunk(stateFrom(p.Context).loaders.users.Load(id)), nil
+}
+
+func (h *Handler) resolveUsers(p graphql.ResolveParams) (interface{}, error) {
+	if v := stateFrom(p.Context).viewer; !v.admin() {
+		if !v.loggedIn() {
+			return nil, errUnauthenticated()
+		}
+		return nil, errForbidden("需要管理员权限")
+	}
+	pg, after, err := h.pageFrom(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	return listPage(func() *gorm.DB { return h.db.Model(&models.User{}) }, byID("users"), pg, after, userPosition)
+}
+
+func (h *Handler) resolvePost(p graphql.ResolveParams) (interface{}, error) {
+	state := stateFrom(p.Context)
+
+	var post *models.Post
+	if slug, ok := p.Args["slug"].(string); ok {
+		var found models.Post
+		err := state.viewer.visiblePosts(h.db.Model(&models.Post{})).Where("posts.slug = ?", slug).First(&found).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return nil, errInternal("查询文章失败", err)
+		}
+		post = &found
+	} else {
+		if p.Args["id"] == nil {
+			return nil, errBadInput("需要提供 id 或 slug")
+		}
+		id, err := parseID(p.Args["id"])
+		if err != nil {
+			return nil, err
+		}
+		if post, err = state.loaders.posts.Load(id)(); err != nil {
+			return nil, errInternal("查询文章失败", err)
+		}
+		if post == nil {
+			return nil, nil
+		}
+	}
+
+	// 与 REST 接口一致，访问文章详情时增加阅读次数
+	h.postService.IncrementViewCount(post.ID)
+	return post, nil
+}
+
+func (h *Handler) resolvePosts(p graphql.ResolveParams) (interface{}, error) {
+	state := stateFrom(p.Context)
+	pg, after, err := h.pageFrom(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	status, _ := p.Args["status"].(models.PostStatus)
+	tag, _ := p.Args["tag"].(string)
+	base := func() *gorm.DB {
+		q := state.viewer.visiblePosts(h.db.Model(&models.Post{}))
+		if status != "" {
+			q = q.Where("posts.status = ?", status)
+		}
+		if tag != "" {
+			tagged := h.db.Table("post_tags").Select("post_tags.post_id").
+				Joins("JOIN tags ON tags.id = post_tags.tag_id").Where("tags.slug = ?", tag)
+			q = q.Where("posts.id IN (?)", tagged)
+		}
+		return q
+	}
+	return listPage(base, newestFirst("posts"), pg, after, postPosition)
+}
+
+func (h *Handler) resolveComment(p graphql.ResolveParams) (interface{}, error) {
+	id, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	loaders := stateFrom(p.Context).loaders
+	comment, err := loaders.comments.Load(id)()
+	if err != nil {
+		return nil, errInternal("查询评论失败", err)
+	}
+	if comment == nil {
+		return nil, nil
+	}
+
+	// 所属文章不可见时评论也不可见
+	post, err := loaders.posts.Load(comment.PostID)()
+	if err != nil {
+		return nil, errInternal("查询文章失败", err)
+	}
+	if post == nil {
+		return nil, nil
+	}
+	return comment, nil
+}
+
+func (h *Handler) resolveTag(p graphql.ResolveParams) (interface{}, error) {
+	var tag models.Tag
+	err := h.db.Where("slug = ?", p.Args["slug"]).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errInternal("查询标签失败", err)
+	}
+	return &tag, nil
+}
+
+func (h *Handler) resolveTags(p graphql.ResolveParams) (interface{}, error) {
+	pg, after, err := h.pageFrom(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	return listPage(func() *gorm.DB { return h.db.Model(&models.Tag{}) }, byID("tags"), pg, after, tagPosition)
+}
+
+// 嵌套连接
+
+func (h *Handler) resolveUserPosts(p graphql.ResolveParams) (interface{}, error) {
+	status, _ := p.Args["status"].(models.PostStatus)
+	l := stateFrom(p.Context).loaders
+	return nestedConnection(h, p, p.Source.(*models.User).ID, string(status), l.userPosts, l.userPostCount, newestFirst("posts"), postPosition)
+}
+
+func (h *Handler) resolveTagPosts(p graphql.ResolveParams) (interface{}, error) {
+	l := stateFrom(p.Context).loaders
+	return nestedConnection(h, p, p.Source.(*models.Tag).ID, "", l.tagPosts, l.tagPostCount, newestFirst("posts"), postPosition)
+}
+
+func (h *Handler) resolvePostComments(p graphql.ResolveParams) (interface{}, error) {
+	l := stateFrom(p.Context).loaders
+	return nestedConnection(h, p, p.Source.(*models.Post).ID, "", l.postComments, l.postCommentCount, newestFirst("comments"), commentPosition)
+}
+
+func (h *Handler) resolveReplies(p graphql.ResolveParams) (interface{}, error) {
+	l := stateFrom(p.Context).loaders
+	return nestedConnection(h, p, p.Source.(*models.Comment).ID, "", l.replies, l.replyCount, oldestFirst("comments"), commentPosition)
+}
+
+// 变更，复用 REST 接口的服务和校验规则
+
+// currentUser 返回当前登录用户，未登录或个人访问令牌缺少权限范围时返回错误
+func currentUser(p graphql.ResolveParams, scope models.TokenScope) (viewer, error) {
+	v := stateFrom(p.Context).viewer
+	if !v.loggedIn() {
+		return v, errUnauthenticated()
+	}
+	if !v.can(scope) {
+		return v, errForbidden("访问令牌缺少 " + string(scope) + " 权限")
+	}
+	return v, nil
+}
+
+func (h *Handler) createPost(p graphql.ResolveParams) (interface{}, error) {
+	v, err := currentUser(p, models.ScopePostsWrite)
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]interface{})
+	req := controllers.CreatePostRequest{
+		Title:   stringArg(input, "title"),
+		Content: stringArg(input, "content"),
+		Summary: stringArg(input, "summary"),
+		Slug:    stringArg(input, "slug"),
+	}
+	req.Status, _ = input["status"].(models.PostStatus)
+	if isPublic, ok := input["isPublic"].(bool); ok {
+		req.IsPublic = isPublic
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	post, err := h.postService.CreatePost(p.Context, v.ID, req.Title, req.Content, req.Summary, req.Slug, req.Status, req.IsPublic)
+	if err != nil {
+		return nil, errInternal("创建文章失败", err)
+	}
+	return post, nil
+}
+
+func (h *Handler) updatePost(p graphql.ResolveParams) (interface{}, error) {
+	v, err := currentUser(p, models.ScopePostsWrite)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]interface{})
+	req := controllers.UpdatePostRequest{
+		Title:   stringArg(input, "title"),
+		Content: stringArg(input, "content"),
+		Summary: stringArg(input, "summary"),
+		Slug:    stringArg(input, "slug"),
+	}
+	req.Status, _ = input["status"].(models.PostStatus)
+	if isPublic, ok := input["isPublic"].(bool); ok {
+		req.IsPublic = &isPublic
+	}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	if !h.postService.IsPostOwner(postID, v.ID) {
+		return nil, errForbidden("只能修改自己的文章")
+	}
+	post, err := h.postService.UpdatePost(p.Context, postID, req.Title, req.Content, req.Summary, req.Slug, req.Status, req.IsPublic)
+	if err != nil {
+		return nil, errInternal("更新文章失败", err)
+	}
+	return post, nil
+}
+
+func (h *Handler) deletePost(p graphql.ResolveParams) (interface{}, error) {
+	v, err := currentUser(p, models.ScopePostsWrite)
+	if err != nil {
+		return nil, err
+	}
+	postID, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	if !h.postService.IsPostOwner(postID, v.ID) {
+		return nil, errForbidden("只能删除自己的文章")
+	}
+	if err := h.postService.DeletePost(p.Context, postID); err != nil {
+		return nil, errInternal("删除文章失败", err)
+	}
+	return true, nil
+}
+
+func (h *Handler) createComment(p graphql.ResolveParams) (interface{}, error) {
+	v, err := currentUser(p, models.ScopeCommentsWrite)
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]interface{})
+	postID, err := parseID(input["postId"])
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := optionalID(input["parentId"])
+	if err != nil {
+		return nil, err
+	}
+	req := controllers.CreateCommentRequest{Content: stringArg(input, "content"), PostID: postID, ParentID: parentID}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	// 只能评论自己可见的文章
+	post, err := stateFrom(p.Context).loaders.posts.Load(postID)()
+	if err != nil {
+		return nil, errInternal("查询文章失败", err)
+	}
+	if post == nil {
+		return nil, errNotFound("文章不存在")
+	}
+
+	comment, err := h.commentService.CreateComment(p.Context, v.ID, req.PostID, req.Content, req.ParentID)
+	if err != nil {
+		if errors.Is(err, services.ErrCommentParentNotFound) || errors.Is(err, services.ErrCommentParentMismatch) {
+			return nil, errBadInput(err.Error())
+		}
+		return nil, errInternal("创建评论失败", err)
+	}
+	return comment, nil
+}
+
+func (h *Handler) updateComment(p graphql.ResolveParams) (interface{}, error) {
+	v, err := currentUser(p, models.ScopeCommentsWrite)
+	if err != nil {
+		return nil, err
+	}
+	commentID, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	req := controllers.UpdateCommentRequest{Content: stringArg(p.Args, "content")}
+	if err := validate(&req); err != nil {
+		return nil, err
+	}
+
+	if !h.commentService.IsCommentOwner(commentID, v.ID) {
+		return nil, errForbidden("只能修改自己的评论")
+	}
+	comment, err := h.commentService.UpdateComment(p.Context, commentID, req.Content)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, errNotFound("评论不存在")
+		case errors.Is(err, services.ErrCommentEditWindow):
+			return nil, errForbidden(err.Error())
+		}
+		return nil, errInternal("更新评论失败", err)
+	}
+	return comment, nil
+}
+
+func (h *Handler) deleteComment(p graphql.ResolveParams) (interface{}, error) {
+	v, err := currentUser(p, models.ScopeCommentsWrite)
+	if err != nil {
+		return nil, err
+	}
+	commentID, err := parseID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	if !h.commentService.IsCommentOwner(commentID, v.ID) {
+		return nil, errForbidden("只能删除自己的评论")
+	}
+	if err := h.commentService.DeleteComment(p.Context, commentID); err != nil {
+		return nil, errInternal("删除评论失败", err)
+	}
+	return true, nil
+}
+
+// stringArg 读取可选的字符串参数
+func stringArg(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
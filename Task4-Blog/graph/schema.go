@@ -0,0 +1,316 @@
+package graph
+
+import (
+	"blog-system/models"
+
+	"github.com/graphql-go/graphql"
+)
+
+// connectionArgs 连接字段的分页参数
+var connectionArgs = graphql.FieldConfigArgument{
+	"first": &graphql.ArgumentConfig{Type: graphql.Int, Description: "返回条数，默认和上限见 graphql 配置"},
+	"after": &graphql.ArgumentConfig{Type: graphql.String, Description: "从该游标之后开始"},
+}
+
+// withArgs 在分页参数基础上增加其他参数
+func withArgs(extra graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{}
+	for name, arg := range connectionArgs {
+		args[name] = arg
+	}
+	for name, arg := range extra {
+		args[name] = arg
+	}
+	return args
+}
+
+var postStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "PostStatus",
+	Values: graphql.EnumValueConfigMap{
+		"DRAFT":     &graphql.EnumValueConfig{Value: models.PostStatusDraft, Description: "草稿"},
+		"PUBLISHED": &graphql.EnumValueConfig{Value: models.PostStatusPublished, Description: "已发布"},
+		"ARCHIVED":  &graphql.EnumValueConfig{Value: models.PostStatusArchived, Description: "已归档"},
+	},
+})
+
+// field 从来源对象读取简单字段
+func field[T any](t graphql.Output, get func(*T) interface{}) *graphql.Field {
+	return &graphql.Field{Type: t, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*T)), nil
+	}}
+}
+
+// buildSchema 构建 GraphQL schema
+func (h *Handler) buildSchema() (graphql.Schema, error) {
+	nonNullString := graphql.NewNonNull(graphql.String)
+	nonNullInt := graphql.NewNonNull(graphql.Int)
+	nonNullID := graphql.NewNonNull(graphql.ID)
+	nonNullTime := graphql.NewNonNull(graphql.DateTime)
+
+	var userType, postType, commentType, tagType *graphql.Object
+	var userConnection, postConnection, commentConnection, tagConnection *graphql.Object
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "用户",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":       field(nonNullID, func(u *models.User) interface{} { return u.ID }),
+				"username": field(nonNullString, func(u *models.User) interface{} { return u.Username }),
+				"email": {Type: graphql.String, Description: "仅本人和管理员可见，个人访问令牌需要 profile:read 权限", Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					u := p.Source.(*models.User)
+					if v := stateFrom(p.Context).viewer; (v.ID == u.ID && v.can(models.ScopeProfileRead)) || v.admin() {
+						return u.Email, nil
+					}
+					return nil, nil
+				}},
+				"bio":       field(nonNullString, func(u *models.User) interface{} { return u.Bio }),
+				"avatar":    field(nonNullString, func(u *models.User) interface{} { return u.Avatar }),
+				"role":      field(nonNullString, func(u *models.User) interface{} { return u.Role }),
+				"postCount": field(nonNullInt, func(u *models.User) interface{} { return u.PostCount }),
+				"createdAt": field(nonNullTime, func(u *models.User) interface{} { return u.CreatedAt }),
+				"posts": {
+					Type:        graphql.NewNonNull(postConnection),
+					Description: "用户的文章，本人可看到草稿和私密文章",
+					Args:        withArgs(graphql.FieldConfigArgument{"status": &graphql.ArgumentConfig{Type: postStatusEnum}}),
+					Resolve:     h.resolveUserPosts,
+				},
+			}
+		}),
+	})
+
+	postType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Post",
+		Description: "文章",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           field(nonNullID, func(p *models.Post) interface{} { return p.ID }),
+				"title":        field(nonNullString, func(p *models.Post) interface{} { return p.Title }),
+				"slug":         field(nonNullString, func(p *models.Post) interface{} { return p.Slug }),
+				"summary":      field(nonNullString, func(p *models.Post) interface{} { return p.Summary }),
+				"content":      field(nonNullString, func(p *models.Post) interface{} { return p.Content }),
+				"status":       field(graphql.NewNonNull(postStatusEnum), func(p *models.Post) interface{} { return p.Status }),
+				"isPublic":     field(graphql.NewNonNull(graphql.Boolean), func(p *models.Post) interface{} { return p.IsPublic }),
+				"viewCount":    field(nonNullInt, func(p *models.Post) interface{} { return p.ViewCount }),
+				"likeCount":    field(nonNullInt, func(p *models.Post) interface{} { return p.LikeCount }),
+				"commentCount": field(nonNullInt, func(p *models.Post) interface{} { return p.CommentCount }),
+				"publishedAt":  field(graphql.DateTime, func(p *models.Post) interface{} { return p.PublishedAt }),
+				"createdAt":    field(nonNullTime, func(p *models.Post) interface{} { return p.CreatedAt }),
+				"updatedAt":    field(nonNullTime, func(p *models.Post) interface{} { return p.UpdatedAt }),
+				"author": {Type: userType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return thunk(stateFrom(p.Context).loaders.users.Load(p.Source.(*models.Post).UserID)), nil
+				}},
+				"tags": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					load := stateFrom(p.Context).loaders.postTags.Load(p.Source.(*models.Post).ID)
+					return func() (interface{}, error) {
+						tags, err := load()
+						if err != nil {
+							return nil, err
+						}
+						nodes := make([]*models.Tag, len(tags))
+						for i := range tags {
+							nodes[i] = &tags[i]
+						}
+						return nodes, nil
+					}, nil
+				}},
+				"comments": {
+					Type:        graphql.NewNonNull(commentConnection),
+					Description: "顶级评论，最新的在前",
+					Args:        connectionArgs,
+					Resolve:     h.resolvePostComments,
+				},
+			}
+		}),
+	})
+
+	commentType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Comment",
+		Description: "评论",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":         field(nonNullID, func(c *models.Comment) interface{} { return c.ID }),
+				"content":    field(nonNullString, func(c *models.Comment) interface{} { return c.Content }),
+				"isApproved": field(graphql.NewNonNull(graphql.Boolean), func(c *models.Comment) interface{} { return c.IsApproved }),
+				"createdAt":  field(nonNullTime, func(c *models.Comment) interface{} { return c.CreatedAt }),
+				"updatedAt":  field(nonNullTime, func(c *models.Comment) interface{} { return c.UpdatedAt }),
+				"editedAt":   field(graphql.DateTime, func(c *models.Comment) interface{} { return c.EditedAt }),
+				"author": {Type: userType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return thunk(stateFrom(p.Context).loaders.users.Load(p.Source.(*models.Comment).UserID)), nil
+				}},
+				"post": {Type: postType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return thunk(stateFrom(p.Context).loaders.posts.Load(p.Source.(*models.Comment).PostID)), nil
+				}},
+				"parent": {Type: commentType, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(*models.Comment)
+					if c.ParentID == nil {
+						return nil, nil
+					}
+					return thunk(stateFrom(p.Context).loaders.comments.Load(*c.ParentID)), nil
+				}},
+				"replies": {
+					Type:        graphql.NewNonNull(commentConnection),
+					Description: "直接回复，按时间正序",
+					Args:        connectionArgs,
+					Resolve:     h.resolveReplies,
+				},
+			}
+		}),
+	})
+
+	tagType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Tag",
+		Description: "标签",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":    field(nonNullID, func(t *models.Tag) interface{} { return t.ID }),
+				"name":  field(nonNullString, func(t *models.Tag) interface{} { return t.Name }),
+				"slug":  field(nonNullString, func(t *models.Tag) interface{} { return t.Slug }),
+				"color": field(nonNullString, func(t *models.Tag) interface{} { return t.Color }),
+				"posts": {
+					Type:    graphql.NewNonNull(postConnection),
+					Args:    connectionArgs,
+					Resolve: h.resolveTagPosts,
+				},
+			}
+		}),
+	})
+
+	userConnection = connectionType("User", userType)
+	postConnection = connectionType("Post", postType)
+	commentConnection = connectionType("Comment", commentType)
+	tagConnection = connectionType("Tag", tagType)
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"viewer": {Type: userType, Description: "当前登录用户", Resolve: h.resolveViewer},
+			"user": {
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id":       &graphql.ArgumentConfig{Type: graphql.ID},
+					"username": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: h.resolveUser,
+			},
+			"users": {
+				Type:        graphql.NewNonNull(userConnection),
+				Description: "用户列表（需要管理员权限）",
+				Args:        connectionArgs,
+				Resolve:     h.resolveUsers,
+			},
+			"post": {
+				Type:        postType,
+				Description: "按 ID 或 slug 获取文章，每次访问阅读数加一",
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.ID},
+					"slug": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: h.resolvePost,
+			},
+			"posts": {
+				Type:        graphql.NewNonNull(postConnection),
+				Description: "文章列表，最新的在前；未登录时只包含公开的已发布文章",
+				Args: withArgs(graphql.FieldConfigArgument{
+					"status": &graphql.ArgumentConfig{Type: postStatusEnum},
+					"tag":    &graphql.ArgumentConfig{Type: graphql.String, Description: "标签 slug"},
+				}),
+				Resolve: h.resolvePosts,
+			},
+			"comment": {
+				Type:    commentType,
+				Args:    graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: nonNullID}},
+				Resolve: h.resolveComment,
+			},
+			"tag": {
+				Type:    tagType,
+				Args:    graphql.FieldConfigArgument{"slug": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: h.resolveTag,
+			},
+			"tags": {
+				Type:    graphql.NewNonNull(tagConnection),
+				Args:    connectionArgs,
+				Resolve: h.resolveTags,
+			},
+		},
+	})
+
+	createPostInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreatePostInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":    &graphql.InputObjectFieldConfig{Type: nonNullString},
+			"content":  &graphql.InputObjectFieldConfig{Type: nonNullString},
+			"summary":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"slug":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"status":   &graphql.InputObjectFieldConfig{Type: postStatusEnum},
+			"isPublic": &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		},
+	})
+	updatePostInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdatePostInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"content":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"summary":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"slug":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"status":   &graphql.InputObjectFieldConfig{Type: postStatusEnum},
+			"isPublic": &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		},
+	})
+	createCommentInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateCommentInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"postId":   &graphql.InputObjectFieldConfig{Type: nonNullID},
+			"content":  &graphql.InputObjectFieldConfig{Type: nonNullString},
+			"parentId": &graphql.InputObjectFieldConfig{Type: graphql.ID},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPost": {
+				Type:    graphql.NewNonNull(postType),
+				Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createPostInput)}},
+				Resolve: h.createPost,
+			},
+			"updatePost": {
+				Type:        graphql.NewNonNull(postType),
+				Description: "只能修改自己的文章",
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: nonNullID},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updatePostInput)},
+				},
+				Resolve: h.updatePost,
+			},
+			"deletePost": {
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "只能删除自己的文章",
+				Args:        graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: nonNullID}},
+				Resolve:     h.deletePost,
+			},
+			"createComment": {
+				Type:    graphql.NewNonNull(commentType),
+				Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createCommentInput)}},
+				Resolve: h.createComment,
+			},
+			"updateComment": {
+				Type:        graphql.NewNonNull(commentType),
+				Description: "只能在发表后 comment.edit_window 分钟内修改自己的评论，修改后重新审核",
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: nonNullID},
+					"content": &graphql.ArgumentConfig{Type: nonNullString},
+				},
+				Resolve: h.updateComment,
+			},
+			"deleteComment": {
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "只能删除自己的评论",
+				Args:        graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: nonNullID}},
+				Resolve:     h.deleteComment,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
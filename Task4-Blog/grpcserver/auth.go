@@ -0,0 +1,170 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	blogv1 "blog-system/proto/blog/v1"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// access 方法的认证要求
+type access int
+
+const (
+	accessUser   access = iota // 需要登录，未在 methodAccess 中列出的方法默认如此
+	accessPublic               // 公开，携带有效 token 时识别当前用户
+	accessAdmin                // 需要管理员权限
+)
+
+// methodAccess 各方法的认证要求，与 REST 路由分组保持一致
+var methodAccess = map[string]access{
+	blogv1.AuthService_Register_FullMethodName:              accessPublic,
+	blogv1.AuthService_Login_FullMethodName:                 accessPublic,
+	blogv1.AuthService_VerifyTwoFactor_FullMethodName:       accessPublic,
+	blogv1.UserService_GetUser_FullMethodName:               accessPublic,
+	blogv1.UserService_ListUserPosts_FullMethodName:         accessPublic,
+	blogv1.UserService_ListUsers_FullMethodName:             accessAdmin,
+	blogv1.PostService_ListPosts_FullMethodName:             accessPublic,
+	blogv1.PostService_GetPost_FullMethodName:               accessPublic,
+	blogv1.CommentService_ListPostComments_FullMethodName:   accessPublic,
+	blogv1.CommentService_ListCommentReplies_FullMethodName: accessPublic,
+	blogv1.CommentService_GetComment_FullMethodName:         accessPublic,
+	blogv1.CommentService_WatchPostComments_FullMethodName:  accessPublic,
+}
+
+// accessOf 返回方法的认证要求，服务反射接口公开
+func accessOf(method string) access {
+	if level, ok := methodAccess[method]; ok {
+		return level
+	}
+	if strings.HasPrefix(method, "/grpc.reflection.") {
+		return accessPublic
+	}
+	return accessUser
+}
+
+type userKey struct{}
+
+// userFrom 取出拦截器识别的当前用户
+func userFrom(ctx context.Context) (*services.TokenUser, bool) {
+	user, ok := ctx.Value(userKey{}).(*services.TokenUser)
+	return user, ok
+}
+
+// currentUserID 当前用户 ID，需要登录的方法中一定存在
+func currentUserID(ctx context.Context) uint {
+	if user, ok := userFrom(ctx); ok {
+		return user.ID
+	}
+	return 0
+}
+
+// authInterceptor JWT 认证拦截器，token 通过 metadata authorization: Bearer <token> 传递，
+// 校验逻辑与 HTTP 认证中间件相同
+type authInterceptor struct {
+	authService *services.AuthService
+}
+
+// authenticate 按方法的认证要求校验 token，通过后将用户信息写入 context
+func (a *authInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+	level := accessOf(method)
+
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	if header == "" {
+		if level == accessPublic {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "缺少认证token")
+	}
+
+	parts := strings.Split(header, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		if level == accessPublic {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "token格式错误")
+	}
+
+	user, err := a.authService.Authenticate(parts[1])
+	if err != nil {
+		if level == accessPublic {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "token无效或已过期")
+	}
+
+	if level == accessAdmin && !user.HasRole("admin") {
+		if user.Role == "admin" {
+			return nil, status.Error(codes.PermissionDenied, "需要先开启两步验证并使用两步验证登录")
+		}
+		return nil, status.Error(codes.PermissionDenied, "需要管理员权限")
+	}
+
+	actor := services.ActorFrom(ctx)
+	actor.UserID = user.ID
+	actor.Username = user.Username
+	ctx = services.WithActor(ctx, actor)
+	return context.WithValue(ctx, userKey{}, user), nil
+}
+
+// withRequestID 沿用 metadata x-request-id 中合法的请求 ID，否则生成新的 ID，
+// 与客户端 IP 一起写入 context 供审计日志使用
+func withRequestID(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("x-request-id"); len(values) > 0 && utils.ValidRequestID(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = utils.NewRequestID()
+	}
+	return services.WithActor(ctx, services.Actor{IP: clientIP(ctx), RequestID: id}), id
+}
+
+// unary 一元调用的认证拦截器，请求 ID 通过响应 header x-request-id 返回
+func (a *authInterceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, requestID := withRequestID(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream 流式调用的认证拦截器，请求 ID 通过响应 header x-request-id 返回
+func (a *authInterceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, requestID := withRequestID(ss.Context())
+	ss.SetHeader(metadata.Pairs("x-request-id", requestID))
+
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
+
+// authStream 替换 context 以携带用户信息
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,153 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"blog-system/controllers"
+	"blog-system/models"
+	blogv1 "blog-system/proto/blog/v1"
+	"blog-system/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// authServer 认证服务
+type authServer struct {
+	blogv1.UnimplementedAuthServiceServer
+	authService      *services.AuthService
+	userService      *services.UserService
+	twoFactorService *services.TwoFactorService
+}
+
+// Register 用户注册
+func (s *authServer) Register(ctx context.Context, req *blogv1.RegisterRequest) (*blogv1.AuthResponse, error) {
+	if err := validate(controllers.RegisterRequest{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+		Bio:      req.Bio,
+	}); err != nil {
+		return nil, err
+	}
+
+	if s.userService.UsernameExists(req.Username) {
+		return nil, status.Error(codes.AlreadyExists, "用户名已存在")
+	}
+	if s.userService.EmailExists(req.Email) {
+		return nil, status.Error(codes.AlreadyExists, "邮箱已存在")
+	}
+
+	user, err := s.authService.Register(req.Username, req.Email, req.Password, req.Bio)
+	if err != nil {
+		return nil, internal("注册失败", err)
+	}
+	return s.issueToken(user, false)
+}
+
+// Login 用户登录
+func (s *authServer) Login(ctx context.Context, req *blogv1.LoginRequest) (*blogv1.AuthResponse, error) {
+	if err := validate(controllers.LoginRequest{Username: req.Username, Password: req.Password}); err != nil {
+		return nil, err
+	}
+
+	user, err := s.authService.Login(req.Username, req.Password, clientIP(ctx))
+	if err != nil {
+		var blocked *services.LoginBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			return nil, status.Error(codes.ResourceExhausted, blocked.Error())
+		case errors.Is(err, services.ErrInvalidCredentials):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		default:
+			return nil, internal("登录失败", err)
+		}
+	}
+
+	// 开启了两步验证的用户先返回挑战令牌
+	if user.TwoFactorEnabled {
+		challenge, _, err := s.authService.GenerateChallengeToken(user)
+		if err != nil {
+			return nil, internal("生成token失败", err)
+		}
+		return &blogv1.AuthResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	s.userService.RecordLogin(ctx, user, "password")
+
+	return s.issueToken(user, false)
+}
+
+// VerifyTwoFactor 两步验证登录的第二步
+func (s *authServer) VerifyTwoFactor(ctx context.Context, req *blogv1.VerifyTwoFactorRequest) (*blogv1.AuthResponse, error) {
+	if err := validate(controllers.VerifyTwoFactorRequest{ChallengeToken: req.ChallengeToken, Code: req.Code}); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.authService.ParseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "挑战令牌无效或已过期")
+	}
+
+	user, err := s.twoFactorService.Verify(userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTwoFactorLocked):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, services.ErrTwoFactorCode), errors.Is(err, services.ErrTwoFactorNotEnabled):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, status.Error(codes.Unauthenticated, "账号不存在或已停用")
+		default:
+			return nil, internal("登录失败", err)
+		}
+	}
+
+	s.userService.RecordLogin(ctx, user, "two_factor")
+
+	return s.issueToken(user, true)
+}
+
+// GetProfile 获取当前用户信息
+func (s *authServer) GetProfile(ctx context.Context, req *blogv1.GetProfileRequest) (*blogv1.User, error) {
+	user, err := s.userService.GetUserByID(currentUserID(ctx))
+	if err != nil {
+		return nil, lookupError(err, "用户不存在")
+	}
+	return toUser(user.ToResponse()), nil
+}
+
+// UpdateProfile 更新当前用户信息
+func (s *authServer) UpdateProfile(ctx context.Context, req *blogv1.UpdateProfileRequest) (*blogv1.User, error) {
+	user, err := s.userService.UpdateUser(currentUserID(ctx), req.Bio, req.Avatar)
+	if err != nil {
+		return nil, internal("更新用户信息失败", err)
+	}
+	return toUser(user.ToResponse()), nil
+}
+
+// clientIP 调用方的 IP 地址
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// issueToken 为用户生成 token，mfa 表示本次登录是否通过了两步验证
+func (s *authServer) issueToken(user *models.User, mfa bool) (*blogv1.AuthResponse, error) {
+	token, err := s.authService.GenerateToken(user, mfa)
+	if err != nil {
+		return nil, internal("生成token失败", err)
+	}
+	return &blogv1.AuthResponse{Token: token, User: toUser(user.ToResponse())}, nil
+}
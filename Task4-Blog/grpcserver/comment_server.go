@@ -0,0 +1,236 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"blog-system/controllers"
+	"blog-system/models"
+	blogv1 "blog-system/proto/blog/v1"
+	"blog-system/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// watchBacklogBatch 补发历史评论时每次查询的条数
+const watchBacklogBatch = 100
+
+// commentServer 评论服务
+type commentServer struct {
+	blogv1.UnimplementedCommentServiceServer
+	commentService *services.CommentService
+	postService    *services.PostService
+	quit           <-chan struct{} // 服务器关闭时结束所有订阅流
+}
+
+// ListPostComments 获取文章的评论列表
+func (s *commentServer) ListPostComments(ctx context.Context, req *blogv1.ListPostCommentsRequest) (*blogv1.ListPostCommentsResponse, error) {
+	postID, err := id(req.PostId, "文章ID")
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := pageParams(req.Page, req.PageSize, 20)
+	opts := services.CommentThreadOptions{
+		Sort:      models.CommentSort(req.Sort),
+		ReplySort: models.CommentSort(req.ReplySort),
+		Depth:     optionalDepth(req.Depth),
+	}
+	comments, total, err := s.commentService.GetPostComments(postID, page, pageSize, opts)
+	if err != nil {
+		if errors.Is(err, services.ErrCommentSort) {
+			return nil, invalidArgument(err.Error())
+		}
+		return nil, internal("获取评论列表失败", err)
+	}
+
+	resp := &blogv1.ListPostCommentsResponse{Pagination: pagination(page, pageSize, total)}
+	for _, comment := range comments {
+		resp.Comments = append(resp.Comments, toComment(comment))
+	}
+	return resp, nil
+}
+
+// ListCommentReplies 分页获取评论的回复
+func (s *commentServer) ListCommentReplies(ctx context.Context, req *blogv1.ListCommentRepliesRequest) (*blogv1.ListCommentRepliesResponse, error) {
+	commentID, err := id(req.CommentId, "评论ID")
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	page, err := s.commentService.GetCommentReplies(commentID, models.CommentSort(req.Sort), req.Cursor, limit, optionalDepth(req.Depth))
+	if err != nil {
+		if errors.Is(err, services.ErrCommentSort) || errors.Is(err, services.ErrCommentCursor) {
+			return nil, invalidArgument(err.Error())
+		}
+		return nil, lookupError(err, "评论不存在")
+	}
+
+	resp := &blogv1.ListCommentRepliesResponse{NextCursor: page.NextCursor, ReplyCount: int32(page.ReplyCount)}
+	for _, reply := range page.Replies {
+		resp.Replies = append(resp.Replies, toComment(reply))
+	}
+	return resp, nil
+}
+
+// optionalDepth 转换可选的展开层数，未指定时为 nil（使用 comment.max_depth）
+func optionalDepth(depth *int32) *int {
+	if depth == nil {
+		return nil
+	}
+	d := int(*depth)
+	return &d
+}
+
+// GetComment 根据 ID 获取评论
+func (s *commentServer) GetComment(ctx context.Context, req *blogv1.GetCommentRequest) (*blogv1.Comment, error) {
+	commentID, err := id(req.Id, "评论ID")
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentService.GetCommentByID(commentID)
+	if err != nil {
+		return nil, lookupError(err, "评论不存在")
+	}
+	return toComment(comment.ToResponse()), nil
+}
+
+// CreateComment 创建评论
+func (s *commentServer) CreateComment(ctx context.Context, req *blogv1.CreateCommentRequest) (*blogv1.Comment, error) {
+	input := controllers.CreateCommentRequest{Content: req.Content}
+	if req.PostId != 0 {
+		postID, err := id(req.PostId, "文章ID")
+		if err != nil {
+			return nil, err
+		}
+		input.PostID = postID
+	}
+	if req.ParentId != nil {
+		parentID, err := id(*req.ParentId, "父评论ID")
+		if err != nil {
+			return nil, err
+		}
+		input.ParentID = &parentID
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentService.CreateComment(ctx, currentUserID(ctx), input.PostID, input.Content, input.ParentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, status.Error(codes.NotFound, "文章不存在")
+		case errors.Is(err, services.ErrCommentParentNotFound), errors.Is(err, services.ErrCommentParentMismatch):
+			return nil, invalidArgument(err.Error())
+		}
+		return nil, internal("创建评论失败", err)
+	}
+	return toComment(comment.ToResponse()), nil
+}
+
+// UpdateComment 修改评论
+func (s *commentServer) UpdateComment(ctx context.Context, req *blogv1.UpdateCommentRequest) (*blogv1.Comment, error) {
+	commentID, err := id(req.Id, "评论ID")
+	if err != nil {
+		return nil, err
+	}
+	input := controllers.UpdateCommentRequest{Content: req.Content}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否有权限修改这条评论
+	if !s.commentService.IsCommentOwner(commentID, currentUserID(ctx)) {
+		return nil, status.Error(codes.PermissionDenied, "只能修改自己的评论")
+	}
+
+	comment, err := s.commentService.UpdateComment(ctx, commentID, input.Content)
+	if err != nil {
+		if errors.Is(err, services.ErrCommentEditWindow) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, lookupError(err, "评论不存在")
+	}
+	return toComment(comment.ToResponse()), nil
+}
+
+// DeleteComment 删除评论
+func (s *commentServer) DeleteComment(ctx context.Context, req *blogv1.DeleteCommentRequest) (*blogv1.DeleteCommentResponse, error) {
+	commentID, err := id(req.Id, "评论ID")
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否有权限删除这条评论
+	if !s.commentService.IsCommentOwner(commentID, currentUserID(ctx)) {
+		return nil, status.Error(codes.PermissionDenied, "只能删除自己的评论")
+	}
+
+	if err := s.commentService.DeleteComment(ctx, commentID); err != nil {
+		return nil, internal("删除评论失败", err)
+	}
+	return &blogv1.DeleteCommentResponse{}, nil
+}
+
+// WatchPostComments 推送文章的新评论，直到客户端取消或服务器关闭
+func (s *commentServer) WatchPostComments(req *blogv1.WatchPostCommentsRequest, stream grpc.ServerStreamingServer[blogv1.Comment]) error {
+	postID, err := id(req.PostId, "文章ID")
+	if err != nil {
+		return err
+	}
+	if _, err := s.postService.GetPostByID(postID); err != nil {
+		return lookupError(err, "文章不存在")
+	}
+
+	// 先订阅再补发历史评论，补发期间创建的评论按 ID 去重
+	comments, cancel := s.commentService.SubscribeComments(postID)
+	defer cancel()
+
+	last := uint(req.SinceId)
+	if req.SinceId > 0 {
+		for {
+			backlog, err := s.commentService.GetCommentsAfter(postID, last, watchBacklogBatch)
+			if err != nil {
+				return internal("获取评论失败", err)
+			}
+			for _, comment := range backlog {
+				if err := stream.Send(toComment(comment.ToResponse())); err != nil {
+					return err
+				}
+				last = comment.ID
+			}
+			if len(backlog) < watchBacklogBatch {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-s.quit:
+			return status.Error(codes.Unavailable, "服务器正在关闭，请稍后重新订阅")
+		case comment, ok := <-comments:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "推送积压过多，请使用 since_id 重新订阅")
+			}
+			if comment.ID <= last {
+				continue
+			}
+			if err := stream.Send(toComment(comment.ToResponse())); err != nil {
+				return err
+			}
+			last = comment.ID
+		}
+	}
+}
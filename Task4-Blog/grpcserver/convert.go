@@ -0,0 +1,168 @@
+package grpcserver
+
+import (
+	"errors"
+	"time"
+
+	"blog-system/models"
+	blogv1 "blog-system/proto/blog/v1"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// postStatuses proto 枚举与文章状态的对应关系
+var postStatuses = map[blogv1.PostStatus]models.PostStatus{
+	blogv1.PostStatus_POST_STATUS_UNSPECIFIED: "",
+	blogv1.PostStatus_POST_STATUS_DRAFT:       models.PostStatusDraft,
+	blogv1.PostStatus_POST_STATUS_PUBLISHED:   models.PostStatusPublished,
+	blogv1.PostStatus_POST_STATUS_ARCHIVED:    models.PostStatusArchived,
+}
+
+// fromPostStatus 转换文章状态，UNSPECIFIED 转为空字符串
+func fromPostStatus(s blogv1.PostStatus) (models.PostStatus, error) {
+	status, ok := postStatuses[s]
+	if !ok {
+		return "", invalidArgument("文章状态不正确")
+	}
+	return status, nil
+}
+
+// toPostStatus 转换文章状态，未知状态转为 UNSPECIFIED
+func toPostStatus(s models.PostStatus) blogv1.PostStatus {
+	for k, v := range postStatuses {
+		if v == s {
+			return k
+		}
+	}
+	return blogv1.PostStatus_POST_STATUS_UNSPECIFIED
+}
+
+// timestamp 转换时间，nil 保持为 nil
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func toUser(u models.UserResponse) *blogv1.User {
+	return &blogv1.User{
+		Id:        uint64(u.ID),
+		Username:  u.Username,
+		Email:     u.Email,
+		Bio:       u.Bio,
+		Avatar:    u.Avatar,
+		Role:      u.Role,
+		CreatedAt: timestamppb.New(u.CreatedAt),
+	}
+}
+
+func toPost(p models.PostResponse) *blogv1.Post {
+	return &blogv1.Post{
+		Id:           uint64(p.ID),
+		Title:        p.Title,
+		Content:      p.Content,
+		Summary:      p.Summary,
+		Slug:         p.Slug,
+		Status:       toPostStatus(p.Status),
+		IsPublic:     p.IsPublic,
+		ViewCount:    int32(p.ViewCount),
+		LikeCount:    int32(p.LikeCount),
+		CommentCount: int32(p.CommentCount),
+		PublishedAt:  timestamp(p.PublishedAt),
+		CreatedAt:    timestamppb.New(p.CreatedAt),
+		UpdatedAt:    timestamppb.New(p.UpdatedAt),
+		Author:       toUser(p.User),
+	}
+}
+
+func toPosts(posts []models.PostResponse) []*blogv1.Post {
+	result := make([]*blogv1.Post, 0, len(posts))
+	for _, p := range posts {
+		result = append(result, toPost(p))
+	}
+	return result
+}
+
+func toComment(c models.CommentResponse) *blogv1.Comment {
+	comment := &blogv1.Comment{
+		Id:            uint64(c.ID),
+		Content:       c.Content,
+		IsApproved:    c.IsApproved,
+		CreatedAt:     timestamppb.New(c.CreatedAt),
+		UpdatedAt:     timestamppb.New(c.UpdatedAt),
+		Author:        toUser(c.User),
+		PostId:        uint64(c.PostID),
+		Depth:         int32(c.Depth),
+		ReplyCount:    int32(c.ReplyCount),
+		RepliesCursor: c.RepliesCursor,
+	}
+	if c.EditedAt != nil {
+		comment.EditedAt = timestamppb.New(*c.EditedAt)
+	}
+	if c.ParentID != nil {
+		parentID := uint64(*c.ParentID)
+		comment.ParentId = &parentID
+	}
+	for _, reply := range c.Replies {
+		comment.Replies = append(comment.Replies, toComment(reply))
+	}
+	return comment
+}
+
+// pageParams 补全分页参数，与 REST 接口的默认值一致
+func pageParams(page, pageSize int32, defaultSize int) (int, int) {
+	p, size := int(page), int(pageSize)
+	if p <= 0 {
+		p = 1
+	}
+	if size <= 0 {
+		size = defaultSize
+	}
+	return p, size
+}
+
+func pagination(page, pageSize int, total int64) *blogv1.Pagination {
+	return &blogv1.Pagination{
+		Page:      int32(page),
+		PageSize:  int32(pageSize),
+		Total:     total,
+		TotalPage: (total + int64(pageSize) - 1) / int64(pageSize),
+	}
+}
+
+// id 校验并转换请求中的 ID
+func id(v uint64, name string) (uint, error) {
+	if v == 0 || v > uint64(^uint32(0)) {
+		return 0, invalidArgument(name + "格式不正确")
+	}
+	return uint(v), nil
+}
+
+// validate 使用与 REST 接口相同的校验规则
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return invalidArgument("请求参数错误: " + err.Error())
+	}
+	return nil
+}
+
+func invalidArgument(msg string) error {
+	return status.Error(codes.InvalidArgument, msg)
+}
+
+func internal(msg string, err error) error {
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+// lookupError 记录不存在时返回 NotFound，其他错误返回 Internal
+func lookupError(err error, notFound string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, notFound)
+	}
+	return internal(notFound, err)
+}
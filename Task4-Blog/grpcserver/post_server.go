@@ -0,0 +1,147 @@
+package grpcserver
+
+import (
+	"context"
+
+	"blog-system/controllers"
+	"blog-system/models"
+	blogv1 "blog-system/proto/blog/v1"
+	"blog-system/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// postServer 文章服务
+type postServer struct {
+	blogv1.UnimplementedPostServiceServer
+	postService *services.PostService
+}
+
+// ListPosts 获取文章列表，默认只返回已发布的文章
+func (s *postServer) ListPosts(ctx context.Context, req *blogv1.ListPostsRequest) (*blogv1.ListPostsResponse, error) {
+	postStatus, err := fromPostStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	if postStatus == "" {
+		postStatus = models.PostStatusPublished
+	}
+
+	page, pageSize := pageParams(req.Page, req.PageSize, 10)
+	posts, total, err := s.postService.GetPosts(page, pageSize, postStatus)
+	if err != nil {
+		return nil, internal("获取文章列表失败", err)
+	}
+	return &blogv1.ListPostsResponse{Posts: toPosts(posts), Pagination: pagination(page, pageSize, total)}, nil
+}
+
+// GetPost 根据 ID 获取文章
+func (s *postServer) GetPost(ctx context.Context, req *blogv1.GetPostRequest) (*blogv1.Post, error) {
+	postID, err := id(req.Id, "文章ID")
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.postService.GetPostByID(postID)
+	if err != nil {
+		return nil, lookupError(err, "文章不存在")
+	}
+
+	// 增加阅读次数
+	s.postService.IncrementViewCount(postID)
+
+	return toPost(post.ToResponse()), nil
+}
+
+// ListMyPosts 获取当前用户的文章
+func (s *postServer) ListMyPosts(ctx context.Context, req *blogv1.ListMyPostsRequest) (*blogv1.ListPostsResponse, error) {
+	postStatus, err := fromPostStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := pageParams(req.Page, req.PageSize, 10)
+	posts, total, err := s.postService.GetUserPosts(currentUserID(ctx), page, pageSize, postStatus)
+	if err != nil {
+		return nil, internal("获取文章列表失败", err)
+	}
+	return &blogv1.ListPostsResponse{Posts: toPosts(posts), Pagination: pagination(page, pageSize, total)}, nil
+}
+
+// CreatePost 创建文章
+func (s *postServer) CreatePost(ctx context.Context, req *blogv1.CreatePostRequest) (*blogv1.Post, error) {
+	postStatus, err := fromPostStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	input := controllers.CreatePostRequest{
+		Title:    req.Title,
+		Content:  req.Content,
+		Summary:  req.Summary,
+		Slug:     req.Slug,
+		Status:   postStatus,
+		IsPublic: req.IsPublic,
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	post, err := s.postService.CreatePost(ctx, currentUserID(ctx), input.Title, input.Content, input.Summary, input.Slug, input.Status, input.IsPublic)
+	if err != nil {
+		return nil, internal("创建文章失败", err)
+	}
+	return toPost(post.ToResponse()), nil
+}
+
+// UpdatePost 更新文章
+func (s *postServer) UpdatePost(ctx context.Context, req *blogv1.UpdatePostRequest) (*blogv1.Post, error) {
+	postID, err := id(req.Id, "文章ID")
+	if err != nil {
+		return nil, err
+	}
+	postStatus, err := fromPostStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+	input := controllers.UpdatePostRequest{
+		Title:    req.Title,
+		Content:  req.Content,
+		Summary:  req.Summary,
+		Slug:     req.Slug,
+		Status:   postStatus,
+		IsPublic: req.IsPublic,
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否有权限修改这篇文章
+	if !s.postService.IsPostOwner(postID, currentUserID(ctx)) {
+		return nil, status.Error(codes.PermissionDenied, "只能修改自己的文章")
+	}
+
+	post, err := s.postService.UpdatePost(ctx, postID, input.Title, input.Content, input.Summary, input.Slug, input.Status, input.IsPublic)
+	if err != nil {
+		return nil, internal("更新文章失败", err)
+	}
+	return toPost(post.ToResponse()), nil
+}
+
+// DeletePost 删除文章
+func (s *postServer) DeletePost(ctx context.Context, req *blogv1.DeletePostRequest) (*blogv1.DeletePostResponse, error) {
+	postID, err := id(req.Id, "文章ID")
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否有权限删除这篇文章
+	if !s.postService.IsPostOwner(postID, currentUserID(ctx)) {
+		return nil, status.Error(codes.PermissionDenied, "只能删除自己的文章")
+	}
+
+	if err := s.postService.DeletePost(ctx, postID); err != nil {
+		return nil, internal("删除文章失败", err)
+	}
+	return &blogv1.DeletePostResponse{}, nil
+}
@@ -0,0 +1,99 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"net"
+	"runtime/debug"
+	"sync"
+
+	"blog-system/config"
+	blogv1 "blog-system/proto/blog/v1"
+	"blog-system/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Server gRPC 服务器，与 HTTP 接口共用服务层和 token 校验
+type Server struct {
+	srv      *grpc.Server
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// NewServer 创建 gRPC 服务器并注册所有服务
+func NewServer(cfg config.GRPCConfig) *Server {
+	authService := services.NewAuthService()
+	userService := services.NewUserService()
+	postService := services.NewPostService()
+	commentService := services.NewCommentService()
+	twoFactorService := services.NewTwoFactorService()
+
+	auth := &authInterceptor{authService: authService}
+	s := &Server{
+		srv: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(recoverUnary, auth.unary),
+			grpc.ChainStreamInterceptor(recoverStream, auth.stream),
+		),
+		quit: make(chan struct{}),
+	}
+
+	blogv1.RegisterAuthServiceServer(s.srv, &authServer{authService: authService, userService: userService, twoFactorService: twoFactorService})
+	blogv1.RegisterUserServiceServer(s.srv, &userServer{userService: userService})
+	blogv1.RegisterPostServiceServer(s.srv, &postServer{postService: postService})
+	blogv1.RegisterCommentServiceServer(s.srv, &commentServer{commentService: commentService, postService: postService, quit: s.quit})
+
+	if cfg.Reflection {
+		reflection.Register(s.srv)
+	}
+	return s
+}
+
+// Serve 在 lis 上处理请求，直到调用 Shutdown
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// Shutdown 结束所有评论订阅流并等待进行中的调用完成，ctx 到期后强制关闭
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.quitOnce.Do(func() { close(s.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// recoverUnary 捕获一元调用中的 panic，防止服务崩溃
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic recovered: %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "服务器内部错误")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// recoverStream 捕获流式调用中的 panic
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic recovered: %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "服务器内部错误")
+		}
+	}()
+	return handler(srv, ss)
+}
@@ -0,0 +1,58 @@
+package grpcserver
+
+import (
+	"context"
+
+	blogv1 "blog-system/proto/blog/v1"
+	"blog-system/services"
+)
+
+// userServer 用户服务
+type userServer struct {
+	blogv1.UnimplementedUserServiceServer
+	userService *services.UserService
+}
+
+// GetUser 根据 ID 获取用户
+func (s *userServer) GetUser(ctx context.Context, req *blogv1.GetUserRequest) (*blogv1.User, error) {
+	userID, err := id(req.Id, "用户ID")
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userService.GetUserByID(userID)
+	if err != nil {
+		return nil, lookupError(err, "用户不存在")
+	}
+	return toUser(user.ToResponse()), nil
+}
+
+// ListUserPosts 获取用户的文章列表
+func (s *userServer) ListUserPosts(ctx context.Context, req *blogv1.ListUserPostsRequest) (*blogv1.ListPostsResponse, error) {
+	userID, err := id(req.UserId, "用户ID")
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := pageParams(req.Page, req.PageSize, 10)
+	posts, total, err := s.userService.GetUserPosts(userID, page, pageSize)
+	if err != nil {
+		return nil, internal("获取文章列表失败", err)
+	}
+	return &blogv1.ListPostsResponse{Posts: toPosts(posts), Pagination: pagination(page, pageSize, total)}, nil
+}
+
+// ListUsers 获取用户列表（管理员）
+func (s *userServer) ListUsers(ctx context.Context, req *blogv1.ListUsersRequest) (*blogv1.ListUsersResponse, error) {
+	page, pageSize := pageParams(req.Page, req.PageSize, 10)
+	users, total, err := s.userService.GetUsers(page, pageSize)
+	if err != nil {
+		return nil, internal("获取用户列表失败", err)
+	}
+
+	resp := &blogv1.ListUsersResponse{Pagination: pagination(page, pageSize, total)}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toUser(user.ToResponse()))
+	}
+	return resp, nil
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"blog-system/database"
+	"blog-system/jobs"
+)
+
+// DatabaseChecker 数据库连通性检查
+func DatabaseChecker() Checker {
+	return func(ctx context.Context) error {
+		return database.HealthCheck(ctx)
+	}
+}
+
+// MigrationsChecker 数据库迁移是否已执行
+func MigrationsChecker() Checker {
+	return func(ctx context.Context) error {
+		return database.MigrationsApplied(ctx)
+	}
+}
+
+// StorageChecker 存储目录是否可写
+func StorageChecker(dir string) Checker {
+	return func(ctx context.Context) error {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("创建存储目录失败: %v", err)
+		}
+
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return fmt.Errorf("存储目录不可写: %v", err)
+		}
+		name := f.Name()
+		defer os.Remove(name)
+
+		if _, err := f.WriteString("ok"); err != nil {
+			f.Close()
+			return fmt.Errorf("写入存储目录失败: %v", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("写入存储目录失败: %v", err)
+		}
+		return nil
+	}
+}
+
+// WorkersChecker 后台任务是否存活（运行中且心跳未超时）
+func WorkersChecker(grace time.Duration) Checker {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		for _, st := range jobs.Statuses() {
+			if !st.Running {
+				return fmt.Errorf("后台任务 %s 未运行", st.Name)
+			}
+			if deadline := st.Heartbeat.Add(2*st.Interval + grace); now.After(deadline) {
+				return fmt.Errorf("后台任务 %s 心跳超时，最近一次: %s", st.Name, st.Heartbeat.Format(time.RFC3339))
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Checker 健康检查函数，返回 nil 表示正常
+type Checker func(ctx context.Context) error
+
+// check 已注册的检查项
+type check struct {
+	name    string
+	checker Checker
+}
+
+// Result 单项检查结果
+type Result struct {
+	Status    string  `json:"status"` // ok, fail
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report 检查报告
+type Report struct {
+	Status string            `json:"status"` // ok, fail
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK 是否所有检查都通过
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Registry 健康检查注册表
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []check
+	timeout time.Duration
+}
+
+// NewRegistry 创建注册表，timeout 为单项检查的超时时间
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register 注册检查项
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check{name: name, checker: checker})
+}
+
+// Run 并发执行所有检查项并汇总结果
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]check, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]Result, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, ch := range checks {
+		wg.Add(1)
+		go func(ch check) {
+			defer wg.Done()
+			result := r.runOne(ctx, ch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[ch.name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(ch)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runOne 在超时控制下执行单项检查
+func (r *Registry) runOne(ctx context.Context, ch check) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ch.checker(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
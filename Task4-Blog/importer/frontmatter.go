@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// frontMatter 解析后的 front matter
+type frontMatter map[string]interface{}
+
+// splitFrontMatter 拆分 front matter 与正文，支持 YAML（---）和 TOML（+++）
+func splitFrontMatter(content []byte) (frontMatter, string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	var delim string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delim = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delim = "+++"
+	default:
+		return frontMatter{}, text, nil
+	}
+
+	rest := text[len(delim)+1:]
+	var header, body string
+	if strings.HasPrefix(rest, delim) {
+		body = rest[len(delim):]
+	} else if end := strings.Index(rest, "\n"+delim); end >= 0 {
+		header, body = rest[:end], rest[end+1+len(delim):]
+	} else {
+		return nil, "", fmt.Errorf("front matter 未闭合")
+	}
+	body = strings.TrimLeft(body, "\n")
+
+	fm := frontMatter{}
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal([]byte(header), &fm)
+	} else {
+		err = toml.Unmarshal([]byte(header), &fm)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("解析 front matter 失败: %v", err)
+	}
+	return fm, body, nil
+}
+
+// str 读取字符串字段，按顺序返回第一个非空的键
+func (fm frontMatter) str(keys ...string) string {
+	for _, key := range keys {
+		switch v := fm[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case []interface{}:
+			// Hugo 的 authors 是列表，取第一个
+			if len(v) > 0 {
+				if s, ok := v[0].(string); ok && s != "" {
+					return s
+				}
+			}
+		case nil:
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// boolean 读取布尔字段，exists 表示字段是否存在
+func (fm frontMatter) boolean(key string) (value bool, exists bool) {
+	switch v := fm[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		return v == "true" || v == "yes", true
+	}
+	return false, false
+}
+
+// list 读取列表字段，兼容逗号或空格分隔的字符串（Jekyll 允许 tags: a b c）
+func (fm frontMatter) list(keys ...string) []string {
+	var out []string
+	for _, key := range keys {
+		switch v := fm[key].(type) {
+		case []interface{}:
+			for _, item := range v {
+				if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+					out = append(out, s)
+				}
+			}
+		case []string:
+			out = append(out, v...)
+		case string:
+			sep := " "
+			if strings.Contains(v, ",") {
+				sep = ","
+			}
+			for _, item := range strings.Split(v, sep) {
+				if s := strings.TrimSpace(item); s != "" {
+					out = append(out, s)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// dateLayouts 支持的日期格式
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// date 读取日期字段
+func (fm frontMatter) date(keys ...string) (time.Time, bool) {
+	for _, key := range keys {
+		switch v := fm[key].(type) {
+		case time.Time:
+			return v, true
+		case toml.LocalDateTime:
+			return v.AsTime(time.Local), true
+		case toml.LocalDate:
+			return v.AsTime(time.Local), true
+		case string:
+			if t, ok := parseDate(v); ok {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDate 按支持的格式解析日期
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
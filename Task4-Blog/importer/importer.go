@@ -0,0 +1,651 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-system/models"
+	"blog-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// 导入记录中的实体类型
+const (
+	entityPost    = "post"
+	entityComment = "comment"
+)
+
+// 变更动作
+const (
+	ActionCreate   = "create"   // 新建
+	ActionUpdate   = "update"   // 更新
+	ActionSkip     = "skip"     // 无变化，跳过
+	ActionConflict = "conflict" // 冲突，未导入
+)
+
+// errDryRun 预演模式下用于回滚事务
+var errDryRun = errors.New("dry run")
+
+// Options 导入选项
+type Options struct {
+	DryRun               bool              // 预演模式：执行全部检查但回滚所有写入
+	AuthorMap            map[string]string // 来源作者登录名 -> 本地用户名
+	DefaultAuthor        string            // 作者无法匹配时使用的本地用户名
+	ForceAuthorID        uint              // 非 0 时所有文章归属该用户（用户自助导入）
+	CreateMissingAuthors bool              // 作者无法匹配时自动创建用户
+	FallbackCommenter    string            // 评论者不是本地用户时使用的本地用户名，为空且设置了 ForceAuthorID 时使用该用户
+}
+
+// Change 一条变更
+type Change struct {
+	Type   string `json:"type"`   // post, comment, tag, user
+	Action string `json:"action"` // create, update, skip, conflict
+	Key    string `json:"key"`    // 外部 ID 或名称
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Counts 各动作的数量
+type Counts struct {
+	Create   int `json:"create"`
+	Update   int `json:"update"`
+	Skip     int `json:"skip"`
+	Conflict int `json:"conflict"`
+}
+
+// Report 导入报告，预演模式下即为变更预览
+type Report struct {
+	DryRun  bool              `json:"dry_run"`
+	Source  string            `json:"source"`
+	Format  string            `json:"format"`
+	Changes []Change          `json:"changes"`
+	Summary map[string]Counts `json:"summary"`
+}
+
+// add 记录一条变更
+func (r *Report) add(c Change) {
+	r.Changes = append(r.Changes, c)
+	counts := r.Summary[c.Type]
+	switch c.Action {
+	case ActionCreate:
+		counts.Create++
+	case ActionUpdate:
+		counts.Update++
+	case ActionSkip:
+		counts.Skip++
+	case ActionConflict:
+		counts.Conflict++
+	}
+	r.Summary[c.Type] = counts
+}
+
+// Import 将解析后的内容写入数据库。所有写入在同一个事务中完成，重复导入时根据导入记录和内容摘要跳过未变化的条目
+func Import(db *gorm.DB, doc *Document, opts Options) (*Report, error) {
+	report := &Report{
+		DryRun:  opts.DryRun,
+		Source:  doc.Source,
+		Format:  doc.Format,
+		Changes: []Change{},
+		Summary: make(map[string]Counts),
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		run := &importRun{
+			tx:      tx,
+			opts:    opts,
+			doc:     doc,
+			report:  report,
+			users:   make(map[string]uint),
+			tags:    make(map[string]*models.Tag),
+			authors: make(map[string]Author),
+		}
+		for _, a := range doc.Authors {
+			run.authors[a.Login] = a
+		}
+
+		for i := range doc.Posts {
+			if err := run.importPost(&doc.Posts[i]); err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importRun 一次导入的上下文
+type importRun struct {
+	tx      *gorm.DB
+	opts    Options
+	doc     *Document
+	report  *Report
+	users   map[string]uint // 用户名 -> 用户 ID（0 表示不存在）
+	tags    map[string]*models.Tag
+	authors map[string]Author
+}
+
+// importPost 导入单篇文章及其评论
+func (r *importRun) importPost(p *Post) error {
+	if p.ExternalID == "" {
+		r.report.add(Change{Type: entityPost, Action: ActionConflict, Title: p.Title, Detail: "缺少外部 ID"})
+		return nil
+	}
+
+	authorID, err := r.resolveAuthor(p.Author)
+	if err != nil {
+		return err
+	}
+	if authorID == 0 {
+		r.report.add(Change{Type: entityPost, Action: ActionConflict, Key: p.ExternalID, Title: p.Title,
+			Detail: fmt.Sprintf("作者 %q 无法匹配本地用户", p.Author)})
+		return nil
+	}
+
+	slug := p.Slug
+	if slug == "" {
+		slug = utils.Slugify(p.Title)
+	}
+	if slug == "" {
+		slug = "post-" + shortHash(p.ExternalID)
+	}
+
+	checksum := p.Checksum()
+	var record models.ImportRecord
+	var postID uint
+	err = r.tx.Where("source = ? AND entity_type = ? AND external_id = ?", r.doc.Source, entityPost, p.ExternalID).
+		First(&record).Error
+	switch {
+	case err == nil:
+		postID, err = r.updatePost(p, &record, authorID, slug, checksum)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		postID, err = r.createPost(p, authorID, slug, checksum)
+	}
+	if err != nil || postID == 0 {
+		return err
+	}
+
+	return r.importComments(p, postID)
+}
+
+// createPost 新建文章，slug 已被占用时记录冲突并返回 0
+func (r *importRun) createPost(p *Post, authorID uint, slug, checksum string) (uint, error) {
+	var count int64
+	if err := r.tx.Unscoped().Model(&models.Post{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		r.report.add(Change{Type: entityPost, Action: ActionConflict, Key: p.ExternalID, Title: p.Title,
+			Detail: fmt.Sprintf("slug %q 已被其他文章使用", slug)})
+		return 0, nil
+	}
+
+	tags, err := r.resolveTags(p.Tags)
+	if err != nil {
+		return 0, err
+	}
+
+	post := models.Post{
+		Title:       p.Title,
+		Content:     p.Content,
+		Summary:     p.Summary,
+		Slug:        slug,
+		Status:      p.Status,
+		IsPublic:    p.Public,
+		PublishedAt: p.PublishedAt,
+		CreatedAt:   p.Date,
+		UpdatedAt:   p.Updated,
+		UserID:      authorID,
+	}
+	if post.Status == "" {
+		post.Status = models.PostStatusDraft
+	}
+	if post.Status == models.PostStatusPublished && post.PublishedAt == nil {
+		now := time.Now()
+		post.PublishedAt = &now
+	}
+	if err := r.tx.Create(&post).Error; err != nil {
+		return 0, err
+	}
+	// Post.IsPublic 带有 default:true，插入时 false 会被写成 true，私密文章需要在插入后单独写入
+	if !p.Public {
+		post.IsPublic = false
+		if err := r.tx.Model(&post).UpdateColumn("is_public", false).Error; err != nil {
+			return 0, err
+		}
+	}
+	if len(tags) > 0 {
+		if err := r.tx.Model(&post).Association("Tags").Append(tags); err != nil {
+			return 0, err
+		}
+	}
+
+	record := models.ImportRecord{
+		Source:     r.doc.Source,
+		EntityType: entityPost,
+		ExternalID: p.ExternalID,
+		EntityID:   post.ID,
+		Checksum:   checksum,
+	}
+	if err := r.tx.Create(&record).Error; err != nil {
+		return 0, err
+	}
+
+	r.report.add(Change{Type: entityPost, Action: ActionCreate, Key: p.ExternalID, Title: p.Title,
+		Detail: fmt.Sprintf("slug=%s status=%s tags=%s", slug, post.Status, strings.Join(p.Tags, ","))})
+	return post.ID, nil
+}
+
+// updatePost 更新已导入的文章，内容未变化时跳过；本地文章已删除时返回 0
+func (r *importRun) updatePost(p *Post, record *models.ImportRecord, authorID uint, slug, checksum string) (uint, error) {
+	if record.Checksum == checksum {
+		r.report.add(Change{Type: entityPost, Action: ActionSkip, Key: p.ExternalID, Title: p.Title, Detail: "内容未变化"})
+		return record.EntityID, nil
+	}
+
+	var post models.Post
+	if err := r.tx.First(&post, record.EntityID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.report.add(Change{Type: entityPost, Action: ActionSkip, Key: p.ExternalID, Title: p.Title, Detail: "本地文章已删除"})
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var fields []string
+	updates := map[string]interface{}{}
+	if post.Title != p.Title {
+		updates["title"] = p.Title
+		fields = append(fields, "title")
+	}
+	if post.Content != p.Content {
+		updates["content"] = p.Content
+		fields = append(fields, "content")
+	}
+	if p.Summary != "" && post.Summary != p.Summary {
+		updates["summary"] = p.Summary
+		fields = append(fields, "summary")
+	}
+	if p.Status != "" && post.Status != p.Status {
+		updates["status"] = p.Status
+		fields = append(fields, "status")
+		if p.Status == models.PostStatusPublished && post.PublishedAt == nil {
+			publishedAt := p.PublishedAt
+			if publishedAt == nil {
+				now := time.Now()
+				publishedAt = &now
+			}
+			updates["published_at"] = publishedAt
+		}
+	}
+	if post.IsPublic != p.Public {
+		updates["is_public"] = p.Public
+		fields = append(fields, "is_public")
+	}
+	if post.UserID != authorID {
+		updates["user_id"] = authorID
+		fields = append(fields, "author")
+	}
+	if post.Slug != slug {
+		var count int64
+		if err := r.tx.Unscoped().Model(&models.Post{}).Where("slug = ? AND id <> ?", slug, post.ID).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		if count > 0 {
+			r.report.add(Change{Type: entityPost, Action: ActionConflict, Key: p.ExternalID, Title: p.Title,
+				Detail: fmt.Sprintf("slug %q 已被其他文章使用，保留原 slug %q", slug, post.Slug)})
+		} else {
+			updates["slug"] = slug
+			fields = append(fields, "slug")
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := r.tx.Model(&post).Updates(updates).Error; err != nil {
+			return 0, err
+		}
+		if uid, ok := updates["user_id"]; ok {
+			// 作者变化时同步两边的文章数
+			if err := r.tx.Model(&models.User{}).Where("id = ?", post.UserID).
+				Update("post_count", gorm.Expr("post_count - ?", 1)).Error; err != nil {
+				return 0, err
+			}
+			if err := r.tx.Model(&models.User{}).Where("id = ?", uid).
+				Update("post_count", gorm.Expr("post_count + ?", 1)).Error; err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	tagsChanged, err := r.syncTags(&post, p.Tags)
+	if err != nil {
+		return 0, err
+	}
+	if tagsChanged {
+		fields = append(fields, "tags")
+	}
+
+	if err := r.tx.Model(record).Update("checksum", checksum).Error; err != nil {
+		return 0, err
+	}
+
+	if len(fields) == 0 {
+		r.report.add(Change{Type: entityPost, Action: ActionSkip, Key: p.ExternalID, Title: p.Title, Detail: "内容未变化"})
+		return post.ID, nil
+	}
+	r.report.add(Change{Type: entityPost, Action: ActionUpdate, Key: p.ExternalID, Title: p.Title,
+		Detail: "更新字段: " + strings.Join(fields, ",")})
+	return post.ID, nil
+}
+
+// syncTags 使文章标签与来源一致，返回是否有变化
+func (r *importRun) syncTags(post *models.Post, names []string) (bool, error) {
+	var current []models.Tag
+	if err := r.tx.Model(post).Association("Tags").Find(&current); err != nil {
+		return false, err
+	}
+	have := make([]string, 0, len(current))
+	for _, t := range current {
+		have = append(have, strings.ToLower(t.Name))
+	}
+	want := make([]string, 0, len(names))
+	for _, n := range names {
+		want = append(want, strings.ToLower(n))
+	}
+	sort.Strings(have)
+	sort.Strings(want)
+	if strings.Join(have, "\x00") == strings.Join(want, "\x00") {
+		return false, nil
+	}
+
+	tags, err := r.resolveTags(names)
+	if err != nil {
+		return false, err
+	}
+	if err := r.tx.Model(post).Association("Tags").Replace(tags); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resolveTags 按名称查找标签，不存在时创建
+func (r *importRun) resolveTags(names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		if tag, ok := r.tags[key]; ok {
+			tags = append(tags, *tag)
+			continue
+		}
+
+		slug := utils.Slugify(name)
+		var tag models.Tag
+		query := r.tx.Where("LOWER(name) = ?", key)
+		if slug != "" {
+			query = query.Or("slug = ?", slug)
+		}
+		err := query.First(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if slug == "" {
+				slug = "tag-" + shortHash(name)
+			}
+			tag = models.Tag{Name: name, Slug: slug}
+			if err := r.tx.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+			r.report.add(Change{Type: "tag", Action: ActionCreate, Key: name, Detail: "slug=" + slug})
+		} else if err != nil {
+			return nil, err
+		}
+
+		r.tags[key] = &tag
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// resolveAuthor 将来源作者映射为本地用户 ID，无法匹配时返回 0
+func (r *importRun) resolveAuthor(login string) (uint, error) {
+	if r.opts.ForceAuthorID != 0 {
+		return r.opts.ForceAuthorID, nil
+	}
+
+	if mapped, ok := r.opts.AuthorMap[login]; ok {
+		return r.lookupUser(mapped)
+	}
+	if login != "" {
+		id, err := r.lookupUser(login)
+		if err != nil || id != 0 {
+			return id, err
+		}
+		if r.opts.CreateMissingAuthors {
+			return r.createAuthor(login)
+		}
+	}
+	if r.opts.DefaultAuthor != "" {
+		return r.lookupUser(r.opts.DefaultAuthor)
+	}
+	return 0, nil
+}
+
+// lookupUser 按用户名查找本地用户，结果会被缓存
+func (r *importRun) lookupUser(username string) (uint, error) {
+	if id, ok := r.users[username]; ok {
+		return id, nil
+	}
+	var user models.User
+	err := r.tx.Select("id").Where("username = ?", username).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	r.users[username] = user.ID
+	return user.ID, nil
+}
+
+// createAuthor 为来源作者创建本地用户（随机密码，需要管理员重置后才能登录）
+func (r *importRun) createAuthor(login string) (uint, error) {
+	author := r.authors[login]
+	email := author.Email
+	if email == "" {
+		email = utils.Slugify(login) + "@import.invalid"
+	}
+
+	var count int64
+	if err := r.tx.Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		r.report.add(Change{Type: "user", Action: ActionConflict, Key: login, Detail: fmt.Sprintf("邮箱 %s 已被其他用户使用", email)})
+		r.users[login] = 0
+		return 0, nil
+	}
+
+	password, err := utils.GenerateRandomPassword(24)
+	if err != nil {
+		return 0, err
+	}
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+	user := models.User{Username: login, Email: email, Password: hashed, Bio: author.DisplayName}
+	if err := r.tx.Create(&user).Error; err != nil {
+		return 0, err
+	}
+
+	r.users[login] = user.ID
+	r.report.add(Change{Type: "user", Action: ActionCreate, Key: login, Detail: "email=" + email})
+	return user.ID, nil
+}
+
+// importComments 导入文章评论，已导入的评论会被跳过；父评论先于子评论创建
+func (r *importRun) importComments(p *Post, postID uint) error {
+	if len(p.Comments) == 0 || postID == 0 {
+		return nil
+	}
+
+	// 已导入评论的外部 ID -> 本地 ID
+	prefix := p.ExternalID + "#"
+	var records []models.ImportRecord
+	if err := r.tx.Where("source = ? AND entity_type = ? AND external_id LIKE ?", r.doc.Source, entityComment, escapeLike(prefix)+"%").
+		Find(&records).Error; err != nil {
+		return err
+	}
+	local := make(map[string]uint, len(records))
+	for _, rec := range records {
+		local[strings.TrimPrefix(rec.ExternalID, prefix)] = rec.EntityID
+	}
+
+	known := make(map[string]bool, len(p.Comments))
+	for _, c := range p.Comments {
+		known[c.ExternalID] = true
+	}
+
+	pending := make([]Comment, 0, len(p.Comments))
+	for _, c := range p.Comments {
+		if _, ok := local[c.ExternalID]; ok {
+			r.report.add(Change{Type: entityComment, Action: ActionSkip, Key: prefix + c.ExternalID, Title: p.Title, Detail: "已导入"})
+			continue
+		}
+		pending = append(pending, c)
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].Date.Before(pending[j].Date) })
+
+	// 按层级逐轮创建，父评论不在来源中时作为顶级评论导入
+	for len(pending) > 0 {
+		var next []Comment
+		for _, c := range pending {
+			var parentID *uint
+			if c.ParentExternalID != "" && known[c.ParentExternalID] {
+				id, ok := local[c.ParentExternalID]
+				if !ok {
+					next = append(next, c)
+					continue
+				}
+				parentID = &id
+			}
+			id, err := r.createComment(p, prefix, postID, parentID, c)
+			if err != nil {
+				return err
+			}
+			if id != 0 {
+				local[c.ExternalID] = id
+			}
+		}
+		if len(next) == len(pending) {
+			// 父评论导入失败，剩余评论作为顶级评论导入
+			for i := range next {
+				next[i].ParentExternalID = ""
+			}
+		}
+		pending = next
+	}
+	return nil
+}
+
+// createComment 创建单条评论，评论者不是本地用户时归属到备用用户并在内容前注明原作者
+func (r *importRun) createComment(p *Post, prefix string, postID uint, parentID *uint, c Comment) (uint, error) {
+	key := prefix + c.ExternalID
+	content := c.Content
+
+	userID, err := r.resolveCommenter(c)
+	if err != nil {
+		return 0, err
+	}
+	if userID == 0 {
+		fallback, err := r.fallbackCommenter()
+		if err != nil {
+			return 0, err
+		}
+		if fallback == 0 {
+			r.report.add(Change{Type: entityComment, Action: ActionConflict, Key: key, Title: p.Title,
+				Detail: fmt.Sprintf("评论者 %q 无法匹配本地用户", c.Author)})
+			return 0, nil
+		}
+		userID = fallback
+		if c.Author != "" {
+			content = fmt.Sprintf("**%s**: %s", c.Author, content)
+		}
+	}
+
+	comment := models.Comment{
+		Content:    content,
+		IsApproved: c.Approved,
+		CreatedAt:  c.Date,
+		UserID:     userID,
+		PostID:     postID,
+		ParentID:   parentID,
+	}
+	if err := r.tx.Create(&comment).Error; err != nil {
+		return 0, err
+	}
+	record := models.ImportRecord{
+		Source:     r.doc.Source,
+		EntityType: entityComment,
+		ExternalID: key,
+		EntityID:   comment.ID,
+	}
+	if err := r.tx.Create(&record).Error; err != nil {
+		return 0, err
+	}
+
+	r.report.add(Change{Type: entityComment, Action: ActionCreate, Key: key, Title: p.Title, Detail: "author=" + c.Author})
+	return comment.ID, nil
+}
+
+// resolveCommenter 按用户名或邮箱匹配本地用户
+func (r *importRun) resolveCommenter(c Comment) (uint, error) {
+	if mapped, ok := r.opts.AuthorMap[c.Author]; ok {
+		return r.lookupUser(mapped)
+	}
+	if c.Author != "" {
+		id, err := r.lookupUser(c.Author)
+		if err != nil || id != 0 {
+			return id, err
+		}
+	}
+	if c.AuthorEmail != "" {
+		var user models.User
+		err := r.tx.Select("id").Where("email = ?", c.AuthorEmail).First(&user).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		return user.ID, nil
+	}
+	return 0, nil
+}
+
+// fallbackCommenter 备用评论用户
+func (r *importRun) fallbackCommenter() (uint, error) {
+	if r.opts.FallbackCommenter != "" {
+		return r.lookupUser(r.opts.FallbackCommenter)
+	}
+	return r.opts.ForceAuthorID, nil
+}
+
+// shortHash 短摘要，用于无法生成 ASCII slug 的名称
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\\`, `\\\\`, "%", `\\%`, "_", `\\_`).Replace(s)
+}
@@ -0,0 +1,219 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-system/models"
+	"blog-system/utils"
+)
+
+// jekyllNameRe Jekyll 文章文件名：2024-01-31-my-post.md
+var jekyllNameRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+// archiveComment 本系统导出归档中 comments.json 的评论格式
+type archiveComment struct {
+	ID         uint      `json:"id"`
+	PostSlug   string    `json:"post_slug"`
+	ParentID   *uint     `json:"parent_id"`
+	Author     string    `json:"author"`
+	Content    string    `json:"content"`
+	IsApproved bool      `json:"is_approved"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ParseMarkdown 解析目录或 ZIP 中带 front matter 的 Markdown 文件（Hugo、Jekyll 及本系统导出的归档）
+func ParseMarkdown(fsys fs.FS, source string) (*Document, error) {
+	doc := &Document{Source: source, Format: "markdown"}
+	authors := make(map[string]bool)
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// 跳过隐藏目录和 Hugo 主题等非内容目录
+			if p != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == "themes" || d.Name() == "node_modules") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(path.Ext(p))
+		if ext != ".md" && ext != ".markdown" {
+			return nil
+		}
+		base := path.Base(p)
+		if strings.HasPrefix(base, "_") || strings.EqualFold(base, "README.md") {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		post, err := parseMarkdownPost(p, content)
+		if err != nil {
+			return fmt.Errorf("%s: %v", p, err)
+		}
+		if post.Author != "" && !authors[post.Author] {
+			authors[post.Author] = true
+			doc.Authors = append(doc.Authors, Author{Login: post.Author})
+		}
+		doc.Posts = append(doc.Posts, post)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := attachArchiveComments(fsys, doc); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(doc.Posts, func(i, j int) bool {
+		return doc.Posts[i].Date.Before(doc.Posts[j].Date)
+	})
+	return doc, nil
+}
+
+// parseMarkdownPost 解析单个 Markdown 文件
+func parseMarkdownPost(p string, content []byte) (Post, error) {
+	fm, body, err := splitFrontMatter(content)
+	if err != nil {
+		return Post{}, err
+	}
+
+	name := strings.TrimSuffix(path.Base(p), path.Ext(p))
+	if name == "index" {
+		// Hugo page bundle: posts/my-post/index.md
+		name = path.Base(path.Dir(p))
+	}
+
+	var fileDate time.Time
+	if m := jekyllNameRe.FindStringSubmatch(name); m != nil {
+		fileDate, _ = time.ParseInLocation("2006-01-02", m[1], time.Local)
+		name = m[2]
+	}
+
+	post := Post{
+		Title:   fm.str("title"),
+		Slug:    fm.str("slug"),
+		Content: body,
+		Summary: fm.str("summary", "description", "excerpt"),
+		Public:  true,
+		Author:  fm.str("author", "authors"),
+		Tags:    dedupe(fm.list("tags", "categories")),
+	}
+
+	if post.Title == "" {
+		post.Title, post.Content = titleFromBody(body, name)
+	}
+	if post.Slug == "" {
+		post.Slug = utils.Slugify(name)
+	}
+
+	post.Date, _ = fm.date("date")
+	if post.Date.IsZero() {
+		post.Date = fileDate
+	}
+	post.Updated, _ = fm.date("updated", "lastmod", "last_modified_at")
+
+	// 状态：本系统归档的 status 优先，其次 Hugo draft、Jekyll published/_drafts
+	post.Status = models.PostStatusPublished
+	switch status := models.PostStatus(fm.str("status")); status {
+	case models.PostStatusDraft, models.PostStatusPublished, models.PostStatusArchived:
+		post.Status = status
+	default:
+		if draft, _ := fm.boolean("draft"); draft {
+			post.Status = models.PostStatusDraft
+		}
+		if published, ok := fm.boolean("published"); ok && !published {
+			post.Status = models.PostStatusDraft
+		}
+		if strings.Contains("/"+p, "/_drafts/") {
+			post.Status = models.PostStatusDraft
+		}
+	}
+	if public, ok := fm.boolean("public"); ok {
+		post.Public = public
+	}
+
+	if t, ok := fm.date("published_at"); ok {
+		post.PublishedAt = &t
+	} else if post.Status == models.PostStatusPublished && !post.Date.IsZero() {
+		t := post.Date
+		post.PublishedAt = &t
+	}
+
+	post.ExternalID = post.Slug
+	if post.ExternalID == "" {
+		post.ExternalID = p
+	}
+	return post, nil
+}
+
+// titleFromBody 没有 title 字段时取第一个一级标题（并从正文中移除），否则使用文件名
+func titleFromBody(body, fallback string) (string, string) {
+	trimmed := strings.TrimLeft(body, "\n")
+	if strings.HasPrefix(trimmed, "# ") {
+		line, rest, _ := strings.Cut(trimmed, "\n")
+		return strings.TrimSpace(strings.TrimPrefix(line, "# ")), strings.TrimLeft(rest, "\n")
+	}
+	return strings.ReplaceAll(fallback, "-", " "), body
+}
+
+// attachArchiveComments 读取本系统归档中的 comments.json 并挂到对应文章下
+func attachArchiveComments(fsys fs.FS, doc *Document) error {
+	data, err := fs.ReadFile(fsys, "comments.json")
+	if err != nil {
+		return nil
+	}
+
+	var comments []archiveComment
+	if err := json.Unmarshal(data, &comments); err != nil {
+		return fmt.Errorf("comments.json: %v", err)
+	}
+
+	bySlug := make(map[string]*Post, len(doc.Posts))
+	for i := range doc.Posts {
+		bySlug[doc.Posts[i].Slug] = &doc.Posts[i]
+	}
+	for _, c := range comments {
+		post, ok := bySlug[c.PostSlug]
+		if !ok {
+			continue
+		}
+		comment := Comment{
+			ExternalID: fmt.Sprint(c.ID),
+			Author:     c.Author,
+			Content:    c.Content,
+			Date:       c.CreatedAt,
+			Approved:   c.IsApproved,
+		}
+		if c.ParentID != nil {
+			comment.ParentExternalID = fmt.Sprint(*c.ParentID)
+		}
+		post.Comments = append(post.Comments, comment)
+	}
+	return nil
+}
+
+// dedupe 去除重复项并保持顺序
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var out []string
+	for _, item := range items {
+		key := strings.ToLower(item)
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
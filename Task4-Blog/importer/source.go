@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 支持的导入格式
+const (
+	FormatAuto     = "auto"
+	FormatMarkdown = "markdown"
+	FormatWXR      = "wxr"
+)
+
+// Load 从本地路径读取导入内容：目录、ZIP 压缩包或 WXR 文件
+func Load(path, format string) (*Document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(filepath.Clean(path))
+
+	if info.IsDir() {
+		if format == FormatWXR {
+			return nil, fmt.Errorf("WXR 格式需要指定 XML 文件")
+		}
+		return ParseMarkdown(os.DirFS(path), "markdown:"+name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(name, f, info.Size(), format)
+}
+
+// Parse 解析上传的文件，name 用于判断文件类型和生成来源标识
+func Parse(name string, r io.ReaderAt, size int64, format string) (*Document, error) {
+	if format == "" {
+		format = FormatAuto
+	}
+	if format != FormatAuto && format != FormatMarkdown && format != FormatWXR {
+		return nil, fmt.Errorf("不支持的导入格式: %s", format)
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".zip":
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return nil, fmt.Errorf("读取 ZIP 失败: %v", err)
+		}
+		return parseZip(zr, name, format)
+	case ".xml":
+		if format == FormatMarkdown {
+			return nil, fmt.Errorf("Markdown 格式需要目录或 ZIP 压缩包")
+		}
+		return ParseWXR(io.NewSectionReader(r, 0, size))
+	case ".md", ".markdown":
+		if format == FormatWXR {
+			return nil, fmt.Errorf("WXR 格式需要指定 XML 文件")
+		}
+		content, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		post, err := parseMarkdownPost(name, content)
+		if err != nil {
+			return nil, err
+		}
+		doc := &Document{Source: "markdown:" + name, Format: FormatMarkdown, Posts: []Post{post}}
+		if post.Author != "" {
+			doc.Authors = []Author{{Login: post.Author}}
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("无法识别的文件类型: %s（支持目录、.zip、.xml、.md）", name)
+	}
+}
+
+// parseZip 解析 ZIP 压缩包：包含 WXR 文件时按 WXR 解析，否则按 Markdown 目录解析
+func parseZip(zr *zip.Reader, name, format string) (*Document, error) {
+	if format != FormatMarkdown {
+		var xmlFiles []*zip.File
+		for _, f := range zr.File {
+			if strings.EqualFold(filepath.Ext(f.Name), ".xml") && !f.FileInfo().IsDir() {
+				xmlFiles = append(xmlFiles, f)
+			}
+		}
+		if len(xmlFiles) == 1 {
+			rc, err := xmlFiles[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			if err != nil {
+				return nil, err
+			}
+			return ParseWXR(bytes.NewReader(data))
+		}
+		if format == FormatWXR {
+			return nil, fmt.Errorf("ZIP 中应包含且仅包含一个 WXR 文件，实际 %d 个", len(xmlFiles))
+		}
+	}
+
+	// 压缩包只有一个顶层目录时以该目录为根
+	var fsys fs.FS = zr
+	if root := singleRoot(zr); root != "" {
+		sub, err := fs.Sub(zr, root)
+		if err != nil {
+			return nil, err
+		}
+		fsys = sub
+	}
+	return ParseMarkdown(fsys, "markdown:"+strings.TrimSuffix(name, filepath.Ext(name)))
+}
+
+// singleRoot 返回 ZIP 唯一的顶层目录名，不存在时返回空
+func singleRoot(zr *zip.Reader) string {
+	root := ""
+	for _, f := range zr.File {
+		first, rest, found := strings.Cut(f.Name, "/")
+		if !found || (rest == "" && !f.FileInfo().IsDir()) {
+			return ""
+		}
+		if root != "" && root != first {
+			return ""
+		}
+		root = first
+	}
+	return root
+}
+
+// ParseAuthorMap 解析作者映射，格式为 "来源登录名=本地用户名,..."
+func ParseAuthorMap(s string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("作者映射格式不正确: %q，应为 来源登录名=本地用户名", pair)
+		}
+		m[from] = to
+	}
+	return m, nil
+}
@@ -0,0 +1,66 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-system/models"
+)
+
+// Author 来源中的作者
+type Author struct {
+	Login       string
+	Email       string
+	DisplayName string
+}
+
+// Comment 来源中的评论
+type Comment struct {
+	ExternalID       string
+	ParentExternalID string
+	Author           string
+	AuthorEmail      string
+	Content          string
+	Date             time.Time
+	Approved         bool
+}
+
+// Post 来源中的文章
+type Post struct {
+	ExternalID  string
+	Title       string
+	Slug        string
+	Content     string
+	Summary     string
+	Status      models.PostStatus
+	Public      bool
+	Author      string // 作者登录名
+	Tags        []string
+	Date        time.Time
+	Updated     time.Time
+	PublishedAt *time.Time
+	Comments    []Comment
+}
+
+// Checksum 文章内容摘要，内容未变化时重复导入会跳过
+func (p *Post) Checksum() string {
+	tags := append([]string(nil), p.Tags...)
+	sort.Strings(tags)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00%t\x00%s\x00%s",
+		p.Title, p.Slug, p.Content, p.Summary, p.Status, p.Public, p.Author, strings.Join(tags, ","))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Document 解析后的导入内容
+type Document struct {
+	Source  string // 来源标识，用于区分不同站点的外部 ID
+	Format  string // markdown, wxr
+	Authors []Author
+	Posts   []Post
+}
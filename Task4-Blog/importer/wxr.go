@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"blog-system/models"
+	"blog-system/utils"
+)
+
+// WordPress WXR 导出文件结构（仅包含需要的字段）
+type wxrRSS struct {
+	Channel wxrChannel `xml:"channel"`
+}
+
+type wxrChannel struct {
+	Link    string      `xml:"link"`
+	BaseURL string      `xml:"http://wordpress.org/export/1.2/ base_site_url"`
+	Authors []wxrAuthor `xml:"http://wordpress.org/export/1.2/ author"`
+	Items   []wxrItem   `xml:"item"`
+}
+
+type wxrAuthor struct {
+	Login       string `xml:"http://wordpress.org/export/1.2/ author_login"`
+	Email       string `xml:"http://wordpress.org/export/1.2/ author_email"`
+	DisplayName string `xml:"http://wordpress.org/export/1.2/ author_display_name"`
+}
+
+type wxrItem struct {
+	Title      string        `xml:"title"`
+	Creator    string        `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Content    string        `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Excerpt    string        `xml:"http://wordpress.org/export/1.2/excerpt/ encoded"`
+	PostID     string        `xml:"http://wordpress.org/export/1.2/ post_id"`
+	PostDate   string        `xml:"http://wordpress.org/export/1.2/ post_date_gmt"`
+	PostDateLo string        `xml:"http://wordpress.org/export/1.2/ post_date"`
+	Modified   string        `xml:"http://wordpress.org/export/1.2/ post_modified_gmt"`
+	PostName   string        `xml:"http://wordpress.org/export/1.2/ post_name"`
+	Status     string        `xml:"http://wordpress.org/export/1.2/ status"`
+	PostType   string        `xml:"http://wordpress.org/export/1.2/ post_type"`
+	Password   string        `xml:"http://wordpress.org/export/1.2/ post_password"`
+	Categories []wxrCategory `xml:"category"`
+	Comments   []wxrComment  `xml:"http://wordpress.org/export/1.2/ comment"`
+}
+
+type wxrCategory struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+type wxrComment struct {
+	ID          string `xml:"http://wordpress.org/export/1.2/ comment_id"`
+	Author      string `xml:"http://wordpress.org/export/1.2/ comment_author"`
+	AuthorEmail string `xml:"http://wordpress.org/export/1.2/ comment_author_email"`
+	DateGMT     string `xml:"http://wordpress.org/export/1.2/ comment_date_gmt"`
+	Content     string `xml:"http://wordpress.org/export/1.2/ comment_content"`
+	Approved    string `xml:"http://wordpress.org/export/1.2/ comment_approved"`
+	Type        string `xml:"http://wordpress.org/export/1.2/ comment_type"`
+	Parent      string `xml:"http://wordpress.org/export/1.2/ comment_parent"`
+}
+
+// wxrTimeLayout WXR 中的时间格式
+const wxrTimeLayout = "2006-01-02 15:04:05"
+
+// ParseWXR 解析 WordPress 导出的 WXR（XML）文件，只导入文章类型的条目
+func ParseWXR(r io.Reader) (*Document, error) {
+	var rss wxrRSS
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	if err := decoder.Decode(&rss); err != nil {
+		return nil, fmt.Errorf("解析 WXR 失败: %v", err)
+	}
+
+	site := rss.Channel.BaseURL
+	if site == "" {
+		site = rss.Channel.Link
+	}
+	doc := &Document{Source: "wxr:" + strings.TrimRight(site, "/"), Format: "wxr"}
+
+	for _, a := range rss.Channel.Authors {
+		doc.Authors = append(doc.Authors, Author{Login: a.Login, Email: a.Email, DisplayName: a.DisplayName})
+	}
+
+	for _, item := range rss.Channel.Items {
+		if item.PostType != "post" {
+			continue
+		}
+		status, ok := wxrStatus(item.Status)
+		if !ok {
+			continue
+		}
+
+		post := Post{
+			ExternalID: item.PostID,
+			Title:      strings.TrimSpace(item.Title),
+			Slug:       item.PostName,
+			Content:    item.Content,
+			Summary:    strings.TrimSpace(item.Excerpt),
+			Status:     status,
+			Public:     item.Status != "private" && item.Password == "",
+			Author:     item.Creator,
+		}
+		if post.Slug == "" {
+			post.Slug = utils.Slugify(post.Title)
+		}
+
+		post.Date = wxrTime(item.PostDate, item.PostDateLo)
+		post.Updated = wxrTime(item.Modified, "")
+		if status == models.PostStatusPublished && !post.Date.IsZero() {
+			t := post.Date
+			post.PublishedAt = &t
+		}
+
+		var tags []string
+		for _, cat := range item.Categories {
+			if (cat.Domain == "post_tag" || cat.Domain == "category") && cat.Name != "Uncategorized" {
+				tags = append(tags, strings.TrimSpace(cat.Name))
+			}
+		}
+		post.Tags = dedupe(tags)
+
+		for _, c := range item.Comments {
+			// 跳过 pingback/trackback 和垃圾评论
+			if c.Type == "pingback" || c.Type == "trackback" || c.Approved == "spam" || c.Approved == "trash" {
+				continue
+			}
+			comment := Comment{
+				ExternalID:  c.ID,
+				Author:      c.Author,
+				AuthorEmail: c.AuthorEmail,
+				Content:     c.Content,
+				Date:        wxrTime(c.DateGMT, ""),
+				Approved:    c.Approved == "1",
+			}
+			if c.Parent != "" && c.Parent != "0" {
+				comment.ParentExternalID = c.Parent
+			}
+			post.Comments = append(post.Comments, comment)
+		}
+
+		doc.Posts = append(doc.Posts, post)
+	}
+
+	return doc, nil
+}
+
+// wxrStatus 将 WordPress 文章状态映射为本系统状态，回收站中的文章不导入
+func wxrStatus(status string) (models.PostStatus, bool) {
+	switch status {
+	case "publish", "private":
+		return models.PostStatusPublished, true
+	case "draft", "pending", "future":
+		return models.PostStatusDraft, true
+	default:
+		return "", false
+	}
+}
+
+// wxrTime 解析 WXR 时间，优先使用 GMT 时间
+func wxrTime(gmt, local string) time.Time {
+	if gmt != "" && gmt != "0000-00-00 00:00:00" {
+		if t, err := time.ParseInLocation(wxrTimeLayout, gmt, time.UTC); err == nil {
+			return t
+		}
+	}
+	if local != "" && local != "0000-00-00 00:00:00" {
+		if t, err := time.ParseInLocation(wxrTimeLayout, local, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,273 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job 周期性后台任务
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Status 后台任务运行状态
+type Status struct {
+	Name      string        `json:"name"`
+	Interval  time.Duration `json:"interval"`
+	Running   bool          `json:"running"`
+	LastRun   time.Time     `json:"last_run"`
+	LastError string        `json:"last_error,omitempty"`
+	Heartbeat time.Time     `json:"heartbeat"`
+}
+
+// Scheduler 后台任务调度器
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []Job
+	status  map[string]*Status
+	hooks   []func(ctx context.Context) error
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewScheduler 创建调度器实例
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		status: make(map[string]*Status),
+	}
+}
+
+// defaultScheduler 全局调度器实例
+var defaultScheduler = NewScheduler()
+
+// Default 获取全局调度器
+func Default() *Scheduler {
+	return defaultScheduler
+}
+
+// Register 注册周期性任务（启动后注册的任务会立即运行）
+func (s *Scheduler) Register(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	s.status[job.Name] = &Status{Name: job.Name, Interval: job.Interval}
+
+	if s.started {
+		s.startJob(job)
+	}
+}
+
+// OnStop 注册停止时执行的回调（如刷新缓冲区），按注册的逆序执行
+func (s *Scheduler) OnStop(fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, fn)
+}
+
+// Start 启动所有已注册的任务
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.started = true
+
+	for _, job := range s.jobs {
+		s.startJob(job)
+	}
+	log.Printf("后台任务已启动: %d 个", len(s.jobs))
+}
+
+// Go 在调度器管理下运行一次性后台任务，停止时会等待其结束。
+// 调度器未启动（命令行）或已停止时直接在当前 goroutine 中执行，保证任务在进程退出前完成
+func (s *Scheduler) Go(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		runTask(context.Background(), name, fn)
+		return
+	}
+	// 在持有锁时计数，Stop 将 started 置为 false 后不会再有新的任务加入等待
+	ctx := s.ctx
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.wg.Done()
+		runTask(ctx, name, fn)
+	}()
+}
+
+// runTask 执行一次性任务，记录错误和 panic
+func runTask(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("后台任务 %s 发生 panic: %v", name, r)
+		}
+	}()
+	if err := fn(ctx); err != nil {
+		log.Printf("后台任务 %s 执行失败: %v", name, err)
+	}
+}
+
+// startJob 启动单个任务的循环（调用方需持有锁）
+func (s *Scheduler) startJob(job Job) {
+	st := s.status[job.Name]
+	st.Running = true
+	st.Heartbeat = time.Now()
+
+	s.wg.Add(1)
+	go func(ctx context.Context) {
+		defer s.wg.Done()
+		defer s.markStopped(job.Name)
+
+		ticker := time.NewTicker(job.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runOnce(ctx, job)
+			}
+		}
+	}(s.ctx)
+}
+
+// runOnce 执行一次任务并记录状态
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		err = job.Run(ctx)
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.status[job.Name]
+	st.LastRun = time.Now()
+	st.Heartbeat = st.LastRun
+	st.LastError = ""
+	if err != nil {
+		st.LastError = err.Error()
+		log.Printf("后台任务 %s 执行失败: %v", job.Name, err)
+	}
+}
+
+// markStopped 标记任务已停止
+func (s *Scheduler) markStopped(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status[name].Running = false
+}
+
+// Stop 停止所有任务并执行停止回调。等待任务结束和执行回调各自最多使用 timeout，
+// 等待超时后仍然执行回调，保证缓冲区在关闭数据库前写入
+func (s *Scheduler) Stop(timeout time.Duration) error {
+	var waitErr error
+	s.mu.Lock()
+	if s.started {
+		s.cancel()
+		s.started = false
+		s.mu.Unlock()
+		waitErr = s.wait(timeout)
+	} else {
+		s.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return errors.Join(waitErr, s.runHooks(ctx))
+}
+
+// wait 等待所有任务结束
+func (s *Scheduler) wait(timeout time.Duration) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("后台任务已停止")
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("等待后台任务停止超时 (%s)", timeout)
+	}
+}
+
+// runHooks 逆序执行停止回调
+func (s *Scheduler) runHooks(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := make([]func(ctx context.Context) error, len(s.hooks))
+	copy(hooks, s.hooks)
+	s.mu.Unlock()
+
+	var firstErr error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Statuses 获取所有任务的状态快照
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		statuses = append(statuses, *s.status[job.Name])
+	}
+	return statuses
+}
+
+// Register 在全局调度器上注册任务
+func Register(job Job) {
+	defaultScheduler.Register(job)
+}
+
+// OnStop 在全局调度器上注册停止回调
+func OnStop(fn func(ctx context.Context) error) {
+	defaultScheduler.OnStop(fn)
+}
+
+// Go 在全局调度器上运行一次性后台任务
+func Go(name string, fn func(ctx context.Context) error) {
+	defaultScheduler.Go(name, fn)
+}
+
+// Start 启动全局调度器
+func Start() {
+	defaultScheduler.Start()
+}
+
+// Stop 停止全局调度器
+func Stop(timeout time.Duration) error {
+	return defaultScheduler.Stop(timeout)
+}
+
+// Statuses 获取全局调度器的任务状态
+func Statuses() []Status {
+	return defaultScheduler.Statuses()
+}
@@ -1,74 +1,41 @@
 package middleware
 
 import (
+	"blog-system/models"
 	"blog-system/services"
 	"blog-system/utils"
+	"errors"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 )
 
-// AuthMiddleware JWT 认证中间件
+// AuthMiddleware 认证中间件，接受登录 JWT 和个人访问令牌（pat_ 开头）
 type AuthMiddleware struct {
-	authService *services.AuthService
+	authService  *services.AuthService
+	tokenService *services.PersonalAccessTokenService
+	// tokenScopes 接受个人访问令牌的接口及所需的权限范围，键为 "方法 路由"，如 "POST /api/v1/posts"。
+	// 未列出的接口不接受个人访问令牌；权限范围为空表示由处理函数自行检查
+	tokenScopes map[string]models.TokenScope
 }
 
 // NewAuthMiddleware 创建认证中间件实例
-func NewAuthMiddleware(authService *services.AuthService) *AuthMiddleware {
+func NewAuthMiddleware(authService *services.AuthService, tokenService *services.PersonalAccessTokenService, tokenScopes map[string]models.TokenScope) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
+		authService:  authService,
+		tokenService: tokenService,
+		tokenScopes:  tokenScopes,
 	}
 }
 
 // AuthRequired 需要认证的中间件
 func (am *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 从请求头中获取 token
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			utils.UnauthorizedResponse(c, "缺少认证token")
-			c.Abort()
-			return
-		}
-
-		// 检查 token 格式 (Bearer token)
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			utils.UnauthorizedResponse(c, "token格式错误")
-			c.Abort()
+		if !am.authenticate(c) {
 			return
 		}
 
-		tokenString := parts[1]
-
-		// 验证 token
-		token, err := am.authService.ValidateToken(tokenString)
-		if err != nil || !token.Valid {
-			utils.UnauthorizedResponse(c, "token无效或已过期")
-			c.Abort()
-			return
-		}
-
-		// 从 token 中提取用户信息
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			utils.UnauthorizedResponse(c, "token解析失败")
-			c.Abort()
-			return
-		}
-
-		// 将用户信息存储到上下文中
-		userID := uint(claims["user_id"].(float64))
-		username := claims["username"].(string)
-		email := claims["email"].(string)
-		role := claims["role"].(string)
-
-		c.Set("userID", userID)
-		c.Set("username", username)
-		c.Set("email", email)
-		c.Set("role", role)
-
 		c.Next()
 	}
 }
@@ -90,27 +57,13 @@ func (am *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := am.authService.ValidateToken(tokenString)
-		if err != nil || !token.Valid {
+		user, status, _ := am.verify(c, tokenString)
+		if status != 0 {
 			c.Next()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.Next()
-			return
-		}
-
-		userID := uint(claims["user_id"].(float64))
-		username := claims["username"].(string)
-		email := claims["email"].(string)
-		role := claims["role"].(string)
-
-		c.Set("userID", userID)
-		c.Set("username", username)
-		c.Set("email", email)
-		c.Set("role", role)
+		setUser(c, user)
 
 		c.Next()
 	}
@@ -120,8 +73,7 @@ func (am *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 func (am *AuthMiddleware) AdminRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 先进行认证
-		am.AuthRequired()(c)
-		if c.IsAborted() {
+		if !am.authenticate(c) {
 			return
 		}
 
@@ -132,11 +84,107 @@ func (am *AuthMiddleware) AdminRequired() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		// 管理员角色要求两步验证时，登录时必须通过了两步验证
+		if services.TwoFactorRequired("admin") && !c.GetBool("twoFactor") {
+			utils.ForbiddenResponse(c, "需要先开启两步验证并使用两步验证登录")
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// authenticate 验证请求头中的 token 并将用户信息存储到上下文中，失败时中止请求
+func (am *AuthMiddleware) authenticate(c *gin.Context) bool {
+	// 从请求头中获取 token
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		utils.UnauthorizedResponse(c, "缺少认证token")
+		c.Abort()
+		return false
+	}
+
+	// 检查 token 格式 (Bearer token)
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		utils.UnauthorizedResponse(c, "token格式错误")
+		c.Abort()
+		return false
+	}
+
+	tokenString := parts[1]
+
+	// 验证 token 并提取用户信息
+	user, status, message := am.verify(c, tokenString)
+	if status != 0 {
+		switch status {
+		case http.StatusUnauthorized:
+			utils.UnauthorizedResponse(c, message)
+		case http.StatusForbidden:
+			utils.ForbiddenResponse(c, message)
+		default:
+			utils.ErrorResponse(c, status, "认证失败", message)
+		}
+		c.Abort()
+		return false
+	}
+
+	// 将用户信息存储到上下文中
+	setUser(c, user)
+	return true
+}
+
+// verify 验证 token，失败时返回状态码和错误信息。
+// 个人访问令牌还要检查当前接口是否接受令牌，以及令牌是否拥有接口所需的权限范围
+func (am *AuthMiddleware) verify(c *gin.Context, tokenString string) (*services.TokenUser, int, string) {
+	if !services.IsPersonalAccessToken(tokenString) {
+		user, err := am.authService.Authenticate(tokenString)
+		if err != nil {
+			return nil, http.StatusUnauthorized, "token无效或已过期"
+		}
+		return user, 0, ""
+	}
+
+	user, err := am.tokenService.Authenticate(tokenString, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrAccessTokenInvalid) {
+			return nil, http.StatusUnauthorized, err.Error()
+		}
+		return nil, http.StatusInternalServerError, "验证访问令牌失败"
+	}
+
+	scope, accepted := am.tokenScopes[c.Request.Method+" "+c.FullPath()]
+	if !accepted {
+		return nil, http.StatusForbidden, "该接口不接受个人访问令牌"
+	}
+	if scope != "" && !user.HasScope(string(scope)) {
+		return nil, http.StatusForbidden, "访问令牌缺少权限: " + string(scope)
+	}
+	return user, 0, ""
+}
+
+// setUser 将 token 中的用户信息存储到上下文中，个人访问令牌额外存储权限范围。
+// 用户同时写入请求的 context，作为审计日志的操作者
+func setUser(c *gin.Context, user *services.TokenUser) {
+	c.Set("userID", user.ID)
+	c.Set("username", user.Username)
+	c.Set("email", user.Email)
+	c.Set("role", user.Role)
+	c.Set("twoFactor", user.TwoFactor)
+	if user.IsAccessToken() {
+		c.Set("scopes", user.Scopes)
+	}
+
+	actor := services.ActorFrom(c.Request.Context())
+	actor.UserID = user.ID
+	actor.Username = user.Username
+	if actor.IP == "" {
+		actor.IP = c.ClientIP()
+	}
+	c.Request = c.Request.WithContext(services.WithActor(c.Request.Context(), actor))
+}
+
 // GetUserFromContext 从上下文中获取用户信息
 func GetUserFromContext(c *gin.Context) (userID uint, username, email, role string, exists bool) {
 	userIDVal, exists := c.Get("userID")
@@ -1,71 +1,165 @@
 package middleware
 
 import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"blog-system/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-// CORS 跨域中间件
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-		
-		// 设置允许的域名，* 表示允许所有域名
-		if origin != "" {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-		} else {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+// originMatcher 匹配允许的来源
+type originMatcher struct {
+	any      bool                // 允许所有来源
+	exact    map[string]struct{} // 完整来源，如 https://example.com
+	wildcard [][2]string         // 子域名通配，https://*.example.com 保存为 {"https://", ".example.com"}
+}
+
+func newOriginMatcher(origins []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]struct{})}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "*":
+			m.any = true
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			m.wildcard = append(m.wildcard, [2]string{origin[:i], origin[i+1:]})
+		case origin != "":
+			m.exact[origin] = struct{}{}
 		}
-		
-		// 设置允许的请求方法
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		// 设置允许的请求头
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, Cache-Control, X-Requested-With")
-		// 设置是否允许携带凭证（cookies）
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		// 设置预检请求缓存时间
-		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
-		// 设置暴露的响应头
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Authorization")
-
-		// 处理预检请求
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	}
+	return m
+}
+
+// match 检查来源是否允许。通配只匹配子域名部分，不能跨越协议、端口或路径
+func (m *originMatcher) match(origin string) bool {
+	if m.any {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+	for _, w := range m.wildcard {
+		if !strings.HasPrefix(origin, w[0]) || !strings.HasSuffix(origin, w[1]) {
+			continue
+		}
+		sub := origin[len(w[0]) : len(origin)-len(w[1])]
+		if sub != "" && !strings.ContainsAny(sub, ":/@") && !strings.HasPrefix(sub, ".") && !strings.HasSuffix(sub, ".") {
+			return true
 		}
+	}
+	return false
+}
 
-		c.Next()
+// corsRules 由跨域配置生成的规则
+type corsRules struct {
+	origins       *originMatcher
+	credentials   bool
+	methods       map[string]struct{}
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	maxAge        string
+}
+
+func newCORSRules(cfg config.CORSConfig) *corsRules {
+	r := &corsRules{
+		origins:       newOriginMatcher(cfg.AllowedOrigins),
+		credentials:   cfg.AllowCredentials,
+		methods:       make(map[string]struct{}, len(cfg.AllowedMethods)),
+		allowMethods:  strings.Join(cfg.AllowedMethods, ", "),
+		allowHeaders:  strings.Join(cfg.AllowedHeaders, ", "),
+		exposeHeaders: strings.Join(cfg.ExposedHeaders, ", "),
+		maxAge:        strconv.Itoa(cfg.MaxAge),
+	}
+	if r.credentials && r.origins.any {
+		log.Printf("警告: cors.allowed_origins 为 * 时不能允许携带凭证，已忽略 cors.allow_credentials")
+		r.credentials = false
+	}
+	for _, method := range cfg.AllowedMethods {
+		r.methods[strings.ToUpper(method)] = struct{}{}
 	}
+	return r
 }
 
-// CORSCustom 自定义跨域配置
-func CORSCustom(allowedOrigins []string) gin.HandlerFunc {
+// CORSMiddleware 跨域中间件，配置可在运行期间更新
+type CORSMiddleware struct {
+	rules atomic.Pointer[corsRules]
+}
+
+// NewCORSMiddleware 创建跨域中间件
+func NewCORSMiddleware(cfg config.CORSConfig) *CORSMiddleware {
+	m := &CORSMiddleware{}
+	m.Update(cfg)
+	return m
+}
+
+// Update 替换跨域配置，之后的请求立即使用新配置
+func (m *CORSMiddleware) Update(cfg config.CORSConfig) {
+	m.rules.Store(newCORSRules(cfg))
+}
+
+// Handler 按配置的来源白名单处理跨域请求。
+// 允许的来源原样写入 Access-Control-Allow-Origin；不允许的来源不返回跨域响应头，预检请求直接返回 403
+func (m *CORSMiddleware) Handler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-		
-		// 检查请求来源是否在允许列表中
-		allowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if allowedOrigin == "*" || allowedOrigin == origin {
-				allowed = true
-				break
-			}
+		r := m.rules.Load()
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// 响应内容随 Origin 变化，缓存需要区分
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
 		}
 
-		if allowed {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+		// 不是跨域请求
+		if origin == "" {
+			c.Next()
+			return
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, Authorization")
+		if !r.origins.match(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if r.origins.any && !r.credentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if r.credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			if _, ok := r.methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))]; !ok {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			header.Set("Access-Control-Allow-Methods", r.allowMethods)
+			header.Set("Access-Control-Allow-Headers", r.allowHeaders)
+			header.Set("Access-Control-Max-Age", r.maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
+		if r.exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", r.exposeHeaders)
+		}
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -4,12 +4,50 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// 请求日志级别
+const (
+	logLevelDebug int32 = iota
+	logLevelInfo
+	logLevelWarn
+	logLevelError
+)
+
+// requestLogLevel 当前请求日志级别，零值为 debug
+var requestLogLevel atomic.Int32
+
+// SetLogLevel 设置请求日志级别：debug、info 记录所有请求，warn 只记录 4xx/5xx，error 只记录 5xx
+func SetLogLevel(level string) {
+	switch level {
+	case "info":
+		requestLogLevel.Store(logLevelInfo)
+	case "warn":
+		requestLogLevel.Store(logLevelWarn)
+	case "error":
+		requestLogLevel.Store(logLevelError)
+	default:
+		requestLogLevel.Store(logLevelDebug)
+	}
+}
+
+// shouldLog 按当前日志级别判断是否记录该状态码的请求
+func shouldLog(statusCode int) bool {
+	switch requestLogLevel.Load() {
+	case logLevelWarn:
+		return statusCode >= 400
+	case logLevelError:
+		return statusCode >= 500
+	default:
+		return true
+	}
+}
+
 // Logger 日志中间件
 func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -35,6 +73,9 @@ func Logger() gin.HandlerFunc {
 		
 		// 状态码
 		statusCode := c.Writer.Status()
+		if !shouldLog(statusCode) {
+			return
+		}
 		
 		// 客户端IP
 		clientIP := c.ClientIP()
@@ -49,10 +90,11 @@ func Logger() gin.HandlerFunc {
 		errors := c.Errors.ByType(gin.ErrorTypePrivate).String()
 
 		// 记录日志
-		log.Printf("| %3d | %13v | %15s | %-7s %s | %s | %s | %s",
+		log.Printf("| %3d | %13v | %15s | %s | %-7s %s | %s | %s | %s",
 			statusCode,
 			cost,
 			clientIP,
+			c.GetString("requestID"),
 			method,
 			path,
 			query,
@@ -62,7 +104,7 @@ func Logger() gin.HandlerFunc {
 
 		// 如果是错误响应，记录更多信息
 		if statusCode >= 400 {
-			log.Printf("Error Request Body: %s", string(requestBody))
+			log.Printf("Error Request Body [%s]: %s", c.GetString("requestID"), string(requestBody))
 		}
 	}
 }
@@ -87,6 +129,7 @@ func ZapLogger(logger *zap.Logger) gin.HandlerFunc {
 			zap.String("path", path),
 			zap.String("query", query),
 			zap.String("ip", c.ClientIP()),
+			zap.String("request_id", c.GetString("requestID")),
 			zap.String("user-agent", c.Request.UserAgent()),
 			zap.Duration("latency", latency),
 			zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()),
@@ -100,7 +143,7 @@ func Recovery() gin.HandlerFunc {
 		defer func() {
 			if err := recover(); err != nil {
 				// 记录 panic 信息
-				log.Printf("Panic recovered: %v", err)
+				log.Printf("Panic recovered [%s]: %v", c.GetString("requestID"), err)
 				
 				// 返回 500 错误
 				c.JSON(500, gin.H{
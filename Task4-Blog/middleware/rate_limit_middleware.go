@@ -1,65 +1,71 @@
 package middleware
 
 import (
+	"fmt"
+	"log"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"blog-system/config"
+	"blog-system/ratelimit"
 	"blog-system/utils"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
-// RateLimiter 限流器
+// RateLimiter 限流器，按路由分组的配额限制请求频率
 type RateLimiter struct {
-	ips map[string]*rate.Limiter
-	mu   sync.RWMutex
-	r    rate.Limit
-	b    int
+	store    ratelimit.Store
+	policies atomic.Pointer[map[string]config.RateLimitPolicy]
 }
 
 // NewRateLimiter 创建限流器
-func NewRateLimiter(r rate.Limit, b int) *RateLimiter {
-	return &RateLimiter{
-		ips: make(map[string]*rate.Limiter),
-		mu:  sync.RWMutex{},
-		r:   r,
-		b:   b,
-	}
+func NewRateLimiter(store ratelimit.Store, policies map[string]config.RateLimitPolicy) *RateLimiter {
+	rl := &RateLimiter{store: store}
+	rl.Update(policies)
+	return rl
 }
 
-// AddIP 添加 IP 到限流器
-func (rl *RateLimiter) AddIP(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	limiter := rate.NewLimiter(rl.r, rl.b)
-	rl.ips[ip] = limiter
-
-	return limiter
+// Update 替换各分组的配额，之后的请求立即使用新配额
+func (rl *RateLimiter) Update(policies map[string]config.RateLimitPolicy) {
+	rl.policies.Store(&policies)
 }
 
-// GetLimiter 获取 IP 的限流器
-func (rl *RateLimiter) GetLimiter(ip string) *rate.Limiter {
-	rl.mu.RLock()
-	limiter, exists := rl.ips[ip]
-	rl.mu.RUnlock()
+// Limit 按名为 policy 的配额限流的中间件，配额未配置或 limit 为 0 时不限制。
+// 已登录的请求按用户ID计数，需放在认证中间件之后；否则按 IP 计数。
+// 响应中带有 RateLimit-Limit、RateLimit-Remaining、RateLimit-Reset 头，被拒绝时还带有 Retry-After
+func (rl *RateLimiter) Limit(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := (*rl.policies.Load())[policy]
+		if !ok || p.Limit <= 0 || p.Window <= 0 {
+			c.Next()
+			return
+		}
+		window := time.Duration(p.Window) * time.Second
 
-	if !exists {
-		return rl.AddIP(ip)
-	}
+		key := policy + ":ip:" + c.ClientIP()
+		if userID, exists := c.Get("userID"); exists {
+			key = fmt.Sprintf("%s:user:%d", policy, userID)
+		}
 
-	return limiter
-}
+		result, err := rl.store.Take(c.Request.Context(), key, p.Limit, window)
+		if err != nil {
+			// 存储不可用时放行，避免限流故障导致整站不可用
+			log.Printf("限流检查失败 (%s): %v", policy, err)
+			c.Next()
+			return
+		}
 
-// RateLimit 限流中间件
-func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := rl.GetLimiter(ip)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Limit, p.Window))
 
-		if !limiter.Allow() {
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
 			utils.ErrorResponse(c, http.StatusTooManyRequests, "请求过于频繁", "请稍后再试")
 			c.Abort()
 			return
@@ -69,51 +75,7 @@ func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 	}
 }
 
-// GlobalRateLimit 全局限流中间件
-func GlobalRateLimit(requestsPerSecond int, burst int) gin.HandlerFunc {
-	limiter := rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
-	
-	return func(c *gin.Context) {
-		if !limiter.Allow() {
-			utils.ErrorResponse(c, http.StatusTooManyRequests, "系统繁忙", "请稍后再试")
-			c.Abort()
-			return
-		}
-		
-		c.Next()
-	}
+// ceilSeconds 向上取整的秒数
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
 }
-
-// TokenBucketRateLimit 令牌桶限流中间件
-func TokenBucketRateLimit(fillInterval time.Duration, capacity int64) gin.HandlerFunc {
-	tokenBucket := make(chan struct{}, capacity)
-	
-	// 初始化令牌桶
-	for i := int64(0); i < capacity; i++ {
-		tokenBucket <- struct{}{}
-	}
-	
-	// 定时添加令牌
-	go func() {
-		ticker := time.NewTicker(fillInterval)
-		defer ticker.Stop()
-		
-		for range ticker.C {
-			select {
-			case tokenBucket <- struct{}{}:
-			default:
-				// 桶已满，丢弃令牌
-			}
-		}
-	}()
-	
-	return func(c *gin.Context) {
-		select {
-		case <-tokenBucket:
-			c.Next()
-		default:
-			utils.ErrorResponse(c, http.StatusTooManyRequests, "请求过于频繁", "请稍后再试")
-			c.Abort()
-		}
-	}
-}
\ No newline at end of file
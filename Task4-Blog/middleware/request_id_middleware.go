@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID 请求 ID 中间件：沿用请求头 X-Request-ID 中合法的 ID，否则生成新的 ID，
+// 写入响应头，并与客户端 IP 一起存入 context 供日志和审计日志使用
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(utils.RequestIDHeader)
+		if !utils.ValidRequestID(id) {
+			id = utils.NewRequestID()
+		}
+
+		c.Set("requestID", id)
+		c.Header(utils.RequestIDHeader, id)
+		ctx := services.WithActor(c.Request.Context(), services.Actor{IP: c.ClientIP(), RequestID: id})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
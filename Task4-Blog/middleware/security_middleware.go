@@ -1,25 +1,95 @@
 package middleware
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"blog-system/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-// SecurityHeaders 安全头中间件
-func SecurityHeaders() gin.HandlerFunc {
-	return func(c *gin.Context) {
+// securityHeaders 按配置生成安全响应头
+func securityHeaders(cfg config.SecurityConfig) map[string]string {
+	headers := map[string]string{
 		// 防止 XSS 攻击
-		c.Writer.Header().Set("X-XSS-Protection", "1; mode=block")
-		// 防止点击劫持
-		c.Writer.Header().Set("X-Frame-Options", "DENY")
+		"X-XSS-Protection": "1; mode=block",
 		// 防止 MIME 类型嗅探
-		c.Writer.Header().Set("X-Content-Type-Options", "nosniff")
-		// HSTS 强制 HTTPS
-		c.Writer.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
-		// 内容安全策略
-		c.Writer.Header().Set("Content-Security-Policy", "default-src 'self'")
-		// 引用策略
-		c.Writer.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		"X-Content-Type-Options": "nosniff",
+	}
+
+	// 防止点击劫持
+	if cfg.FrameOptions != "" {
+		headers["X-Frame-Options"] = cfg.FrameOptions
+	}
+	// 引用策略
+	if cfg.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = cfg.ReferrerPolicy
+	}
+	// 内容安全策略
+	if csp := buildCSP(cfg.CSP); csp != "" {
+		headers["Content-Security-Policy"] = csp
+	}
+	// HSTS 强制 HTTPS
+	if cfg.HSTS.Enabled {
+		hsts := fmt.Sprintf("max-age=%d", cfg.HSTS.MaxAge)
+		if cfg.HSTS.IncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTS.Preload {
+			hsts += "; preload"
+		}
+		headers["Strict-Transport-Security"] = hsts
+	}
+	return headers
+}
+
+// buildCSP 按指令名排序生成 Content-Security-Policy，来源列表为空的指令只输出指令名
+func buildCSP(directives map[string][]string) string {
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, strings.TrimSpace(name+" "+strings.Join(directives[name], " ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SecurityMiddleware 安全头中间件，CSP、HSTS 等按配置生成，配置可在运行期间更新
+type SecurityMiddleware struct {
+	headers atomic.Pointer[map[string]string]
+}
+
+// NewSecurityMiddleware 创建安全头中间件
+func NewSecurityMiddleware(cfg config.SecurityConfig) *SecurityMiddleware {
+	m := &SecurityMiddleware{}
+	m.Update(cfg)
+	return m
+}
+
+// Update 替换安全头配置，之后的请求立即使用新配置
+func (m *SecurityMiddleware) Update(cfg config.SecurityConfig) {
+	headers := securityHeaders(cfg)
+	m.headers.Store(&headers)
+}
+
+// setHeaders 写入安全头
+func (m *SecurityMiddleware) setHeaders(c *gin.Context) {
+	for name, value := range *m.headers.Load() {
+		c.Writer.Header().Set(name, value)
+	}
+}
 
+// Headers 设置安全头
+func (m *SecurityMiddleware) Headers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.setHeaders(c)
 		c.Next()
 	}
 }
@@ -27,24 +97,28 @@ func SecurityHeaders() gin.HandlerFunc {
 // NoCache 禁止缓存中间件
 func NoCache() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Cache-Control", "no-cache, no-store, max-age=0, must-revalidate, value")
-		c.Writer.Header().Set("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
-		c.Writer.Header().Set("Last-Modified", "Thu, 01 Jan 1970 00:00:00 GMT")
+		setNoCache(c)
 		c.Next()
 	}
 }
 
-// Secure 安全中间件
-func Secure() gin.HandlerFunc {
+func setNoCache(c *gin.Context) {
+	c.Writer.Header().Set("Cache-Control", "no-cache, no-store, max-age=0, must-revalidate, value")
+	c.Writer.Header().Set("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
+	c.Writer.Header().Set("Last-Modified", "Thu, 01 Jan 1970 00:00:00 GMT")
+}
+
+// Secure 设置安全头，API 请求同时禁止缓存
+func (m *SecurityMiddleware) Secure() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 设置安全头
-		SecurityHeaders()(c)
-		
+		m.setHeaders(c)
+
 		// 对于 API 请求，禁止缓存
-		if c.Request.URL.Path == "/api/" || c.Request.URL.Path[:4] == "/api" {
-			NoCache()(c)
+		if strings.HasPrefix(c.Request.URL.Path, "/api") {
+			setNoCache(c)
 		}
-		
+
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// AuditAction 审计操作类型
+type AuditAction string
+
+const (
+	AuditPostCreate      AuditAction = "post.create"      // 创建文章
+	AuditPostUpdate      AuditAction = "post.update"      // 修改文章
+	AuditPostDelete      AuditAction = "post.delete"      // 删除文章（移入回收站）
+	AuditPostRestore     AuditAction = "post.restore"     // 从回收站恢复文章
+	AuditPostPurge       AuditAction = "post.purge"       // 永久删除文章
+	AuditCommentCreate   AuditAction = "comment.create"   // 发表评论
+	AuditCommentUpdate   AuditAction = "comment.update"   // 修改评论
+	AuditCommentDelete   AuditAction = "comment.delete"   // 删除评论（移入回收站）
+	AuditCommentRestore  AuditAction = "comment.restore"  // 从回收站恢复评论
+	AuditCommentPurge    AuditAction = "comment.purge"    // 永久删除评论
+	AuditCommentModerate AuditAction = "comment.moderate" // 管理员通过或驳回评论
+	AuditUserRole        AuditAction = "user.role_change" // 修改用户角色
+	AuditUserBan         AuditAction = "user.ban"         // 封禁用户
+	AuditUserUnban       AuditAction = "user.unban"       // 解封用户
+	AuditUserLogin       AuditAction = "user.login"       // 登录成功
+)
+
+// AuditActionList 所有审计操作类型
+var AuditActionList = []AuditAction{
+	AuditPostCreate,
+	AuditPostUpdate,
+	AuditPostDelete,
+	AuditPostRestore,
+	AuditPostPurge,
+	AuditCommentCreate,
+	AuditCommentUpdate,
+	AuditCommentDelete,
+	AuditCommentRestore,
+	AuditCommentPurge,
+	AuditCommentModerate,
+	AuditUserRole,
+	AuditUserBan,
+	AuditUserUnban,
+	AuditUserLogin,
+}
+
+// 审计对象类型
+const (
+	AuditTargetPost    = "post"
+	AuditTargetComment = "comment"
+	AuditTargetUser    = "user"
+)
+
+// AuditSnapshot 操作前后的对象快照（JSON），在接口中按原样输出
+type AuditSnapshot []byte
+
+// Value 实现 driver.Valuer
+func (s AuditSnapshot) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	return string(s), nil
+}
+
+// Scan 实现 sql.Scanner
+func (s *AuditSnapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*s = AuditSnapshot(v)
+	case []byte:
+		*s = append(AuditSnapshot(nil), v...)
+	case nil:
+		*s = nil
+	default:
+		return fmt.Errorf("无法将 %T 转换为 AuditSnapshot", value)
+	}
+	return nil
+}
+
+// MarshalJSON 输出快照本身，没有快照时为 null
+func (s AuditSnapshot) MarshalJSON() ([]byte, error) {
+	if len(s) == 0 {
+		return []byte("null"), nil
+	}
+	return s, nil
+}
+
+// AuditLog 审计日志，只追加不修改，超过保留期后由后台任务删除
+type AuditLog struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	ActorID    *uint         `gorm:"index:idx_audit_logs_actor_created,priority:1" json:"actor_id,omitempty"` // 操作者，命令行操作时为空
+	ActorName  string        `gorm:"size:50;not null" json:"actor_name"`                                      // 操作者用户名，命令行操作时为 cli
+	Action     AuditAction   `gorm:"size:50;not null;index" json:"action"`
+	TargetType string        `gorm:"size:30;not null;index:idx_audit_logs_target,priority:1" json:"target_type"`
+	TargetID   uint          `gorm:"not null;index:idx_audit_logs_target,priority:2" json:"target_id"`
+	Before     AuditSnapshot `gorm:"type:text" json:"before"` // 操作前的快照，创建时为空
+	After      AuditSnapshot `gorm:"type:text" json:"after"`  // 操作后的快照，删除时为空
+	IP         string        `gorm:"size:45" json:"ip,omitempty"`
+	RequestID  string        `gorm:"size:64;index" json:"request_id,omitempty"`
+	CreatedAt  time.Time     `gorm:"index;index:idx_audit_logs_actor_created,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
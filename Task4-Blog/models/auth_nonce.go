@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AuthNonce 钱包登录的一次性 nonce，使用后删除
+type AuthNonce struct {
+	Nonce     string    `gorm:"primaryKey;size:32" json:"nonce"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuthNonce) TableName() string {
+	return "auth_nonces"
+}
@@ -1,19 +1,37 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// CommentSort 评论排序方式
+type CommentSort string
+
+const (
+	CommentSortOldest CommentSort = "oldest" // 最早的在前
+	CommentSortNewest CommentSort = "newest" // 最新的在前
+	CommentSortTop    CommentSort = "top"    // 回复最多的在前
+)
+
+// CommentSortList 所有评论排序方式
+var CommentSortList = []CommentSort{
+	CommentSortOldest,
+	CommentSortNewest,
+	CommentSortTop,
+}
+
 // Comment 评论模型
 type Comment struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Content   string    `gorm:"type:text;not null" json:"content"`
-	IsApproved bool     `gorm:"default:true" json:"is_approved"` // 评论是否审核通过
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // 软删除
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Content    string         `gorm:"type:text;not null" json:"content"`
+	IsApproved bool           `gorm:"not null" json:"is_approved"` // 评论是否审核通过，创建时按审核结果写入（不设默认值，否则 false 会被写成 true）
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"` // 软删除
 
 	// 外键关系
 	UserID uint `gorm:"not null;index" json:"user_id"`
@@ -23,8 +41,15 @@ type Comment struct {
 	Post   Post `gorm:"foreignKey:PostID" json:"post,omitempty"`
 
 	// 父评论ID（支持回复功能）
-	ParentID *uint    `gorm:"index" json:"parent_id,omitempty"`
+	ParentID *uint     `gorm:"index" json:"parent_id,omitempty"`
 	Replies  []Comment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+
+	// 祖先评论ID路径（物化路径），如 3/17/，顶级评论为空，创建时由钩子填写
+	Path       string `gorm:"type:text;not null" json:"-"`
+	ReplyCount int    `gorm:"default:0" json:"reply_count"` // 审核通过的直接回复数
+
+	EditedAt    *time.Time `json:"edited_at,omitempty"` // 最后一次修改的时间，未修改过时为空
+	ModeratedAt *time.Time `json:"-"`                   // 管理员最后一次审核的时间，为空表示只经过自动审核
 }
 
 // TableName 指定表名
@@ -32,33 +57,55 @@ func (Comment) TableName() string {
 	return "comments"
 }
 
-// BeforeCreate 创建前的钩子函数
+// Depth 评论所在的层级，顶级评论为 0
+func (c *Comment) Depth() int {
+	return strings.Count(c.Path, "/")
+}
+
+// BeforeCreate 创建前的钩子函数 - 根据父评论填写物化路径
 func (c *Comment) BeforeCreate(tx *gorm.DB) error {
-	// 可以在这里设置默认值
+	if c.ParentID == nil || c.Path != "" {
+		return nil
+	}
+	var parent Comment
+	if err := tx.Unscoped().Select("id", "path").First(&parent, *c.ParentID).Error; err != nil {
+		return err
+	}
+	c.Path = fmt.Sprintf("%s%d/", parent.Path, parent.ID)
 	return nil
 }
 
-// AfterCreate 创建后的钩子函数 - 更新文章的评论数量
+// AfterCreate 创建后的钩子函数 - 更新文章的评论数量和父评论的回复数量
 func (c *Comment) AfterCreate(tx *gorm.DB) error {
 	if c.IsApproved {
-		return tx.Model(&Post{}).Where("id = ?", c.PostID).
-			Update("comment_count", gorm.Expr("comment_count + ?", 1)).Error
+		return c.updateCounters(tx, 1)
 	}
 	return nil
 }
 
+// updateCounters 更新文章的评论数量和父评论的回复数量
+func (c *Comment) updateCounters(tx *gorm.DB, delta int) error {
+	if err := tx.Model(&Post{}).Where("id = ?", c.PostID).
+		Update("comment_count", gorm.Expr("comment_count + ?", delta)).Error; err != nil {
+		return err
+	}
+	if c.ParentID == nil {
+		return nil
+	}
+	return tx.Unscoped().Model(&Comment{}).Where("id = ?", *c.ParentID).
+		Update("reply_count", gorm.Expr("reply_count + ?", delta)).Error
+}
+
 // BeforeDelete 删除前的钩子函数
 func (c *Comment) BeforeDelete(tx *gorm.DB) error {
 	return nil
 }
 
-// AfterDelete 删除后的钩子函数 - 检查并更新文章评论状态
+// AfterDelete 删除后的钩子函数 - 检查并更新文章评论状态和父评论的回复数量
 func (c *Comment) AfterDelete(tx *gorm.DB) error {
 	if c.IsApproved {
-		// 更新文章的评论数量
-		err := tx.Model(&Post{}).Where("id = ?", c.PostID).
-			Update("comment_count", gorm.Expr("comment_count - ?", 1)).Error
-		if err != nil {
+		// 更新文章的评论数量和父评论的回复数量
+		if err := c.updateCounters(tx, -1); err != nil {
 			return err
 		}
 
@@ -77,17 +124,84 @@ func (c *Comment) AfterDelete(tx *gorm.DB) error {
 	return nil
 }
 
+// Restore 从回收站恢复评论，并恢复 AfterDelete 减去的文章评论数量和父评论回复数量
+func (c *Comment) Restore(tx *gorm.DB) error {
+	if err := tx.Unscoped().Model(c).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	c.DeletedAt = gorm.DeletedAt{}
+	if c.IsApproved {
+		return c.updateCounters(tx, 1)
+	}
+	return nil
+}
+
+// Edit 修改评论内容，并保存修改前的版本。审核状态变化时同步文章的评论数量和父评论的回复数量
+func (c *Comment) Edit(tx *gorm.DB, content string, approved bool) error {
+	revision := CommentRevision{CommentID: c.ID, Content: c.Content, IsApproved: c.IsApproved}
+	if err := tx.Create(&revision).Error; err != nil {
+		return err
+	}
+
+	wasApproved := c.IsApproved
+	editedAt := revision.CreatedAt
+	if err := tx.Model(c).Updates(map[string]interface{}{
+		"content":     content,
+		"is_approved": approved,
+		"edited_at":   editedAt,
+	}).Error; err != nil {
+		return err
+	}
+	c.Content, c.IsApproved, c.EditedAt = content, approved, &editedAt
+	return c.approvalChanged(tx, wasApproved)
+}
+
+// Moderate 管理员通过或驳回评论，记录审核时间，审核状态变化时同步文章的评论数量和父评论的回复数量
+func (c *Comment) Moderate(tx *gorm.DB, approved bool) error {
+	wasApproved := c.IsApproved
+	now := time.Now()
+	if err := tx.Model(c).Updates(map[string]interface{}{
+		"is_approved":  approved,
+		"moderated_at": now,
+	}).Error; err != nil {
+		return err
+	}
+	c.IsApproved, c.ModeratedAt = approved, &now
+	return c.approvalChanged(tx, wasApproved)
+}
+
+// RejectedByModerator 评论是否被管理员驳回
+func (c *Comment) RejectedByModerator() bool {
+	return c.ModeratedAt != nil && !c.IsApproved
+}
+
+// approvalChanged 审核状态变化时同步文章的评论数量和父评论的回复数量
+func (c *Comment) approvalChanged(tx *gorm.DB, wasApproved bool) error {
+	switch {
+	case wasApproved && !c.IsApproved:
+		return c.updateCounters(tx, -1)
+	case !wasApproved && c.IsApproved:
+		return c.updateCounters(tx, 1)
+	}
+	return nil
+}
+
 // CommentResponse 评论响应结构
 type CommentResponse struct {
-	ID         uint      `json:"id"`
-	Content    string    `json:"content"`
-	IsApproved bool      `json:"is_approved"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	User       UserResponse `json:"user"`
-	PostID     uint      `json:"post_id"`
-	ParentID   *uint     `json:"parent_id,omitempty"`
+	ID         uint              `json:"id"`
+	Content    string            `json:"content"`
+	IsApproved bool              `json:"is_approved"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	EditedAt   *time.Time        `json:"edited_at,omitempty"` // 修改过的评论为最后一次修改的时间
+	User       UserResponse      `json:"user"`
+	PostID     uint              `json:"post_id"`
+	ParentID   *uint             `json:"parent_id,omitempty"`
+	Depth      int               `json:"depth"`       // 所在层级，顶级评论为 0
+	ReplyCount int               `json:"reply_count"` // 审核通过的直接回复数
 	Replies    []CommentResponse `json:"replies,omitempty"`
+	// 还有未返回的回复时，传给 GET /api/v1/comments/:id/replies 继续加载
+	RepliesCursor string `json:"replies_cursor,omitempty"`
 }
 
 // ToResponse 转换为响应结构体
@@ -104,9 +218,12 @@ func (c *Comment) ToResponse() CommentResponse {
 		IsApproved: c.IsApproved,
 		CreatedAt:  c.CreatedAt,
 		UpdatedAt:  c.UpdatedAt,
+		EditedAt:   c.EditedAt,
 		User:       c.User.ToResponse(),
 		PostID:     c.PostID,
 		ParentID:   c.ParentID,
+		Depth:      c.Depth(),
+		ReplyCount: c.ReplyCount,
 		Replies:    replies,
 	}
-}
\ No newline at end of file
+}
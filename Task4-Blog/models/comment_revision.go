@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CommentRevision 评论修改前的版本，每次修改保存一条，只有管理员可以查看
+type CommentRevision struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CommentID  uint      `gorm:"not null;index" json:"comment_id"`
+	Content    string    `gorm:"type:text;not null" json:"content"` // 修改前的内容
+	IsApproved bool      `gorm:"not null" json:"is_approved"`       // 修改前的审核状态
+	CreatedAt  time.Time `json:"created_at"`                        // 修改时间，即这个版本被替换的时间
+}
+
+// TableName 指定表名
+func (CommentRevision) TableName() string {
+	return "comment_revisions"
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+// ExportScope 导出范围
+type ExportScope string
+
+const (
+	ExportScopeUser ExportScope = "user" // 导出单个用户的博客
+	ExportScopeSite ExportScope = "site" // 导出全站（管理员）
+)
+
+// ExportStatus 导出任务状态
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"   // 等待执行
+	ExportStatusRunning   ExportStatus = "running"   // 执行中
+	ExportStatusCompleted ExportStatus = "completed" // 已完成
+	ExportStatusFailed    ExportStatus = "failed"    // 失败
+	ExportStatusExpired   ExportStatus = "expired"   // 文件已过期清理
+)
+
+// ExportJob 导出任务模型
+type ExportJob struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Scope       ExportScope  `gorm:"size:20;not null" json:"scope"`
+	UserID      uint         `gorm:"not null;index" json:"user_id"` // 导出的用户（全站导出时为发起的管理员）
+	Status      ExportStatus `gorm:"size:20;not null;default:'pending';index" json:"status"`
+	FilePath    string       `gorm:"size:500" json:"-"` // 归档文件路径，不对外暴露
+	FileSize    int64        `gorm:"not null;default:0" json:"file_size"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	StartedAt   *time.Time   `json:"started_at,omitempty"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"` // 归档文件过期时间
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// ImportRecord 导入记录，关联外部来源中的条目与本地数据
+type ImportRecord struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Source     string    `gorm:"size:255;not null;uniqueIndex:idx_import_records_key" json:"source"`      // 来源标识，如 wxr:https://example.com
+	EntityType string    `gorm:"size:20;not null;uniqueIndex:idx_import_records_key" json:"entity_type"`  // post, comment
+	ExternalID string    `gorm:"size:255;not null;uniqueIndex:idx_import_records_key" json:"external_id"` // 来源中的 ID
+	EntityID   uint      `gorm:"not null" json:"entity_id"`                                               // 本地 ID
+	Checksum   string    `gorm:"size:64" json:"checksum"`                                                 // 内容摘要，用于判断是否需要更新
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ImportRecord) TableName() string {
+	return "import_records"
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// LoginResult 登录尝试的结果
+type LoginResult string
+
+const (
+	LoginResultSuccess  LoginResult = "success"  // 密码验证通过
+	LoginResultFailed   LoginResult = "failed"   // 用户名或密码错误
+	LoginResultBlocked  LoginResult = "blocked"  // 因失败次数过多被拒绝，未验证密码
+	LoginResultUnlocked LoginResult = "unlocked" // 管理员解除锁定
+)
+
+// 登录失败的原因
+const (
+	LoginReasonUnknownUser   = "unknown_user"
+	LoginReasonInactive      = "inactive"
+	LoginReasonWrongPassword = "wrong_password"
+	LoginReasonAccountLocked = "account_locked"
+	LoginReasonIPLocked      = "ip_locked"
+)
+
+// LoginAttempt 登录尝试记录，用于失败次数统计、锁定和审计
+type LoginAttempt struct {
+	ID         uint        `gorm:"primarykey" json:"id"`
+	UserID     *uint       `gorm:"index:idx_login_attempts_user_created,priority:1" json:"user_id,omitempty"`                  // 用户不存在时为空
+	Identifier string      `gorm:"size:100;not null;index:idx_login_attempts_identifier_created,priority:1" json:"identifier"` // 提交的用户名或邮箱（小写）
+	IP         string      `gorm:"size:45;not null;index:idx_login_attempts_ip_created,priority:1" json:"ip"`
+	Result     LoginResult `gorm:"size:20;not null" json:"result"`
+	Reason     string      `gorm:"size:50" json:"reason,omitempty"` // 失败或拒绝的原因；解除锁定时为操作的管理员
+	CreatedAt  time.Time   `gorm:"index:idx_login_attempts_user_created,priority:2;index:idx_login_attempts_identifier_created,priority:2;index:idx_login_attempts_ip_created,priority:2" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
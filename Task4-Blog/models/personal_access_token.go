@@ -0,0 +1,103 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenScope 个人访问令牌的权限范围
+type TokenScope string
+
+const (
+	ScopePostsRead     TokenScope = "posts:read"     // 读取自己的文章（含草稿）和导出
+	ScopePostsWrite    TokenScope = "posts:write"    // 创建、修改、删除文章和导入
+	ScopeCommentsRead  TokenScope = "comments:read"  // 读取自己的评论
+	ScopeCommentsWrite TokenScope = "comments:write" // 发表、修改、删除评论
+	ScopeProfileRead   TokenScope = "profile:read"   // 读取个人资料
+	ScopeProfileWrite  TokenScope = "profile:write"  // 修改个人资料
+	ScopeWebhooksRead  TokenScope = "webhooks:read"  // 查看 Webhook 端点和投递记录
+	ScopeWebhooksWrite TokenScope = "webhooks:write" // 管理 Webhook 端点
+)
+
+// TokenScopeList 所有可授予的权限范围
+var TokenScopeList = []TokenScope{
+	ScopePostsRead,
+	ScopePostsWrite,
+	ScopeCommentsRead,
+	ScopeCommentsWrite,
+	ScopeProfileRead,
+	ScopeProfileWrite,
+	ScopeWebhooksRead,
+	ScopeWebhooksWrite,
+}
+
+// TokenScopes 令牌的权限范围列表，以逗号分隔存储
+type TokenScopes []TokenScope
+
+// Value 实现 driver.Valuer
+func (s TokenScopes) Value() (driver.Value, error) {
+	parts := make([]string, len(s))
+	for i, scope := range s {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan 实现 sql.Scanner
+func (s *TokenScopes) Scan(value interface{}) error {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("无法将 %T 转换为 TokenScopes", value)
+	}
+
+	*s = nil
+	for _, part := range strings.Split(str, ",") {
+		if part != "" {
+			*s = append(*s, TokenScope(part))
+		}
+	}
+	return nil
+}
+
+// Has 是否包含指定权限
+func (s TokenScopes) Has(scope TokenScope) bool {
+	for _, sc := range s {
+		if sc == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken 个人访问令牌，供脚本和 CI 调用接口。只保存令牌的 SHA-256 摘要，明文只在创建时返回一次
+type PersonalAccessToken struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	UserID     uint        `gorm:"not null;index" json:"user_id"`
+	Name       string      `gorm:"size:100;not null" json:"name"`
+	TokenHash  string      `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Prefix     string      `gorm:"size:16;not null" json:"prefix"` // 令牌开头几位，便于用户辨认
+	Scopes     TokenScopes `gorm:"type:varchar(255);not null" json:"scopes"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty"` // 为空表示永不过期
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty"`
+	LastUsedIP string      `gorm:"size:45" json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// Active 令牌是否可用：未撤销且未过期
+func (t *PersonalAccessToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
@@ -81,6 +81,16 @@ func (p *Post) AfterDelete(tx *gorm.DB) error {
 		Update("post_count", gorm.Expr("post_count - ?", 1)).Error
 }
 
+// Restore 从回收站恢复文章，并恢复 AfterDelete 减去的用户文章数量
+func (p *Post) Restore(tx *gorm.DB) error {
+	if err := tx.Unscoped().Model(p).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	p.DeletedAt = gorm.DeletedAt{}
+	return tx.Model(&User{}).Where("id = ?", p.UserID).
+		Update("post_count", gorm.Expr("post_count + ?", 1)).Error
+}
+
 // PostResponse 文章响应结构
 type PostResponse struct {
 	ID          uint       `json:"id"`
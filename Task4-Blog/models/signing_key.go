@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SigningKey JWT 签名密钥。同一时间只有一个密钥用于签名，被替换的密钥在宽限期内仍用于验证并发布在 JWKS 中
+type SigningKey struct {
+	ID          string    `gorm:"primaryKey;size:64" json:"kid"`
+	Algorithm   string    `gorm:"size:16;not null" json:"alg"`        // RS256 或 EdDSA
+	PrivateKey  string    `gorm:"type:text;not null" json:"-"`        // 使用 jwt.key_secret 加密的 PKCS#8 私钥
+	PublicKey   string    `gorm:"type:text;not null" json:"-"`        // PKIX PEM
+	ActivatesAt time.Time `gorm:"not null;index" json:"activates_at"` // 开始用于签名的时间，在此之前只发布公钥
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
@@ -8,15 +8,25 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Username  string         `gorm:"size:50;uniqueIndex;not null" json:"username"`
-	Email     string         `gorm:"size:100;uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"size:255;not null" json:"-"` // 不序列化到 JSON
-	Bio       string         `gorm:"type:text" json:"bio"`       // 个人简介
-	Avatar    string         `gorm:"size:255" json:"avatar"`     // 头像 URL
-	Role      string         `gorm:"size:20;default:'user'" json:"role"` // user, admin
-	IsActive  bool           `gorm:"default:true" json:"is_active"`
-	LastLogin *time.Time     `json:"last_login,omitempty"`
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Username      string     `gorm:"size:50;uniqueIndex;not null" json:"username"`
+	Email         string     `gorm:"size:100;uniqueIndex;not null" json:"email"`
+	Password      string     `gorm:"size:255;not null" json:"-"`         // 不序列化到 JSON
+	Bio           string     `gorm:"type:text" json:"bio"`               // 个人简介
+	Avatar        string     `gorm:"size:255" json:"avatar"`             // 头像 URL
+	Role          string     `gorm:"size:20;default:'user'" json:"role"` // user, admin
+	IsActive      bool       `gorm:"default:true" json:"is_active"`
+	PostCount     int        `gorm:"default:0" json:"post_count"` // 文章数（由 Post 钩子维护）
+	LastLogin     *time.Time `json:"last_login,omitempty"`
+	WalletAddress *string    `gorm:"size:42;uniqueIndex" json:"wallet_address,omitempty"` // 绑定的以太坊钱包地址（EIP-55 格式）
+
+	// 两步验证（TOTP）
+	TwoFactorEnabled     bool       `gorm:"not null;default:false" json:"two_factor_enabled"`
+	TOTPSecret           string     `gorm:"column:totp_secret;size:64" json:"-"`      // 验证器密钥，开启前为待确认的密钥
+	TOTPLastStep         int64      `gorm:"column:totp_last_step;default:0" json:"-"` // 最近一次使用的时间步，防止验证码重放
+	TwoFactorFailures    int        `gorm:"default:0" json:"-"`                       // 连续验证失败次数
+	TwoFactorLockedUntil *time.Time `json:"-"`                                        // 验证失败过多时锁定到该时间
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // 软删除
@@ -42,24 +52,31 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 
 // UserResponse 用户响应结构（不包含敏感信息）
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Bio       string    `json:"bio"`
-	Avatar    string    `json:"avatar"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               uint      `json:"id"`
+	Username         string    `json:"username"`
+	Email            string    `json:"email"`
+	Bio              string    `json:"bio"`
+	Avatar           string    `json:"avatar"`
+	Role             string    `json:"role"`
+	WalletAddress    string    `json:"wallet_address,omitempty"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
 // ToResponse 转换为响应结构体
 func (u *User) ToResponse() UserResponse {
-	return UserResponse{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		Bio:       u.Bio,
-		Avatar:    u.Avatar,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
+	resp := UserResponse{
+		ID:               u.ID,
+		Username:         u.Username,
+		Email:            u.Email,
+		Bio:              u.Bio,
+		Avatar:           u.Avatar,
+		Role:             u.Role,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+		CreatedAt:        u.CreatedAt,
 	}
-}
\ No newline at end of file
+	if u.WalletAddress != nil {
+		resp.WalletAddress = *u.WalletAddress
+	}
+	return resp
+}
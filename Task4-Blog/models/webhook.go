@@ -0,0 +1,149 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WebhookEvent Webhook 事件类型
+type WebhookEvent string
+
+const (
+	WebhookEventPostPublished  WebhookEvent = "post.published"  // 文章发布
+	WebhookEventPostUpdated    WebhookEvent = "post.updated"    // 文章更新
+	WebhookEventPostDeleted    WebhookEvent = "post.deleted"    // 文章删除
+	WebhookEventCommentCreated WebhookEvent = "comment.created" // 新评论
+)
+
+// WebhookEventList 所有可订阅的事件
+var WebhookEventList = []WebhookEvent{
+	WebhookEventPostPublished,
+	WebhookEventPostUpdated,
+	WebhookEventPostDeleted,
+	WebhookEventCommentCreated,
+}
+
+// WebhookEvents 订阅的事件列表，以逗号分隔存储
+type WebhookEvents []WebhookEvent
+
+// Value 实现 driver.Valuer
+func (e WebhookEvents) Value() (driver.Value, error) {
+	parts := make([]string, len(e))
+	for i, event := range e {
+		parts[i] = string(event)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan 实现 sql.Scanner
+func (e *WebhookEvents) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("无法将 %T 转换为 WebhookEvents", value)
+	}
+
+	*e = nil
+	for _, part := range strings.Split(s, ",") {
+		if part != "" {
+			*e = append(*e, WebhookEvent(part))
+		}
+	}
+	return nil
+}
+
+// Has 是否订阅了指定事件
+func (e WebhookEvents) Has(event WebhookEvent) bool {
+	for _, ev := range e {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookScope Webhook 接收范围
+type WebhookScope string
+
+const (
+	WebhookScopeUser WebhookScope = "user" // 只接收所有者自己的文章及其评论的事件
+	WebhookScopeSite WebhookScope = "site" // 接收全站事件（管理员）
+)
+
+// Webhook 外发通知端点
+type Webhook struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	UserID         uint          `gorm:"not null;index" json:"user_id"` // 所有者（全站端点为创建的管理员）
+	Scope          WebhookScope  `gorm:"size:20;not null;index" json:"scope"`
+	URL            string        `gorm:"size:500;not null" json:"url"`
+	Secret         string        `gorm:"size:100;not null" json:"-"` // HMAC 签名密钥，只在创建时返回
+	Events         WebhookEvents `gorm:"type:varchar(255);not null" json:"events"`
+	Description    string        `gorm:"size:255" json:"description"`
+	IsActive       bool          `gorm:"not null;default:true" json:"is_active"`
+	FailureCount   int           `gorm:"not null;default:0" json:"failure_count"` // 连续投递失败次数，成功后清零
+	DisabledAt     *time.Time    `json:"disabled_at,omitempty"`
+	DisabledReason string        `gorm:"size:255" json:"disabled_reason,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDeliveryStatus 投递状态
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // 等待投递或重试
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // 投递成功
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // 重试次数用尽或端点已停用
+)
+
+// WebhookDelivery 一个事件向一个端点的投递，失败时按指数退避重试
+type WebhookDelivery struct {
+	ID            uint                  `gorm:"primaryKey" json:"id"`
+	WebhookID     uint                  `gorm:"not null;index" json:"webhook_id"`
+	EventID       string                `gorm:"size:64;not null;index" json:"event_id"` // 事件 ID，同一事件投递到多个端点时相同
+	Event         WebhookEvent          `gorm:"size:50;not null" json:"event"`
+	Payload       string                `gorm:"type:text;not null" json:"payload"`
+	Status        WebhookDeliveryStatus `gorm:"size:20;not null;default:'pending';index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts      int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time             `gorm:"not null;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	LastError     string                `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+
+	AttemptLog []WebhookAttempt `gorm:"foreignKey:DeliveryID" json:"attempt_log,omitempty"`
+}
+
+// TableName 指定表名
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookAttempt 单次投递尝试的记录
+type WebhookAttempt struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	DeliveryID   uint      `gorm:"not null;index" json:"delivery_id"`
+	Attempt      int       `gorm:"not null" json:"attempt"` // 第几次尝试，从 1 开始
+	StatusCode   int       `json:"status_code,omitempty"`   // HTTP 状态码，连接失败时为 0
+	Error        string    `gorm:"type:text" json:"error,omitempty"`
+	ResponseBody string    `gorm:"type:text" json:"response_body,omitempty"` // 响应体（截断）
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (WebhookAttempt) TableName() string {
+	return "webhook_attempts"
+}
@@ -0,0 +1,389 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Auth 接口的认证要求
+type Auth int
+
+const (
+	AuthNone  Auth = iota // 公开接口
+	AuthUser              // 需要登录
+	AuthAdmin             // 需要管理员权限
+)
+
+// Param 查询参数
+type Param struct {
+	Name        string
+	Description string
+	Type        string // string, integer, boolean，默认 string
+	Default     interface{}
+	Enum        []string
+	Required    bool
+}
+
+// FormField multipart/form-data 表单字段
+type FormField struct {
+	Name        string
+	Description string
+	File        bool // 文件字段
+	Required    bool
+}
+
+// Reply 额外的成功响应（如异步任务返回 202）
+type Reply struct {
+	Status      int
+	Description string
+	Data        interface{}
+}
+
+// Operation 一个接口的文档
+type Operation struct {
+	Method      string
+	Path        string // gin 路由语法，如 /api/v1/posts/:id
+	Tag         string
+	Summary     string
+	Description string
+	Auth        Auth
+	Query       []Param
+	Body        interface{} // JSON 请求体
+	Form        []FormField // multipart/form-data 请求体
+	Status      int         // 成功状态码，默认 200
+	Data        interface{} // 统一响应结构中 data 字段的类型
+	Raw         interface{} // 不使用统一响应结构时的响应体类型
+	Produces    string      // 非 JSON 响应的内容类型，如 application/zip
+	Also        []Reply
+	Errors      []int // 可能返回的错误状态码，认证相关的 401/403 会自动添加
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Builder OpenAPI 文档生成器
+type Builder struct {
+	info     Info
+	envelope interface{}
+	enums    map[reflect.Type][]string
+}
+
+// NewBuilder 创建文档生成器，envelope 为统一响应结构（其 data 字段会被替换为具体类型）
+func NewBuilder(info Info, envelope interface{}) *Builder {
+	return &Builder{info: info, envelope: envelope, enums: make(map[reflect.Type][]string)}
+}
+
+// Enum 声明字符串类型的可选值，如 models.PostStatus
+func (b *Builder) Enum(v interface{}, values ...string) {
+	b.enums[reflect.TypeOf(v)] = values
+}
+
+// errorDescriptions 错误状态码说明
+var errorDescriptions = map[int]string{
+	http.StatusBadRequest:            "请求参数错误",
+	http.StatusUnauthorized:          "未登录、token 无效或已过期",
+	http.StatusForbidden:             "权限不足",
+	http.StatusNotFound:              "资源不存在",
+	http.StatusConflict:              "资源冲突",
+	http.StatusGone:                  "资源已过期",
+	http.StatusRequestEntityTooLarge: "请求体过大",
+	http.StatusTooManyRequests:       "请求过于频繁",
+	http.StatusInternalServerError:   "服务器内部错误",
+	http.StatusServiceUnavailable:    "服务不可用",
+}
+
+// Build 生成 OpenAPI 3 文档
+func (b *Builder) Build(ops []Operation) map[string]interface{} {
+	gen := &schemaGenerator{components: make(map[string]Schema), enums: b.enums}
+	envelope := gen.of(b.envelope)
+
+	paths := make(map[string]map[string]interface{})
+	tags := make(map[string]bool)
+	errorCodes := make(map[int]bool)
+
+	for _, op := range ops {
+		path, params := convertPath(op.Path)
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		if op.Tag != "" {
+			tags[op.Tag] = true
+		}
+
+		operation := map[string]interface{}{
+			"operationId": operationID(op.Method, op.Path),
+			"summary":     op.Summary,
+			"responses":   b.responses(gen, envelope, op, errorCodes),
+		}
+		if op.Tag != "" {
+			operation["tags"] = []string{op.Tag}
+		}
+		description := op.Description
+		switch op.Auth {
+		case AuthUser:
+			operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+		case AuthAdmin:
+			operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+			operation["x-required-role"] = "admin"
+			description = strings.TrimSpace(description + "\n\n需要管理员权限。")
+		}
+		if description != "" {
+			operation["description"] = description
+		}
+
+		for _, q := range op.Query {
+			s := Schema{"type": "string"}
+			if q.Type != "" {
+				s["type"] = q.Type
+			}
+			if q.Default != nil {
+				s["default"] = q.Default
+			}
+			if len(q.Enum) > 0 {
+				s["enum"] = q.Enum
+			}
+			params = append(params, map[string]interface{}{
+				"name": q.Name, "in": "query", "description": q.Description, "required": q.Required, "schema": s,
+			})
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+
+		if body := b.requestBody(gen, op); body != nil {
+			operation["requestBody"] = body
+		}
+
+		paths[path][strings.ToLower(op.Method)] = operation
+	}
+
+	responses := make(map[string]interface{})
+	for code := range errorCodes {
+		responses[errorName(code)] = map[string]interface{}{
+			"description": errorDescriptions[code],
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": envelope,
+					"example": map[string]interface{}{
+						"success": false,
+						"message": errorDescriptions[code],
+						"error":   http.StatusText(code),
+					},
+				},
+			},
+		}
+	}
+
+	tagList := make([]map[string]string, 0, len(tags))
+	for tag := range tags {
+		tagList = append(tagList, map[string]string{"name": tag})
+	}
+	sort.Slice(tagList, func(i, j int) bool { return tagList[i]["name"] < tagList[j]["name"] })
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       b.info.Title,
+			"version":     b.info.Version,
+			"description": b.info.Description,
+		},
+		"tags":  tagList,
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas":   gen.components,
+			"responses": responses,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+					"description":  "登录或注册接口返回的 token，或个人访问令牌（pat_ 开头，只能访问其权限范围内的接口），请求头格式为 Authorization: Bearer <token>",
+				},
+			},
+		},
+	}
+}
+
+// requestBody 生成请求体
+func (b *Builder) requestBody(gen *schemaGenerator, op Operation) map[string]interface{} {
+	if op.Body != nil {
+		return map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": gen.of(op.Body)},
+			},
+		}
+	}
+	if len(op.Form) == 0 {
+		return nil
+	}
+
+	properties := Schema{}
+	var required []string
+	for _, f := range op.Form {
+		s := Schema{"type": "string", "description": f.Description}
+		if f.File {
+			s["format"] = "binary"
+		}
+		properties[f.Name] = s
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+	s := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"multipart/form-data": map[string]interface{}{"schema": s},
+		},
+	}
+}
+
+// responses 生成成功和错误响应
+func (b *Builder) responses(gen *schemaGenerator, envelope Schema, op Operation, errorCodes map[int]bool) map[string]interface{} {
+	responses := make(map[string]interface{})
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	switch {
+	case op.Produces != "":
+		responses[fmt.Sprint(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content": map[string]interface{}{
+				op.Produces: map[string]interface{}{"schema": Schema{"type": "string", "format": "binary"}},
+			},
+		}
+	case op.Raw != nil:
+		responses[fmt.Sprint(status)] = jsonResponse(http.StatusText(status), gen.of(op.Raw))
+	default:
+		responses[fmt.Sprint(status)] = jsonResponse(http.StatusText(status), b.wrap(gen, envelope, op.Data))
+	}
+	for _, reply := range op.Also {
+		description := reply.Description
+		if description == "" {
+			description = http.StatusText(reply.Status)
+		}
+		responses[fmt.Sprint(reply.Status)] = jsonResponse(description, b.wrap(gen, envelope, reply.Data))
+	}
+
+	codes := append([]int(nil), op.Errors...)
+	switch op.Auth {
+	case AuthUser:
+		codes = append(codes, http.StatusUnauthorized)
+	case AuthAdmin:
+		codes = append(codes, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	for _, code := range codes {
+		errorCodes[code] = true
+		responses[fmt.Sprint(code)] = map[string]string{"$ref": "#/components/responses/" + errorName(code)}
+	}
+	return responses
+}
+
+// wrap 将 data 类型套入统一响应结构
+func (b *Builder) wrap(gen *schemaGenerator, envelope Schema, data interface{}) Schema {
+	if data == nil {
+		return envelope
+	}
+	return Schema{"allOf": []Schema{
+		envelope,
+		{"type": "object", "properties": Schema{"data": gen.of(data)}},
+	}}
+}
+
+// jsonResponse JSON 响应
+func jsonResponse(description string, schema Schema) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// pathParamRe gin 路由中的参数（:id 或 *path）
+var pathParamRe = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// convertPath 将 gin 路由转换为 OpenAPI 路径并生成路径参数
+func convertPath(path string) (string, []map[string]interface{}) {
+	var params []map[string]interface{}
+	converted := pathParamRe.ReplaceAllStringFunc(path, func(m string) string {
+		name := m[1:]
+		s := Schema{"type": "string"}
+		if strings.HasSuffix(strings.ToLower(name), "id") {
+			s = Schema{"type": "integer", "minimum": 1}
+		}
+		params = append(params, map[string]interface{}{
+			"name": name, "in": "path", "required": true, "schema": s,
+		})
+		return "{" + name + "}"
+	})
+	return converted, params
+}
+
+// operationID 由方法和路径生成唯一的 operationId
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '_' }) {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			part = "By" + part[1:]
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// errorName 错误响应组件名
+func errorName(code int) string {
+	return strings.ReplaceAll(http.StatusText(code), " ", "")
+}
+
+// Undocumented 返回已注册但没有文档的路由（METHOD PATH）
+func Undocumented(routes gin.RoutesInfo, ops []Operation) []string {
+	documented := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		documented[op.Method+" "+op.Path] = true
+	}
+	var missing []string
+	for _, r := range routes {
+		key := r.Method + " " + r.Path
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Stale 返回有文档但未注册的路由（METHOD PATH）
+func Stale(routes gin.RoutesInfo, ops []Operation) []string {
+	registered := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		registered[r.Method+" "+r.Path] = true
+	}
+	var stale []string
+	for _, op := range ops {
+		key := op.Method + " " + op.Path
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
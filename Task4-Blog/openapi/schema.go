@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema 对象
+type Schema map[string]interface{}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaGenerator 通过反射从 Go 类型生成 schema，具名结构体放入 components/schemas 并以 $ref 引用
+type schemaGenerator struct {
+	components map[string]Schema
+	enums      map[reflect.Type][]string
+}
+
+// ref 引用组件
+func ref(name string) Schema {
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// of 生成类型 v 的 schema，v 为 nil 时返回 nil
+func (g *schemaGenerator) of(v interface{}) Schema {
+	if v == nil {
+		return nil
+	}
+	return g.schema(reflect.TypeOf(v))
+}
+
+// schema 生成类型对应的 schema
+func (g *schemaGenerator) schema(t reflect.Type) Schema {
+	if values, ok := g.enums[t]; ok {
+		return Schema{"type": "string", "enum": values}
+	}
+
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schema(t.Elem())
+		if _, isRef := s["$ref"]; isRef {
+			return Schema{"allOf": []Schema{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return Schema{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return Schema{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "format": "byte"}
+		}
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Interface:
+		return Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := t.Name()
+		if _, ok := g.components[name]; !ok {
+			// 先占位，防止自引用类型无限递归
+			g.components[name] = Schema{}
+			g.components[name] = g.object(t)
+		}
+		return ref(name)
+	default:
+		return Schema{}
+	}
+}
+
+// object 生成结构体的 object schema，按 json 标签命名字段，按 binding 标签生成约束
+func (g *schemaGenerator) object(t reflect.Type) Schema {
+	properties := Schema{}
+	var required []string
+	g.fields(t, properties, &required)
+
+	s := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// fields 收集结构体字段，匿名嵌入的结构体字段会被展开
+func (g *schemaGenerator) fields(t reflect.Type, properties Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.fields(ft, properties, required)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s := g.schema(f.Type)
+		if constrained, isRequired := applyBinding(s, f.Tag.Get("binding")); constrained != nil {
+			s = constrained
+			if isRequired {
+				*required = append(*required, name)
+			}
+		}
+		if desc := f.Tag.Get("description"); desc != "" {
+			s = withDescription(s, desc)
+		}
+		properties[name] = s
+	}
+}
+
+// applyBinding 将 validator 的 binding 标签转换为 schema 约束
+func applyBinding(s Schema, binding string) (Schema, bool) {
+	if binding == "" {
+		return s, false
+	}
+	if _, isRef := s["$ref"]; isRef {
+		return s, strings.Contains(","+binding+",", ",required,")
+	}
+
+	isRequired := false
+	typ, _ := s["type"].(string)
+	rules := strings.Split(binding, ",")
+	for i, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			// dive 之后的规则作用于数组元素
+			if items, ok := s["items"].(Schema); ok && typ == "array" {
+				s["items"], _ = applyBinding(items, strings.Join(rules[i+1:], ","))
+			}
+			return s, isRequired
+		case "required":
+			isRequired = true
+		case "email":
+			s["format"] = "email"
+		case "url":
+			s["format"] = "uri"
+		case "oneof":
+			s["enum"] = strings.Fields(value)
+		case "min", "max", "len":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch typ {
+			case "string":
+				if key != "max" {
+					s["minLength"] = n
+				}
+				if key != "min" {
+					s["maxLength"] = n
+				}
+			case "array":
+				if key != "max" {
+					s["minItems"] = n
+				}
+				if key != "min" {
+					s["maxItems"] = n
+				}
+			case "integer", "number":
+				if key != "max" {
+					s["minimum"] = n
+				}
+				if key != "min" {
+					s["maximum"] = n
+				}
+			}
+		}
+	}
+	return s, isRequired
+}
+
+// withDescription 为 schema 添加描述，$ref 不能带兄弟字段，需要包一层 allOf
+func withDescription(s Schema, desc string) Schema {
+	if _, isRef := s["$ref"]; isRef {
+		return Schema{"allOf": []Schema{s}, "description": desc}
+	}
+	s["description"] = desc
+	return s
+}
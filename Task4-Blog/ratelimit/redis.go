@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"blog-system/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript 在 Redis 中原子地执行 GCRA，时间取自 Redis 服务器，避免各实例时钟不一致。
+// KEYS[1] 为 key，ARGV 为每次请求的间隔和窗口（微秒）；
+// 返回 {是否允许, 剩余次数, 恢复时间(微秒), 需等待时间(微秒)}
+var gcraScript = redis.NewScript(`
+if redis.replicate_commands then
+  redis.replicate_commands()
+end
+local interval = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - window
+if now < allow_at then
+  return {0, 0, tat - now, allow_at - now}
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((window - (new_tat - now)) / interval), new_tat - now, 0}
+`)
+
+// RedisStore 基于 Redis 协议的存储（Redis、Valkey、KeyDB 等），多个实例共享计数
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore 创建 Redis 存储，连接在首次使用时建立
+func NewRedisStore(cfg config.RedisConfig) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:     cfg.Addr,
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}),
+		prefix: cfg.Prefix,
+	}
+}
+
+// Take 实现 Store
+func (s *RedisStore) Take(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	interval := window.Microseconds() / int64(limit)
+	values, err := gcraScript.Run(ctx, s.client, []string{s.prefix + key}, interval, window.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[2]) * time.Microsecond,
+		RetryAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
+
+// Ping 检查 Redis 是否可用，用于就绪检查
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
@@ -0,0 +1,62 @@
+// Package ratelimit 实现基于 GCRA（通用信元速率算法）的限流，计数可以保存在本进程内存或 Redis 中。
+//
+// 每个 key 只需保存一个时间点（理论到达时间 TAT）：配额为 limit 次/window，
+// 每次请求把 TAT 推后 window/limit，TAT 超出当前时间 window 以上时拒绝请求。
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"blog-system/config"
+)
+
+// Result 一次限流检查的结果
+type Result struct {
+	Allowed    bool
+	Limit      int           // 窗口内允许的请求数
+	Remaining  int           // 当前剩余的请求数
+	ResetAfter time.Duration // 配额完全恢复所需的时间
+	RetryAfter time.Duration // 被拒绝时需要等待的时间
+}
+
+// Store 限流计数存储
+type Store interface {
+	// Take 为 key 消耗一次配额，配额为每 window 时间 limit 次
+	Take(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// NewStore 按配置创建存储：memory 只在本进程内限流，redis 在多个实例间共享计数
+func NewStore(cfg config.RateLimitConfig) (Store, error) {
+	switch cfg.Store {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "redis":
+		return NewRedisStore(cfg.Redis), nil
+	default:
+		return nil, fmt.Errorf("不支持的限流存储: %s", cfg.Store)
+	}
+}
+
+// gcra 根据当前的 TAT 计算本次请求的结果和新的 TAT，被拒绝时新的 TAT 不变
+func gcra(now, tat time.Time, limit int, window time.Duration) (Result, time.Time) {
+	interval := window / time.Duration(limit)
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-window)
+	if now.Before(allowAt) {
+		return Result{Limit: limit, ResetAfter: tat.Sub(now), RetryAfter: allowAt.Sub(now)}, tat
+	}
+
+	resetAfter := newTAT.Sub(now)
+	return Result{
+		Allowed:    true,
+		Limit:      limit,
+		Remaining:  int((window - resetAfter) / interval),
+		ResetAfter: resetAfter,
+	}, newTAT
+}
@@ -0,0 +1,480 @@
+package routes
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"blog-system/controllers"
+	"blog-system/graph"
+	"blog-system/health"
+	"blog-system/importer"
+	"blog-system/models"
+	"blog-system/openapi"
+	"blog-system/services"
+	"blog-system/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIVersion Swagger UI 版本（从 CDN 加载）
+const swaggerUIVersion = "5.17.14"
+
+// PostListResponse 文章列表响应
+type PostListResponse struct {
+	Posts      []models.PostResponse    `json:"posts"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// CommentListResponse 评论列表响应
+type CommentListResponse struct {
+	Comments   []models.CommentResponse `json:"comments"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// UserListResponse 用户列表响应
+type UserListResponse struct {
+	Users      []models.UserResponse    `json:"users"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// WebhookListResponse 端点列表响应
+type WebhookListResponse struct {
+	Webhooks []models.Webhook `json:"webhooks"`
+}
+
+// WebhookDeliveryListResponse 投递记录列表响应
+type WebhookDeliveryListResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// AccessTokenListResponse 个人访问令牌列表响应
+type AccessTokenListResponse struct {
+	AccessTokens []models.PersonalAccessToken `json:"access_tokens"`
+}
+
+// AuditLogListResponse 审计日志列表响应
+type AuditLogListResponse struct {
+	Logs       []models.AuditLog        `json:"logs"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// TrashPostListResponse 回收站文章列表响应
+type TrashPostListResponse struct {
+	Posts      []services.TrashedPost   `json:"posts"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// TrashCommentListResponse 回收站评论列表响应
+type TrashCommentListResponse struct {
+	Comments   []services.TrashedComment `json:"comments"`
+	Pagination utils.PaginationResponse  `json:"pagination"`
+}
+
+// LoginAttemptListResponse 登录记录列表响应
+type LoginAttemptListResponse struct {
+	Attempts   []models.LoginAttempt    `json:"attempts"`
+	Pagination utils.PaginationResponse `json:"pagination"`
+}
+
+// ServiceInfo 服务信息
+type ServiceInfo struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Docs        string `json:"docs"`
+}
+
+// 分页查询参数
+var pageParams = []openapi.Param{
+	{Name: "page", Description: "页码", Type: "integer", Default: 1},
+	{Name: "page_size", Description: "每页数量", Type: "integer", Default: 10},
+}
+
+// apiOperations 所有路由的文档，新增路由时需同步添加（可用 openapi check 命令检查）
+func apiOperations() []openapi.Operation {
+	postStatus := openapi.Param{Name: "status", Description: "文章状态", Enum: []string{"draft", "published", "archived"}}
+	importForm := []openapi.FormField{
+		{Name: "file", Description: "WordPress WXR (.xml)、Markdown ZIP (.zip) 或单个 .md 文件", File: true, Required: true},
+		{Name: "format", Description: "auto（默认）、markdown、wxr"},
+		{Name: "dry_run", Description: "true 时只返回变更预览，不写入数据库"},
+	}
+	webhookPageParams := []openapi.Param{pageParams[0], {Name: "page_size", Description: "每页数量（最大 100）", Type: "integer", Default: 20}}
+	loginAttemptParams := append(append([]openapi.Param{}, webhookPageParams...),
+		openapi.Param{Name: "user_id", Description: "用户ID", Type: "integer"},
+		openapi.Param{Name: "ip", Description: "IP 地址"},
+		openapi.Param{Name: "result", Description: "结果", Enum: []string{
+			string(models.LoginResultSuccess), string(models.LoginResultFailed), string(models.LoginResultBlocked), string(models.LoginResultUnlocked)}})
+	trashParams := append(append([]openapi.Param{}, webhookPageParams...),
+		openapi.Param{Name: "user_id", Description: "只看该用户删除的内容", Type: "integer"})
+	auditActions := make([]string, len(models.AuditActionList))
+	for i, action := range models.AuditActionList {
+		auditActions[i] = string(action)
+	}
+	auditParams := append(append([]openapi.Param{}, webhookPageParams...),
+		openapi.Param{Name: "actor_id", Description: "操作者用户ID", Type: "integer"},
+		openapi.Param{Name: "action", Description: "操作类型", Enum: auditActions},
+		openapi.Param{Name: "target_type", Description: "对象类型", Enum: []string{models.AuditTargetPost, models.AuditTargetComment, models.AuditTargetUser}},
+		openapi.Param{Name: "target_id", Description: "对象ID，与 target_type 一起使用", Type: "integer"},
+		openapi.Param{Name: "from", Description: "起始时间（含），RFC 3339 格式"},
+		openapi.Param{Name: "to", Description: "截止时间（不含），RFC 3339 格式"})
+	commentSorts := make([]string, len(models.CommentSortList))
+	for i, s := range models.CommentSortList {
+		commentSorts[i] = string(s)
+	}
+	commentDepth := openapi.Param{Name: "depth", Description: "展开的回复层数，默认并且最多为 comment.max_depth", Type: "integer"}
+
+	return []openapi.Operation{
+		// 服务信息与健康检查
+		{Method: "GET", Path: "/", Tag: "系统", Summary: "服务信息", Raw: ServiceInfo{}},
+		{Method: "GET", Path: "/livez", Tag: "系统", Summary: "存活检查", Description: "后台任务是否正常运行，失败时返回 503。",
+			Raw: health.Report{}, Errors: []int{http.StatusServiceUnavailable}},
+		{Method: "GET", Path: "/readyz", Tag: "系统", Summary: "就绪检查", Description: "数据库、迁移、存储目录和后台任务是否可用，失败时返回 503。",
+			Raw: health.Report{}, Errors: []int{http.StatusServiceUnavailable}},
+		{Method: "GET", Path: "/openapi.json", Tag: "系统", Summary: "OpenAPI 文档", Raw: map[string]interface{}{}},
+		{Method: "GET", Path: "/.well-known/jwks.json", Tag: "认证", Summary: "JWT 公钥（JWKS）",
+			Raw: services.JWKS{}, Description: "jwt.algorithm 为 RS256 / EdDSA 时发布正在使用、即将生效和宽限期内的公钥，其他服务按 token header 中的 kid 选择公钥验证；HS256 时 keys 为空。"},
+		{Method: "GET", Path: "/swagger", Tag: "系统", Summary: "Swagger UI", Produces: "text/html"},
+
+		// GraphQL
+		{Method: "POST", Path: "/graphql", Tag: "GraphQL", Summary: "执行 GraphQL 查询或变更",
+			Description: "认证可选，未登录时只能查询公开数据。响应为标准 GraphQL 结构 {data, errors}，错误码位于 errors[].extensions.code；" +
+				"查询超过 graphql.max_depth 或 graphql.max_complexity 时返回 400。Schema 可通过内省查询获取。",
+			Body: graph.Request{}, Raw: graph.Response{}, Errors: []int{http.StatusBadRequest}},
+		{Method: "GET", Path: "/graphql", Tag: "GraphQL", Summary: "通过 GET 执行 GraphQL 查询", Description: "只能执行查询，变更返回 405。",
+			Query: []openapi.Param{
+				{Name: "query", Description: "GraphQL 文档", Required: true},
+				{Name: "operationName", Description: "文档包含多个操作时要执行的操作"},
+				{Name: "variables", Description: "JSON 编码的变量"},
+			},
+			Raw: graph.Response{}, Errors: []int{http.StatusBadRequest, http.StatusMethodNotAllowed}},
+
+		// 认证
+		{Method: "POST", Path: "/api/v1/auth/register", Tag: "认证", Summary: "用户注册", Body: controllers.RegisterRequest{},
+			Status: http.StatusCreated, Data: controllers.AuthResponse{}, Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/auth/login", Tag: "认证", Summary: "用户登录",
+			Description: "用户开启了两步验证时返回 TwoFactorChallengeResponse（two_factor_required 为 true）而不是 token，" +
+				"客户端再用 challenge_token 调用 /api/v1/auth/2fa/verify 完成登录。钱包登录同样如此。" +
+				"用户不存在、已停用和密码错误返回相同的 401；账号或 IP 连续失败过多时返回 429 和 Retry-After 头，期间不再验证密码。",
+			Body: controllers.LoginRequest{}, Data: controllers.AuthResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/auth/2fa/verify", Tag: "认证", Summary: "两步验证登录",
+			Description: "提交登录返回的 challenge_token（two_factor.challenge_ttl 秒内有效）和验证器生成的验证码或恢复码。" +
+				"连续失败 two_factor.max_attempts 次后锁定 two_factor.lockout_minutes 分钟，期间返回 429。",
+			Body: controllers.VerifyTwoFactorRequest{}, Data: controllers.AuthResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/auth/siwe/nonce", Tag: "认证", Summary: "获取钱包登录 nonce",
+			Description: "nonce 只能使用一次，siwe.nonce_ttl 秒后过期。客户端用返回的 nonce、domain 构造 EIP-4361 消息并用 personal_sign 签名。",
+			Data:        controllers.SIWENonceResponse{}, Errors: []int{http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/auth/siwe", Tag: "认证", Summary: "以太坊钱包登录（EIP-4361）",
+			Description: "验证消息的 domain、nonce、有效期和签名后签发与密码登录相同的 JWT。钱包首次登录时创建用户并返回 201；" +
+				"携带 token 调用时将钱包绑定到当前用户，钱包或账号已绑定其他对象时返回 409。" +
+				"账号开启了两步验证时（包括绑定钱包）返回 TwoFactorChallengeResponse。",
+			Body: controllers.SIWERequest{}, Data: controllers.AuthResponse{}, Also: []openapi.Reply{{Status: http.StatusCreated, Description: "已为新钱包创建用户", Data: controllers.AuthResponse{}}},
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+
+		// 用户
+		{Method: "GET", Path: "/api/v1/users/:id", Tag: "用户", Summary: "获取用户信息",
+			Data: models.UserResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "GET", Path: "/api/v1/users/:id/posts", Tag: "用户", Summary: "获取用户的文章列表", Query: pageParams,
+			Data: PostListResponse{}, Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/profile", Tag: "用户", Summary: "获取当前用户信息", Auth: openapi.AuthUser,
+			Data: models.UserResponse{}, Errors: []int{http.StatusNotFound}},
+		{Method: "PUT", Path: "/api/v1/users/profile", Tag: "用户", Summary: "更新个人资料", Auth: openapi.AuthUser,
+			Body: controllers.UpdateProfileRequest{}, Data: models.UserResponse{}, Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/posts", Tag: "用户", Summary: "获取我的文章", Auth: openapi.AuthUser,
+			Query: append(pageParams, postStatus), Data: PostListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/comments", Tag: "用户", Summary: "获取我的评论", Description: "包含未审核通过的评论，最新的在前。",
+			Auth: openapi.AuthUser, Query: webhookPageParams, Data: CommentListResponse{}, Errors: []int{http.StatusInternalServerError}},
+
+		// 文章
+		{Method: "GET", Path: "/api/v1/posts", Tag: "文章", Summary: "获取文章列表",
+			Query: append(pageParams, openapi.Param{Name: "status", Description: "文章状态", Default: "published", Enum: postStatus.Enum}),
+			Data:  PostListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/posts/:id", Tag: "文章", Summary: "获取文章详情", Description: "每次访问阅读数加一。",
+			Data: models.PostResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "POST", Path: "/api/v1/posts", Tag: "文章", Summary: "创建文章", Auth: openapi.AuthUser, Body: controllers.CreatePostRequest{},
+			Status: http.StatusCreated, Data: models.PostResponse{}, Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "PUT", Path: "/api/v1/posts/:id", Tag: "文章", Summary: "更新文章", Description: "只能修改自己的文章。", Auth: openapi.AuthUser,
+			Body: controllers.UpdatePostRequest{}, Data: models.PostResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/posts/:id", Tag: "文章", Summary: "删除文章", Description: "只能删除自己的文章。文章移入回收站，可以恢复。", Auth: openapi.AuthUser,
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError}},
+
+		// 评论
+		{Method: "GET", Path: "/api/v1/comments/posts/:postId", Tag: "评论", Summary: "获取文章评论树",
+			Description: "顶级评论分页，回复逐层展开。每条评论最多内嵌 comment.inline_replies 条回复，还有更多回复或超出展开层数时返回 replies_cursor，用获取回复接口继续加载。",
+			Query: []openapi.Param{pageParams[0], {Name: "page_size", Description: "每页数量（最大 100）", Type: "integer", Default: 20},
+				{Name: "sort", Description: "顶级评论排序：oldest 最早、newest 最新、top 回复最多", Enum: commentSorts, Default: "newest"},
+				{Name: "reply_sort", Description: "回复排序", Enum: commentSorts, Default: "oldest"},
+				commentDepth},
+			Data: CommentListResponse{}, Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/comments/:id", Tag: "评论", Summary: "获取评论详情",
+			Data: models.CommentResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "GET", Path: "/api/v1/comments/:id/replies", Tag: "评论", Summary: "获取评论的回复",
+			Description: "加载更多回复。cursor 取自评论树中的 replies_cursor 或上一页的 next_cursor，其中已包含排序方式；next_cursor 为空表示没有更多回复。",
+			Query: []openapi.Param{{Name: "cursor", Description: "分页游标，为空时从第一条开始"},
+				{Name: "sort", Description: "回复排序，指定 cursor 时可省略", Enum: commentSorts, Default: "oldest"},
+				{Name: "limit", Description: "返回条数（最大 100）", Type: "integer", Default: 20},
+				{Name: "depth", Description: "展开的回复层数（含本页回复），默认并且最多为 comment.max_depth", Type: "integer"}},
+			Data: services.CommentReplyPage{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/comments", Tag: "评论", Summary: "发表评论", Description: "回复时 parent_id 必须是同一篇文章下审核通过的评论，回复层数不限。",
+			Auth: openapi.AuthUser, Body: controllers.CreateCommentRequest{},
+			Status: http.StatusCreated, Data: models.CommentResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "PUT", Path: "/api/v1/comments/:id", Tag: "评论", Summary: "修改评论", Auth: openapi.AuthUser,
+			Description: "只能修改自己的评论，且只能在发表后 comment.edit_window 分钟内修改。修改后 edited_at 为修改时间，内容重新审核（管理员驳回的评论仍然待审核），修改前的版本保存在修改记录中。",
+			Body:        controllers.UpdateCommentRequest{}, Data: models.CommentResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/comments/:id", Tag: "评论", Summary: "删除评论", Description: "只能删除自己的评论。评论移入回收站，可以恢复。", Auth: openapi.AuthUser,
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError}},
+
+		// 导出与导入
+		{Method: "GET", Path: "/api/v1/users/my/export", Tag: "导出", Summary: "导出我的博客",
+			Description: "文章数不超过 export.sync_max_posts 时直接返回 ZIP；否则（或 async=true）创建异步任务并返回 202。",
+			Auth:        openapi.AuthUser, Query: []openapi.Param{{Name: "async", Description: "强制异步导出", Type: "boolean"}},
+			Produces: "application/zip", Also: []openapi.Reply{{Status: http.StatusAccepted, Description: "导出任务已创建", Data: controllers.ExportJobResponse{}}},
+			Errors: []int{http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/exports/:id", Tag: "导出", Summary: "查询我的导出任务", Auth: openapi.AuthUser,
+			Data: controllers.ExportJobResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "GET", Path: "/api/v1/users/my/exports/:id/download", Tag: "导出", Summary: "下载我的导出归档", Auth: openapi.AuthUser,
+			Produces: "application/zip", Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusGone}},
+		{Method: "POST", Path: "/api/v1/users/my/import", Tag: "导入", Summary: "导入到我的博客", Description: "所有文章归属当前用户。",
+			Auth: openapi.AuthUser, Form: importForm, Data: importer.Report{},
+			Errors: []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusInternalServerError}},
+
+		// 两步验证
+		{Method: "GET", Path: "/api/v1/users/my/2fa", Tag: "两步验证", Summary: "两步验证状态", Auth: openapi.AuthUser,
+			Data: services.TwoFactorStatus{}, Errors: []int{http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/2fa/enroll", Tag: "两步验证", Summary: "生成验证器密钥",
+			Description: "返回 base32 密钥、otpauth:// 地址及其二维码（PNG data URI）。提交验证码确认前两步验证不生效，重复调用会替换未确认的密钥。",
+			Auth:        openapi.AuthUser, Data: services.TwoFactorEnrollment{},
+			Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/2fa/confirm", Tag: "两步验证", Summary: "开启两步验证",
+			Description: "提交验证器生成的验证码。恢复码只在这里返回一次；返回的新 token 已通过两步验证。",
+			Auth:        openapi.AuthUser, Body: controllers.TwoFactorCodeRequest{}, Data: controllers.ConfirmTwoFactorResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/2fa/disable", Tag: "两步验证", Summary: "关闭两步验证",
+			Description: "需要提交验证码或恢复码。角色在 two_factor.required_roles 中时不能关闭。",
+			Auth:        openapi.AuthUser, Body: controllers.TwoFactorCodeRequest{},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/2fa/recovery-codes", Tag: "两步验证", Summary: "重新生成恢复码",
+			Description: "需要提交验证码或恢复码，旧恢复码全部失效。",
+			Auth:        openapi.AuthUser, Body: controllers.TwoFactorCodeRequest{}, Data: controllers.RecoveryCodesResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests, http.StatusInternalServerError}},
+
+		// 个人访问令牌
+		{Method: "GET", Path: "/api/v1/users/my/tokens", Tag: "访问令牌", Summary: "我的访问令牌列表", Description: "包含已撤销和已过期的令牌，不返回令牌明文。",
+			Auth: openapi.AuthUser, Data: AccessTokenListResponse{}, Errors: []int{http.StatusForbidden, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/tokens", Tag: "访问令牌", Summary: "创建访问令牌",
+			Description: "令牌明文只在创建时返回一次。expires_at 为空表示永不过期（access_token.max_expire_days 不为 0 时必须设置且不能超过上限）；" +
+				"每个用户最多 access_token.max_per_user 个有效令牌。",
+			Auth: openapi.AuthUser, Body: controllers.CreateAccessTokenRequest{}, Status: http.StatusCreated, Data: controllers.CreateAccessTokenResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/users/my/tokens/:id", Tag: "访问令牌", Summary: "撤销访问令牌", Description: "撤销后立即失效。",
+			Auth: openapi.AuthUser, Data: models.PersonalAccessToken{}, Errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError}},
+
+		// 回收站
+		{Method: "GET", Path: "/api/v1/users/my/trash/posts", Tag: "回收站", Summary: "我删除的文章",
+			Description: "最近删除的在前。超过 trash.retention_days 天的文章会被永久删除，purge_at 为预计删除时间。",
+			Auth:        openapi.AuthUser, Query: webhookPageParams, Data: TrashPostListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/trash/posts/:id/restore", Tag: "回收站", Summary: "恢复文章", Auth: openapi.AuthUser,
+			Data: models.PostResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/users/my/trash/posts/:id", Tag: "回收站", Summary: "永久删除文章",
+			Description: "只能删除回收站中的文章，文章下的所有评论一并删除，不能恢复。",
+			Auth:        openapi.AuthUser, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/trash/comments", Tag: "回收站", Summary: "我删除的评论",
+			Description: "最近删除的在前。超过 trash.retention_days 天的评论会被永久删除，purge_at 为预计删除时间。",
+			Auth:        openapi.AuthUser, Query: webhookPageParams, Data: TrashCommentListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/trash/comments/:id/restore", Tag: "回收站", Summary: "恢复评论",
+			Description: "所属文章或父评论仍在回收站中时返回 409，需要先恢复它们。",
+			Auth:        openapi.AuthUser, Data: models.CommentResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/users/my/trash/comments/:id", Tag: "回收站", Summary: "永久删除评论",
+			Description: "只能删除回收站中的评论，不能恢复。评论的回复可能属于其他用户，保留并上移一层（顶级评论的回复成为顶级评论）。",
+			Auth:        openapi.AuthUser, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+
+		// Webhook
+		{Method: "GET", Path: "/api/v1/users/my/webhooks", Tag: "Webhook", Summary: "我的端点列表", Auth: openapi.AuthUser,
+			Data: WebhookListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/users/my/webhooks", Tag: "Webhook", Summary: "注册端点",
+			Description: "只接收自己的文章和自己文章下评论的事件。签名密钥只在创建时返回一次；每个用户最多 webhook.max_per_user 个端点。",
+			Auth:        openapi.AuthUser, Body: controllers.CreateWebhookRequest{}, Status: http.StatusCreated, Data: controllers.CreateWebhookResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/webhooks/:id", Tag: "Webhook", Summary: "获取端点详情", Auth: openapi.AuthUser,
+			Data: models.Webhook{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "PUT", Path: "/api/v1/users/my/webhooks/:id", Tag: "Webhook", Summary: "更新端点", Description: "is_active=true 时重新启用已停用的端点并清零失败次数。",
+			Auth: openapi.AuthUser, Body: controllers.UpdateWebhookRequest{}, Data: models.Webhook{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/users/my/webhooks/:id", Tag: "Webhook", Summary: "删除端点", Auth: openapi.AuthUser,
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/webhooks/:id/deliveries", Tag: "Webhook", Summary: "端点的投递记录", Description: "最新的在前。",
+			Auth: openapi.AuthUser, Query: webhookPageParams, Data: WebhookDeliveryListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/users/my/webhooks/:id/deliveries/:deliveryId", Tag: "Webhook", Summary: "投递详情",
+			Description: "attempt_log 包含每次尝试的状态码、错误、响应内容（前 1KB）和耗时。",
+			Auth:        openapi.AuthUser, Data: models.WebhookDelivery{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+		// 管理员
+		{Method: "GET", Path: "/api/v1/admin/users", Tag: "管理", Summary: "用户列表", Auth: openapi.AuthAdmin, Query: pageParams,
+			Data: UserListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/admin/users/:id/unlock", Tag: "管理", Summary: "解除用户登录锁定",
+			Description: "清零账号的登录失败次数和两步验证失败次数，不影响 IP 锁定。", Auth: openapi.AuthAdmin,
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/login-attempts", Tag: "管理", Summary: "登录记录", Description: "最新的在前，保留 login.retention_days 天。",
+			Auth: openapi.AuthAdmin, Query: loginAttemptParams, Data: LoginAttemptListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/audit", Tag: "管理", Summary: "审计日志",
+			Description: "记录文章和评论的增删改、角色变更、封禁和登录，最新的在前，保留 audit.retention_days 天。",
+			Auth:        openapi.AuthAdmin, Query: auditParams, Data: AuditLogListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/trash/posts", Tag: "管理", Summary: "回收站中的文章", Description: "所有用户删除的文章，最近删除的在前。",
+			Auth: openapi.AuthAdmin, Query: trashParams, Data: TrashPostListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/admin/trash/posts/:id/restore", Tag: "管理", Summary: "恢复文章", Auth: openapi.AuthAdmin,
+			Data: models.PostResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/admin/trash/posts/:id", Tag: "管理", Summary: "永久删除文章", Description: "文章下的所有评论一并删除。",
+			Auth: openapi.AuthAdmin, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/trash/comments", Tag: "管理", Summary: "回收站中的评论", Description: "所有用户删除的评论，最近删除的在前。",
+			Auth: openapi.AuthAdmin, Query: trashParams, Data: TrashCommentListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/admin/trash/comments/:id/restore", Tag: "管理", Summary: "恢复评论", Auth: openapi.AuthAdmin,
+			Data: models.CommentResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/admin/trash/comments/:id", Tag: "管理", Summary: "永久删除评论", Description: "评论的所有层级的回复一并删除，不论作者。",
+			Auth: openapi.AuthAdmin, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/comments/:id/history", Tag: "管理", Summary: "评论修改记录",
+			Description: "评论当前内容和每次修改前的版本，最近修改的在前。回收站中的评论也可以查看。", Auth: openapi.AuthAdmin,
+			Data: services.CommentHistory{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "PUT", Path: "/api/v1/admin/comments/:id/approval", Tag: "管理", Summary: "审核评论",
+			Description: "通过或驳回评论。驳回的评论在作者修改后仍然待审核，只能由管理员重新通过；变为审核通过时与发表时一样推送 comment.created 事件。",
+			Auth:        openapi.AuthAdmin, Body: controllers.ModerateCommentRequest{}, Data: models.CommentResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/admin/export", Tag: "管理", Summary: "创建全站导出任务", Auth: openapi.AuthAdmin,
+			Status: http.StatusAccepted, Data: controllers.ExportJobResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/exports/:id", Tag: "管理", Summary: "查询全站导出任务", Auth: openapi.AuthAdmin,
+			Data: controllers.ExportJobResponse{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "GET", Path: "/api/v1/admin/exports/:id/download", Tag: "管理", Summary: "下载全站导出归档", Auth: openapi.AuthAdmin,
+			Produces: "application/zip", Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusGone}},
+		{Method: "POST", Path: "/api/v1/admin/import", Tag: "管理", Summary: "导入 Markdown 或 WordPress WXR",
+			Description: "按来源中的 ID 记录导入结果，重复导入时未变化的条目跳过。",
+			Auth:        openapi.AuthAdmin,
+			Form: append(importForm,
+				openapi.FormField{Name: "author_map", Description: "作者映射，如 wp_admin=admin,alice=alice2"},
+				openapi.FormField{Name: "default_author", Description: "作者无法匹配时使用的本地用户名"},
+				openapi.FormField{Name: "create_authors", Description: "true 时为无法匹配的作者创建用户"},
+				openapi.FormField{Name: "fallback_commenter", Description: "评论者不是本地用户时使用的本地用户名"},
+				openapi.FormField{Name: "source", Description: "来源标识，默认由文件名生成"},
+			),
+			Data: importer.Report{}, Errors: []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/webhooks", Tag: "管理", Summary: "全站端点列表", Auth: openapi.AuthAdmin,
+			Data: WebhookListResponse{}, Errors: []int{http.StatusInternalServerError}},
+		{Method: "POST", Path: "/api/v1/admin/webhooks", Tag: "管理", Summary: "注册全站端点", Description: "接收所有用户内容的事件。签名密钥只在创建时返回一次。",
+			Auth: openapi.AuthAdmin, Body: controllers.CreateWebhookRequest{}, Status: http.StatusCreated, Data: controllers.CreateWebhookResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/webhooks/:id", Tag: "管理", Summary: "获取全站端点详情", Auth: openapi.AuthAdmin,
+			Data: models.Webhook{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		{Method: "PUT", Path: "/api/v1/admin/webhooks/:id", Tag: "管理", Summary: "更新全站端点", Auth: openapi.AuthAdmin,
+			Body: controllers.UpdateWebhookRequest{}, Data: models.Webhook{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "DELETE", Path: "/api/v1/admin/webhooks/:id", Tag: "管理", Summary: "删除全站端点", Auth: openapi.AuthAdmin,
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/webhooks/:id/deliveries", Tag: "管理", Summary: "全站端点的投递记录", Auth: openapi.AuthAdmin,
+			Query: webhookPageParams, Data: WebhookDeliveryListResponse{},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}},
+		{Method: "GET", Path: "/api/v1/admin/webhooks/:id/deliveries/:deliveryId", Tag: "管理", Summary: "全站端点的投递详情", Auth: openapi.AuthAdmin,
+			Data: models.WebhookDelivery{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	}
+}
+
+// BuildOpenAPI 生成 OpenAPI 文档
+func BuildOpenAPI() map[string]interface{} {
+	builder := openapi.NewBuilder(openapi.Info{
+		Title:       "Personal Blog System API",
+		Version:     "1.0.0",
+		Description: "A blog system built with Gin and GORM。除健康检查和下载接口外，响应均使用统一结构 {success, message, data, error}。",
+	}, utils.Response{})
+	builder.Enum(models.PostStatus(""), string(models.PostStatusDraft), string(models.PostStatusPublished), string(models.PostStatusArchived))
+	builder.Enum(models.ExportScope(""), string(models.ExportScopeUser), string(models.ExportScopeSite))
+	builder.Enum(models.ExportStatus(""), string(models.ExportStatusPending), string(models.ExportStatusRunning),
+		string(models.ExportStatusCompleted), string(models.ExportStatusFailed), string(models.ExportStatusExpired))
+	events := make([]string, len(models.WebhookEventList))
+	for i, event := range models.WebhookEventList {
+		events[i] = string(event)
+	}
+	builder.Enum(models.WebhookEvent(""), events...)
+	builder.Enum(models.WebhookScope(""), string(models.WebhookScopeUser), string(models.WebhookScopeSite))
+	builder.Enum(models.LoginResult(""), string(models.LoginResultSuccess), string(models.LoginResultFailed),
+		string(models.LoginResultBlocked), string(models.LoginResultUnlocked))
+	builder.Enum(models.WebhookDeliveryStatus(""), string(models.WebhookDeliveryPending), string(models.WebhookDeliverySucceeded), string(models.WebhookDeliveryFailed))
+	scopes := make([]string, len(models.TokenScopeList))
+	for i, scope := range models.TokenScopeList {
+		scopes[i] = string(scope)
+	}
+	builder.Enum(models.TokenScope(""), scopes...)
+	auditActions := make([]string, len(models.AuditActionList))
+	for i, action := range models.AuditActionList {
+		auditActions[i] = string(action)
+	}
+	builder.Enum(models.AuditAction(""), auditActions...)
+
+	ops := apiOperations()
+	for i := range ops {
+		if scope := tokenScopes[ops[i].Method+" "+ops[i].Path]; scope != "" {
+			ops[i].Description = strings.TrimSpace(ops[i].Description + " 个人访问令牌需要 " + string(scope) + " 权限。")
+		}
+	}
+	return builder.Build(ops)
+}
+
+// UndocumentedRoutes 返回已注册但缺少文档的路由，以及有文档但未注册的路由
+func UndocumentedRoutes(r *gin.Engine) (missing, stale []string) {
+	ops := apiOperations()
+	return openapi.Undocumented(r.Routes(), ops), openapi.Stale(r.Routes(), ops)
+}
+
+// SetupSwaggerRoutes 设置 OpenAPI 文档和 Swagger UI 路由
+func SetupSwaggerRoutes(r *gin.Engine) {
+	var once sync.Once
+	var spec map[string]interface{}
+
+	r.GET("/openapi.json", func(c *gin.Context) {
+		once.Do(func() { spec = BuildOpenAPI() })
+		c.JSON(http.StatusOK, spec)
+	})
+
+	r.GET("/swagger", func(c *gin.Context) {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "生成页面失败", err.Error())
+			return
+		}
+		n := base64.StdEncoding.EncodeToString(nonce)
+		cdn := "https://unpkg.com"
+
+		// 放开全局 CSP 中对 Swagger UI 脚本和样式的限制
+		c.Header("Content-Security-Policy", fmt.Sprintf(
+			"default-src 'self'; script-src %s 'nonce-%s'; style-src %s 'unsafe-inline'; img-src 'self' data: %s", cdn, n, cdn, cdn))
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(swaggerPage, cdn, swaggerUIVersion, cdn, swaggerUIVersion, n)))
+	})
+}
+
+// swaggerPage Swagger UI 页面
+const swaggerPage = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+  <meta charset="utf-8">
+  <title>Personal Blog System API</title>
+  <link rel="stylesheet" href="%s/swagger-ui-dist@%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%s/swagger-ui-dist@%s/swagger-ui-bundle.js"></script>
+  <script nonce="%s">
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"testing"
+
+	"blog-system/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRoutesDocumented 所有注册的路由都必须写入 OpenAPI 文档，文档中也不能有不存在的路由
+func TestRoutesDocumented(t *testing.T) {
+	if err := config.Init(); err != nil {
+		t.Fatalf("配置初始化失败: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	SetupRoutes(r)
+
+	missing, stale := UndocumentedRoutes(r)
+	for _, route := range missing {
+		t.Errorf("未写入文档的路由: %s", route)
+	}
+	for _, route := range stale {
+		t.Errorf("文档中不存在的路由: %s", route)
+	}
+}
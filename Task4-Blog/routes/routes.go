@@ -1,8 +1,18 @@
 package routes
 
 import (
+	"log"
+	"net/http"
+	"reflect"
+	"time"
+
+	"blog-system/config"
 	"blog-system/controllers"
+	"blog-system/graph"
+	"blog-system/health"
+	"blog-system/jobs"
 	"blog-system/middleware"
+	"blog-system/ratelimit"
 	"blog-system/services"
 
 	"github.com/gin-gonic/gin"
@@ -15,15 +25,48 @@ func SetupRoutes(r *gin.Engine) {
 	userService := services.NewUserService()
 	postService := services.NewPostService()
 	commentService := services.NewCommentService()
+	exportService := services.NewExportService()
+	importService := services.NewImportService()
+	webhookService := services.NewWebhookService()
+	twoFactorService := services.NewTwoFactorService()
+	loginAttemptService := services.NewLoginAttemptService()
+	signingKeyService := services.NewSigningKeyService()
+	accessTokenService := services.NewPersonalAccessTokenService()
+	auditService := services.NewAuditService()
+	trashService := services.NewTrashService()
 
 	// 初始化控制器
 	authController := controllers.NewAuthController(authService, userService)
 	userController := controllers.NewUserController(userService)
 	postController := controllers.NewPostController(postService)
 	commentController := controllers.NewCommentController(commentService)
+	exportController := controllers.NewExportController(exportService)
+	importController := controllers.NewImportController(importService)
+	webhookController := controllers.NewWebhookController(webhookService)
+	twoFactorController := controllers.NewTwoFactorController(twoFactorService, authService, userService)
+	loginAttemptController := controllers.NewLoginAttemptController(loginAttemptService)
+	accessTokenController := controllers.NewPersonalAccessTokenController(accessTokenService)
+	auditController := controllers.NewAuditController(auditService)
+	trashController := controllers.NewTrashController(trashService)
+	graphHandler := graph.NewHandler(postService, commentService, userService)
 
 	// 初始化中间件
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	authMiddleware := middleware.NewAuthMiddleware(authService, accessTokenService, tokenScopes)
+	rateStore, err := ratelimit.NewStore(config.GetConfig().RateLimit)
+	if err != nil {
+		log.Fatalf("初始化限流存储失败: %v", err)
+	}
+	rateLimiter := newRateLimiter(rateStore)
+
+	// 后台任务
+	jobs.Register(jobs.Job{Name: "export-cleanup", Interval: time.Hour, Run: exportService.CleanupExpired})
+	jobs.Register(jobs.Job{Name: "webhook-delivery", Interval: 10 * time.Second, Run: webhookService.DeliverDue})
+	jobs.Register(jobs.Job{Name: "webhook-cleanup", Interval: time.Hour, Run: webhookService.CleanupDeliveries})
+	jobs.Register(jobs.Job{Name: "siwe-nonce-cleanup", Interval: time.Hour, Run: authService.CleanupNonces})
+	jobs.Register(jobs.Job{Name: "login-attempt-cleanup", Interval: time.Hour, Run: loginAttemptService.CleanupAttempts})
+	jobs.Register(jobs.Job{Name: "jwt-key-rotation", Interval: time.Hour, Run: signingKeyService.RotateKeys})
+	jobs.Register(jobs.Job{Name: "audit-log-cleanup", Interval: time.Hour, Run: auditService.CleanupLogs})
+	jobs.Register(jobs.Job{Name: "trash-purge", Interval: time.Hour, Run: trashService.PurgeExpired})
 
 	// 全局中间件
 	setupGlobalMiddleware(r)
@@ -33,50 +76,105 @@ func SetupRoutes(r *gin.Engine) {
 	{
 		// 公开路由 - 不需要认证
 		public := api.Group("")
+		public.Use(rateLimiter.Limit("public"))
 		{
-			setupPublicRoutes(public, authController, userController, postController, commentController)
+			setupPublicRoutes(public, authMiddleware, rateLimiter, authController, twoFactorController, userController, postController, commentController)
 		}
 
 		// 受保护路由 - 需要认证
 		protected := api.Group("")
-		protected.Use(authMiddleware.AuthRequired())
+		protected.Use(authMiddleware.AuthRequired(), rateLimiter.Limit("user"))
 		{
-			setupProtectedRoutes(protected, authController, userController, postController, commentController)
+			setupProtectedRoutes(protected, authController, twoFactorController, accessTokenController, trashController, userController, postController, commentController, exportController, importController, webhookController)
 		}
 
 		// 管理员路由 - 需要管理员权限
 		admin := api.Group("/admin")
-		admin.Use(authMiddleware.AdminRequired())
+		admin.Use(authMiddleware.AdminRequired(), rateLimiter.Limit("admin"))
 		{
-			setupAdminRoutes(admin, userController, postController, commentController)
+			setupAdminRoutes(admin, userController, loginAttemptController, auditController, trashController, postController, commentController, exportController, importController, webhookController)
 		}
 	}
 
 	// 健康检查路由（不在 API 分组内）
-	setupHealthRoutes(r)
+	setupHealthRoutes(r, rateStore)
+
+	// 验证 token 使用的公钥，供其他服务使用
+	r.GET("/.well-known/jwks.json", authController.GetJWKS)
+
+	// GraphQL 接口（认证可选）
+	setupGraphQLRoutes(r, graphHandler, authMiddleware, rateLimiter)
+
+	// OpenAPI 文档和 Swagger UI
+	SetupSwaggerRoutes(r)
+
+	if stale := staleTokenScopes(r); len(stale) > 0 {
+		log.Fatalf("个人访问令牌权限表中有未注册的路由: %v", stale)
+	}
 }
 
-// setupGlobalMiddleware 设置全局中间件
+// setupGlobalMiddleware 设置全局中间件，日志级别、跨域和安全头配置随配置热加载更新
 func setupGlobalMiddleware(r *gin.Engine) {
+	cfg := config.GetConfig()
+	cors := middleware.NewCORSMiddleware(cfg.CORS)
+	security := middleware.NewSecurityMiddleware(cfg.Security)
+	middleware.SetLogLevel(cfg.Log.Level)
+
+	config.OnChange(func(old, cfg *config.Config) {
+		if cfg.Log.Level != old.Log.Level {
+			middleware.SetLogLevel(cfg.Log.Level)
+		}
+		if !reflect.DeepEqual(cfg.CORS, old.CORS) {
+			cors.Update(cfg.CORS)
+		}
+		if !reflect.DeepEqual(cfg.Security, old.Security) {
+			security.Update(cfg.Security)
+		}
+	})
+
+	// 请求 ID 中间件
+	r.Use(middleware.RequestID())
 	// 跨域中间件
-	r.Use(middleware.CORS())
+	r.Use(cors.Handler())
 	// 日志中间件
 	r.Use(middleware.Logger())
 	// 恢复中间件
 	r.Use(middleware.Recovery())
 	// 安全中间件
-	r.Use(middleware.SecurityHeaders())
-	// 限流中间件（每秒10个请求，突发20个）
-	// r.Use(middleware.GlobalRateLimit(10, 20))
+	r.Use(security.Headers())
+}
+
+// newRateLimiter 按配置创建限流器，限流关闭时各分组都不限制。配额随配置热加载更新
+func newRateLimiter(store ratelimit.Store) *middleware.RateLimiter {
+	rateLimiter := middleware.NewRateLimiter(store, ratePolicies(config.GetConfig().RateLimit))
+	config.OnChange(func(old, cfg *config.Config) {
+		if !reflect.DeepEqual(cfg.RateLimit, old.RateLimit) {
+			rateLimiter.Update(ratePolicies(cfg.RateLimit))
+		}
+	})
+	return rateLimiter
+}
+
+// ratePolicies 生效的限流配额，限流关闭时为空
+func ratePolicies(cfg config.RateLimitConfig) map[string]config.RateLimitPolicy {
+	if !cfg.Enabled {
+		return nil
+	}
+	return cfg.Policies
 }
 
 // setupPublicRoutes 设置公开路由
-func setupPublicRoutes(public *gin.RouterGroup, authController *controllers.AuthController, userController *controllers.UserController, postController *controllers.PostController, commentController *controllers.CommentController) {
-	// 认证相关
-	auth := public.Group("/auth")
+func setupPublicRoutes(public *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware, rateLimiter *middleware.RateLimiter, authController *controllers.AuthController, twoFactorController *controllers.TwoFactorController, userController *controllers.UserController, postController *controllers.PostController, commentController *controllers.CommentController) {
+	// 认证相关，额外使用更严格的 auth 配额
+	auth := public.Group("/auth", rateLimiter.Limit("auth"))
 	{
 		auth.POST("/register", authController.Register)
 		auth.POST("/login", authController.Login)
+		auth.GET("/siwe/nonce", authController.GetSIWENonce)
+		// 携带 token 时将钱包绑定到当前用户
+		auth.POST("/siwe", authMiddleware.OptionalAuth(), authController.SignInWithEthereum)
+		// 两步验证登录的第二步
+		auth.POST("/2fa/verify", twoFactorController.Verify)
 	}
 
 	// 用户相关
@@ -98,17 +196,64 @@ func setupPublicRoutes(public *gin.RouterGroup, authController *controllers.Auth
 	{
 		comments.GET("/posts/:postId", commentController.GetPostComments)
 		comments.GET("/:id", commentController.GetCommentByID)
+		comments.GET("/:id/replies", commentController.GetCommentReplies)
 	}
 }
 
 // setupProtectedRoutes 设置受保护路由（需要登录）
-func setupProtectedRoutes(protected *gin.RouterGroup, authController *controllers.AuthController, userController *controllers.UserController, postController *controllers.PostController, commentController *controllers.CommentController) {
+func setupProtectedRoutes(protected *gin.RouterGroup, authController *controllers.AuthController, twoFactorController *controllers.TwoFactorController, accessTokenController *controllers.PersonalAccessTokenController, trashController *controllers.TrashController, userController *controllers.UserController, postController *controllers.PostController, commentController *controllers.CommentController, exportController *controllers.ExportController, importController *controllers.ImportController, webhookController *controllers.WebhookController) {
 	// 用户相关
 	users := protected.Group("/users")
 	{
 		users.GET("/profile", authController.GetProfile)
 		users.PUT("/profile", authController.UpdateProfile)
 		users.GET("/my/posts", postController.GetUserPosts)
+		users.GET("/my/comments", commentController.GetMyComments)
+		users.GET("/my/export", exportController.ExportMyBlog)
+		users.GET("/my/exports/:id", exportController.GetMyExport)
+		users.GET("/my/exports/:id/download", exportController.DownloadMyExport)
+		users.POST("/my/import", importController.ImportMyBlog)
+	}
+
+	// 两步验证
+	twoFactor := protected.Group("/users/my/2fa")
+	{
+		twoFactor.GET("", twoFactorController.GetStatus)
+		twoFactor.POST("/enroll", twoFactorController.BeginEnrollment)
+		twoFactor.POST("/confirm", twoFactorController.ConfirmEnrollment)
+		twoFactor.POST("/disable", twoFactorController.Disable)
+		twoFactor.POST("/recovery-codes", twoFactorController.RegenerateRecoveryCodes)
+	}
+
+	// 个人访问令牌（只能使用登录 token 管理）
+	tokens := protected.Group("/users/my/tokens")
+	{
+		tokens.GET("", accessTokenController.GetMyTokens)
+		tokens.POST("", accessTokenController.CreateMyToken)
+		tokens.DELETE("/:id", accessTokenController.RevokeMyToken)
+	}
+
+	// 回收站
+	trash := protected.Group("/users/my/trash")
+	{
+		trash.GET("/posts", trashController.GetMyDeletedPosts)
+		trash.POST("/posts/:id/restore", trashController.RestoreMyPost)
+		trash.DELETE("/posts/:id", trashController.PurgeMyPost)
+		trash.GET("/comments", trashController.GetMyDeletedComments)
+		trash.POST("/comments/:id/restore", trashController.RestoreMyComment)
+		trash.DELETE("/comments/:id", trashController.PurgeMyComment)
+	}
+
+	// Webhook 端点
+	webhooks := protected.Group("/users/my/webhooks")
+	{
+		webhooks.GET("", webhookController.GetMyWebhooks)
+		webhooks.POST("", webhookController.CreateMyWebhook)
+		webhooks.GET("/:id", webhookController.GetMyWebhook)
+		webhooks.PUT("/:id", webhookController.UpdateMyWebhook)
+		webhooks.DELETE("/:id", webhookController.DeleteMyWebhook)
+		webhooks.GET("/:id/deliveries", webhookController.GetMyWebhookDeliveries)
+		webhooks.GET("/:id/deliveries/:deliveryId", webhookController.GetMyWebhookDelivery)
 	}
 
 	// 文章相关
@@ -123,64 +268,122 @@ func setupProtectedRoutes(protected *gin.RouterGroup, authController *controller
 	comments := protected.Group("/comments")
 	{
 		comments.POST("", commentController.CreateComment)
+		comments.PUT("/:id", commentController.UpdateComment)
 		comments.DELETE("/:id", commentController.DeleteComment)
 	}
 }
 
 // setupAdminRoutes 设置管理员路由
-func setupAdminRoutes(admin *gin.RouterGroup, userController *controllers.UserController, postController *controllers.PostController, commentController *controllers.CommentController) {
+func setupAdminRoutes(admin *gin.RouterGroup, userController *controllers.UserController, loginAttemptController *controllers.LoginAttemptController, auditController *controllers.AuditController, trashController *controllers.TrashController, postController *controllers.PostController, commentController *controllers.CommentController, exportController *controllers.ExportController, importController *controllers.ImportController, webhookController *controllers.WebhookController) {
 	// 用户管理
 	users := admin.Group("/users")
 	{
 		users.GET("", userController.GetUsers)
+		users.POST("/:id/unlock", loginAttemptController.UnlockUser)
 		// 可以添加更多管理员功能：用户封禁、角色修改等
 	}
 
+	// 登录记录
+	admin.GET("/login-attempts", loginAttemptController.GetLoginAttempts)
+
+	// 审计日志
+	admin.GET("/audit", auditController.GetAuditLogs)
+
+	// 回收站
+	trash := admin.Group("/trash")
+	{
+		trash.GET("/posts", trashController.GetDeletedPosts)
+		trash.POST("/posts/:id/restore", trashController.RestorePost)
+		trash.DELETE("/posts/:id", trashController.PurgePost)
+		trash.GET("/comments", trashController.GetDeletedComments)
+		trash.POST("/comments/:id/restore", trashController.RestoreComment)
+		trash.DELETE("/comments/:id", trashController.PurgeComment)
+	}
+
+	// 全站导出
+	admin.POST("/export", exportController.CreateSiteExport)
+	admin.GET("/exports/:id", exportController.GetSiteExport)
+	admin.GET("/exports/:id/download", exportController.DownloadSiteExport)
+
+	// 内容导入
+	admin.POST("/import", importController.ImportSite)
+
+	// 全站 Webhook 端点
+	webhooks := admin.Group("/webhooks")
+	{
+		webhooks.GET("", webhookController.GetSiteWebhooks)
+		webhooks.POST("", webhookController.CreateSiteWebhook)
+		webhooks.GET("/:id", webhookController.GetSiteWebhook)
+		webhooks.PUT("/:id", webhookController.UpdateSiteWebhook)
+		webhooks.DELETE("/:id", webhookController.DeleteSiteWebhook)
+		webhooks.GET("/:id/deliveries", webhookController.GetSiteWebhookDeliveries)
+		webhooks.GET("/:id/deliveries/:deliveryId", webhookController.GetSiteWebhookDelivery)
+	}
+
 	// // 文章管理
 	// posts := admin.Group("/posts")
 	// {
 	// 	// 可以添加文章审核、推荐等功能
 	// }
 
-	// // 评论管理
-	// comments := admin.Group("/comments")
-	// {
-	// 	// 可以添加评论审核、删除等功能
-	// }
+	// 评论管理
+	comments := admin.Group("/comments")
+	{
+		comments.GET("/:id/history", commentController.GetCommentHistory)
+		comments.PUT("/:id/approval", commentController.ModerateComment)
+	}
 }
 
 // setupHealthRoutes 设置健康检查路由
-func setupHealthRoutes(r *gin.Engine) {
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "Blog system is running",
-			"version": "1.0.0",
-		})
-	})
+func setupHealthRoutes(r *gin.Engine, rateStore ratelimit.Store) {
+	cfg := config.GetConfig()
+	timeout := time.Duration(cfg.Health.CheckTimeout) * time.Second
+	workerGrace := time.Duration(cfg.Health.WorkerGrace) * time.Second
+
+	// 存活检查：进程及后台任务是否正常运行
+	liveness := health.NewRegistry(timeout)
+	liveness.Register("workers", health.WorkersChecker(workerGrace))
+
+	// 就绪检查：依赖是否可用，未就绪时不应接收流量
+	readiness := health.NewRegistry(timeout)
+	readiness.Register("database", health.DatabaseChecker())
+	readiness.Register("migrations", health.MigrationsChecker())
+	readiness.Register("storage", health.StorageChecker(cfg.Storage.UploadDir))
+	readiness.Register("workers", health.WorkersChecker(workerGrace))
+	if redisStore, ok := rateStore.(*ratelimit.RedisStore); ok {
+		readiness.Register("rate_limit_store", redisStore.Ping)
+	}
+
+	r.GET("/livez", healthHandler(liveness))
+	r.GET("/readyz", healthHandler(readiness))
 
 	r.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"name":        "Personal Blog System API",
 			"version":     "1.0.0",
 			"description": "A blog system built with Gin and GORM",
-			"docs":        "/api/v1",
+			"docs":        "/swagger",
 		})
 	})
+}
 
-	// 数据库信息
-	r.GET("/db-info", func(c *gin.Context) {
-		// 这里可以添加数据库连接检查
-		c.JSON(200, gin.H{
-			"database": "connected",
-			"status":   "healthy",
-		})
-	})
+// setupGraphQLRoutes 设置 GraphQL 路由，未登录时只能查询公开数据
+func setupGraphQLRoutes(r *gin.Engine, graphHandler *graph.Handler, authMiddleware *middleware.AuthMiddleware, rateLimiter *middleware.RateLimiter) {
+	r.GET("/graphql", authMiddleware.OptionalAuth(), rateLimiter.Limit("graphql"), graphHandler.Serve)
+	r.POST("/graphql", authMiddleware.OptionalAuth(), rateLimiter.Limit("graphql"), graphHandler.Serve)
 }
 
-// SetupSwaggerRoutes 设置 Swagger 文档路由（可选）
-func SetupSwaggerRoutes(r *gin.Engine) {
-	// r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+// healthHandler 执行检查并返回报告，未通过时返回 503
+func healthHandler(registry *health.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := registry.Run(c.Request.Context())
+
+		status := http.StatusOK
+		if !report.OK() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
 }
 
 // SetupTestRoutes 设置测试路由（开发环境使用）
@@ -205,4 +408,4 @@ func SetupTestRoutes(r *gin.Engine) {
 			})
 		})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"sort"
+
+	"blog-system/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenScopes 接受个人访问令牌的接口及所需的权限范围，新增接口时按需添加。
+// 未列出的接口（令牌管理、两步验证、管理员接口等）只接受登录 token；
+// 权限范围为空的接口由处理函数自行检查（GraphQL 在变更中检查）
+var tokenScopes = map[string]models.TokenScope{
+	"GET /api/v1/users/profile":                                models.ScopeProfileRead,
+	"PUT /api/v1/users/profile":                                models.ScopeProfileWrite,
+	"GET /api/v1/users/my/posts":                               models.ScopePostsRead,
+	"GET /api/v1/users/my/comments":                            models.ScopeCommentsRead,
+	"GET /api/v1/users/my/export":                              models.ScopePostsRead,
+	"GET /api/v1/users/my/exports/:id":                         models.ScopePostsRead,
+	"GET /api/v1/users/my/exports/:id/download":                models.ScopePostsRead,
+	"POST /api/v1/users/my/import":                             models.ScopePostsWrite,
+	"GET /api/v1/users/my/trash/posts":                         models.ScopePostsRead,
+	"POST /api/v1/users/my/trash/posts/:id/restore":            models.ScopePostsWrite,
+	"DELETE /api/v1/users/my/trash/posts/:id":                  models.ScopePostsWrite,
+	"GET /api/v1/users/my/trash/comments":                      models.ScopeCommentsRead,
+	"POST /api/v1/users/my/trash/comments/:id/restore":         models.ScopeCommentsWrite,
+	"DELETE /api/v1/users/my/trash/comments/:id":               models.ScopeCommentsWrite,
+	"GET /api/v1/users/my/webhooks":                            models.ScopeWebhooksRead,
+	"POST /api/v1/users/my/webhooks":                           models.ScopeWebhooksWrite,
+	"GET /api/v1/users/my/webhooks/:id":                        models.ScopeWebhooksRead,
+	"PUT /api/v1/users/my/webhooks/:id":                        models.ScopeWebhooksWrite,
+	"DELETE /api/v1/users/my/webhooks/:id":                     models.ScopeWebhooksWrite,
+	"GET /api/v1/users/my/webhooks/:id/deliveries":             models.ScopeWebhooksRead,
+	"GET /api/v1/users/my/webhooks/:id/deliveries/:deliveryId": models.ScopeWebhooksRead,
+	"POST /api/v1/posts":                                       models.ScopePostsWrite,
+	"PUT /api/v1/posts/:id":                                    models.ScopePostsWrite,
+	"DELETE /api/v1/posts/:id":                                 models.ScopePostsWrite,
+	"POST /api/v1/comments":                                    models.ScopeCommentsWrite,
+	"PUT /api/v1/comments/:id":                                 models.ScopeCommentsWrite,
+	"DELETE /api/v1/comments/:id":                              models.ScopeCommentsWrite,
+	"GET /graphql":                                             "",
+	"POST /graphql":                                            "",
+}
+
+// staleTokenScopes 返回 tokenScopes 中没有对应路由的条目
+func staleTokenScopes(r *gin.Engine) []string {
+	registered := make(map[string]bool)
+	for _, route := range r.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	var stale []string
+	for route := range tokenScopes {
+		if !registered[route] {
+			stale = append(stale, route)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
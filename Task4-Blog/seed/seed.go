@@ -0,0 +1,506 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"blog-system/models"
+	"blog-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// DefaultPassword 所有测试用户的密码
+const DefaultPassword = "password123"
+
+// ErrAlreadySeeded 使用相同种子的测试数据已存在
+var ErrAlreadySeeded = errors.New("使用该种子的测试数据已存在，请更换 -seed 或先执行 reset-db")
+
+// Options 测试数据生成选项
+type Options struct {
+	Seed            int64     // 随机种子，相同种子生成相同的数据
+	Users           int       // 用户数量
+	Posts           int       // 文章数量
+	Tags            int       // 标签数量
+	CommentsPerPost float64   // 已发布文章的平均评论数
+	MaxDepth        int       // 评论最大嵌套层数
+	BatchSize       int       // 每批写入的文章数量
+	Until           time.Time // 数据时间范围的结束时间
+	Days            int       // 数据时间跨度（天）
+}
+
+// DefaultOptions 默认生成选项
+func DefaultOptions() Options {
+	return Options{
+		Seed:            1,
+		Users:           50,
+		Posts:           500,
+		Tags:            20,
+		CommentsPerPost: 8,
+		MaxDepth:        4,
+		BatchSize:       200,
+		Days:            365,
+	}
+}
+
+// Stats 生成结果统计
+type Stats struct {
+	Users    int
+	Posts    int
+	Tags     int
+	Comments int
+	Elapsed  time.Duration
+}
+
+// generator 测试数据生成器
+type generator struct {
+	db    *gorm.DB
+	opts  Options
+	r     *rand.Rand
+	start time.Time
+
+	users     []models.User
+	userLangs []language
+	tags      []models.Tag
+	authorOf  func() int
+	tagOf     func() int
+	stats     Stats
+}
+
+// commentNode 评论树节点（写入前）
+type commentNode struct {
+	comment models.Comment
+	parent  int // 父节点在同一文章节点列表中的下标，-1 表示顶级评论
+	depth   int
+}
+
+// Run 按选项生成测试数据
+func Run(db *gorm.DB, opts Options) (*Stats, error) {
+	if err := opts.normalize(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		db:    db.Session(&gorm.Session{SkipHooks: true}),
+		opts:  opts,
+		r:     rand.New(rand.NewSource(opts.Seed)),
+		start: opts.Until.AddDate(0, 0, -opts.Days),
+	}
+
+	began := time.Now()
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"用户", g.createUsers},
+		{"标签", g.createTags},
+		{"文章和评论", g.createPosts},
+		{"统计数据", g.updateCounters},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			return nil, fmt.Errorf("生成%s失败: %w", step.name, err)
+		}
+	}
+
+	g.stats.Elapsed = time.Since(began)
+	return &g.stats, nil
+}
+
+// normalize 校验并补全选项
+func (o *Options) normalize() error {
+	if o.Users < 1 {
+		return fmt.Errorf("用户数量至少为 1")
+	}
+	if o.Posts < 0 || o.Tags < 0 || o.CommentsPerPost < 0 {
+		return fmt.Errorf("数量不能为负数")
+	}
+	if o.MaxDepth < 1 {
+		o.MaxDepth = 1
+	}
+	if o.BatchSize < 1 {
+		o.BatchSize = 200
+	}
+	if o.Days < 1 {
+		o.Days = 365
+	}
+	if o.Until.IsZero() {
+		// 默认以当天零点为结束时间，保证同一天内多次生成的数据一致
+		now := time.Now()
+		o.Until = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	return nil
+}
+
+// createUsers 生成用户，第一个用户为管理员
+func (g *generator) createUsers() error {
+	hashed, err := utils.HashPassword(DefaultPassword)
+	if err != nil {
+		return err
+	}
+
+	g.users = make([]models.User, g.opts.Users)
+	g.userLangs = make([]language, g.opts.Users)
+	for i := range g.users {
+		lang := langEnglish
+		bio := pick(g.r, enBios)
+		if g.r.Intn(2) == 0 {
+			lang = langChinese
+			bio = pick(g.r, zhBios)
+		}
+		username := fmt.Sprintf("%s_%s%d_%d", pick(g.r, firstNames), pick(g.r, lastNames), i+1, g.opts.Seed)
+		role := "user"
+		if i == 0 {
+			role = "admin"
+		}
+		// 用户注册时间集中在时间范围的前半段
+		createdAt := g.randomTime(g.start, g.start.Add(g.span()/2))
+
+		g.userLangs[i] = lang
+		g.users[i] = models.User{
+			Username:  username,
+			Email:     username + "@example.com",
+			Password:  hashed,
+			Bio:       bio,
+			Avatar:    fmt.Sprintf("https://api.dicebear.com/7.x/identicon/svg?seed=%s", username),
+			Role:      role,
+			IsActive:  i == 0 || g.r.Intn(50) != 0, // 约 2% 的普通用户被封禁
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+	}
+
+	var count int64
+	if err := g.db.Model(&models.User{}).Where("username = ?", g.users[0].Username).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadySeeded
+	}
+
+	// User.IsActive 带有 default:true，插入时 false 会被写成 true，封禁状态需要在插入后单独写入
+	var banned []int
+	for i, user := range g.users {
+		if !user.IsActive {
+			banned = append(banned, i)
+		}
+	}
+	if err := g.db.CreateInBatches(g.users, g.opts.BatchSize).Error; err != nil {
+		return err
+	}
+	if len(banned) > 0 {
+		ids := make([]uint, len(banned))
+		for i, idx := range banned {
+			g.users[idx].IsActive = false
+			ids[i] = g.users[idx].ID
+		}
+		if err := g.db.Model(&models.User{}).Where("id IN ?", ids).UpdateColumn("is_active", false).Error; err != nil {
+			return err
+		}
+	}
+
+	// 发文数量服从 Zipf 分布：少数作者贡献了大部分文章
+	if len(g.users) > 1 {
+		perm := g.r.Perm(len(g.users))
+		zipf := rand.NewZipf(g.r, 1.3, 1, uint64(len(g.users)-1))
+		g.authorOf = func() int { return perm[zipf.Uint64()] }
+	} else {
+		g.authorOf = func() int { return 0 }
+	}
+
+	g.stats.Users = len(g.users)
+	log.Printf("已生成 %d 个用户（默认密码: %s）", len(g.users), DefaultPassword)
+	return nil
+}
+
+// createTags 生成标签，已存在的同名标签会被复用
+func (g *generator) createTags() error {
+	for i := 0; i < g.opts.Tags; i++ {
+		name := fmt.Sprintf("tag-%d", i+1)
+		if i < len(tagNames) {
+			name = tagNames[i]
+		}
+		slug := utils.Slugify(name)
+		if slug == "" {
+			slug = fmt.Sprintf("tag-%d", i+1)
+		}
+
+		tag := models.Tag{Name: name, Slug: slug, Color: tagColors[i%len(tagColors)]}
+		if err := g.db.Where(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return err
+		}
+		g.tags = append(g.tags, tag)
+	}
+
+	if len(g.tags) > 1 {
+		// 标签热度同样服从 Zipf 分布
+		zipf := rand.NewZipf(g.r, 1.1, 2, uint64(len(g.tags)-1))
+		g.tagOf = func() int { return int(zipf.Uint64()) }
+	} else {
+		g.tagOf = func() int { return 0 }
+	}
+
+	g.stats.Tags = len(g.tags)
+	return nil
+}
+
+// createPosts 分批生成文章、标签关联和评论
+func (g *generator) createPosts() error {
+	for offset := 0; offset < g.opts.Posts; offset += g.opts.BatchSize {
+		size := g.opts.BatchSize
+		if offset+size > g.opts.Posts {
+			size = g.opts.Posts - offset
+		}
+
+		posts := make([]models.Post, size)
+		trees := make([][]commentNode, size)
+		postTags := make([][]int, size)
+		for i := range posts {
+			posts[i], trees[i] = g.newPost(offset + i)
+			postTags[i] = g.pickTags()
+		}
+
+		err := g.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.CreateInBatches(posts, g.opts.BatchSize).Error; err != nil {
+				return err
+			}
+			if err := g.insertPostTags(tx, posts, postTags); err != nil {
+				return err
+			}
+			return g.insertComments(tx, posts, trees)
+		})
+		if err != nil {
+			return err
+		}
+
+		g.stats.Posts += size
+		log.Printf("已生成文章 %d/%d，评论 %d", g.stats.Posts, g.opts.Posts, g.stats.Comments)
+	}
+	return nil
+}
+
+// newPost 生成一篇文章及其评论树
+func (g *generator) newPost(index int) (models.Post, []commentNode) {
+	authorIdx := g.authorOf()
+	author := g.users[authorIdx]
+
+	// 作者大多使用自己的语言写作
+	lang := g.userLangs[authorIdx]
+	if g.r.Intn(5) == 0 {
+		lang = 1 - lang
+	}
+
+	postTitle := title(g.r, lang)
+	content := markdown(g.r, lang)
+	createdAt := g.randomTime(author.CreatedAt, g.opts.Until)
+
+	slug := utils.Slugify(postTitle)
+	if slug == "" {
+		slug = "post"
+	}
+	slug = fmt.Sprintf("%s-%d-%d", slug, g.opts.Seed, index+1)
+
+	post := models.Post{
+		Title:     postTitle,
+		Content:   content,
+		Summary:   summarize(content, 150),
+		Slug:      slug,
+		Status:    g.postStatus(),
+		IsPublic:  g.r.Intn(10) != 0,
+		UserID:    author.ID,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	if post.Status == models.PostStatusDraft {
+		return post, nil
+	}
+
+	publishedAt := createdAt.Add(time.Duration(g.r.Intn(72)) * time.Hour)
+	if publishedAt.After(g.opts.Until) {
+		publishedAt = g.opts.Until
+	}
+	post.PublishedAt = &publishedAt
+	post.UpdatedAt = publishedAt
+
+	// 阅读数服从对数正态分布（中位数约 250），点赞率 1%~6%
+	post.ViewCount = int(math.Exp(5.5 + 1.2*g.r.NormFloat64()))
+	post.LikeCount = int(float64(post.ViewCount) * (0.01 + 0.05*g.r.Float64()))
+
+	if !post.IsPublic {
+		return post, nil
+	}
+
+	// 评论数与阅读数正相关
+	mean := g.opts.CommentsPerPost * math.Min(float64(post.ViewCount)/250, 5)
+	tree := g.commentTree(poisson(g.r, mean), lang, publishedAt)
+	for _, node := range tree {
+		if node.comment.IsApproved {
+			post.CommentCount++
+		}
+	}
+	return post, tree
+}
+
+// postStatus 文章状态分布：80% 已发布，15% 草稿，5% 已归档
+func (g *generator) postStatus() models.PostStatus {
+	switch n := g.r.Intn(100); {
+	case n < 80:
+		return models.PostStatusPublished
+	case n < 95:
+		return models.PostStatusDraft
+	default:
+		return models.PostStatusArchived
+	}
+}
+
+// pickTags 为文章选择 1~4 个不重复的标签
+func (g *generator) pickTags() []int {
+	if len(g.tags) == 0 {
+		return nil
+	}
+	n := 1 + g.r.Intn(4)
+	seen := make(map[int]bool, n)
+	var picked []int
+	for i := 0; i < n*3 && len(picked) < n; i++ {
+		idx := g.tagOf()
+		if !seen[idx] {
+			seen[idx] = true
+			picked = append(picked, idx)
+		}
+	}
+	return picked
+}
+
+// commentTree 生成嵌套评论树，越新的评论越可能是回复
+func (g *generator) commentTree(n int, lang language, after time.Time) []commentNode {
+	nodes := make([]commentNode, 0, n)
+	at := after
+	for i := 0; i < n; i++ {
+		// 评论时间间隔服从指数分布，平均 6 小时
+		at = at.Add(time.Duration(g.r.ExpFloat64() * float64(6*time.Hour)))
+		if at.After(g.opts.Until) {
+			at = g.opts.Until
+		}
+
+		parent, depth := -1, 0
+		if i > 0 && g.r.Float64() < 0.45 {
+			candidate := g.r.Intn(len(nodes))
+			if nodes[candidate].depth+1 < g.opts.MaxDepth {
+				parent, depth = candidate, nodes[candidate].depth+1
+			}
+		}
+
+		commentLang := lang
+		if g.r.Intn(10) == 0 {
+			commentLang = 1 - lang
+		}
+
+		node := commentNode{
+			comment: models.Comment{
+				Content:    comment(g.r, commentLang),
+				IsApproved: g.r.Intn(20) != 0, // 约 5% 待审核
+				UserID:     g.users[g.r.Intn(len(g.users))].ID,
+				CreatedAt:  at,
+				UpdatedAt:  at,
+			},
+			parent: parent,
+			depth:  depth,
+		}
+		if parent >= 0 && node.comment.IsApproved {
+			nodes[parent].comment.ReplyCount++
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// insertPostTags 写入文章与标签的关联
+func (g *generator) insertPostTags(tx *gorm.DB, posts []models.Post, postTags [][]int) error {
+	var rows []models.PostTag
+	for i, post := range posts {
+		for _, idx := range postTags[i] {
+			rows = append(rows, models.PostTag{PostID: post.ID, TagID: g.tags[idx].ID, CreatedAt: post.CreatedAt})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(rows, 1000).Error
+}
+
+// insertComments 按层级写入评论，保证父评论先于回复写入
+func (g *generator) insertComments(tx *gorm.DB, posts []models.Post, trees [][]commentNode) error {
+	for depth := 0; depth < g.opts.MaxDepth; depth++ {
+		var batch []*models.Comment
+		for i := range trees {
+			for j := range trees[i] {
+				node := &trees[i][j]
+				if node.depth != depth {
+					continue
+				}
+				node.comment.PostID = posts[i].ID
+				if node.parent >= 0 {
+					parent := &trees[i][node.parent].comment
+					parentID := parent.ID
+					node.comment.ParentID = &parentID
+					node.comment.Path = fmt.Sprintf("%s%d/", parent.Path, parent.ID)
+				}
+				batch = append(batch, &node.comment)
+			}
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if err := tx.CreateInBatches(batch, 1000).Error; err != nil {
+			return err
+		}
+		g.stats.Comments += len(batch)
+	}
+	return nil
+}
+
+// updateCounters 根据生成的文章重新计算用户文章数
+func (g *generator) updateCounters() error {
+	return g.db.Exec(`UPDATE users SET post_count = (
+		SELECT COUNT(*) FROM posts WHERE posts.user_id = users.id AND posts.deleted_at IS NULL
+	)`).Error
+}
+
+// span 数据时间跨度
+func (g *generator) span() time.Duration {
+	return g.opts.Until.Sub(g.start)
+}
+
+// randomTime 在 [from, to) 范围内随机选择时间
+func (g *generator) randomTime(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(g.r.Int63n(int64(to.Sub(from)))))
+}
+
+// poisson 泊松分布采样，均值较大时使用正态近似
+func poisson(r *rand.Rand, mean float64) int {
+	if mean <= 0 {
+		return 0
+	}
+	if mean > 30 {
+		n := int(math.Round(mean + math.Sqrt(mean)*r.NormFloat64()))
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	l, k, p := math.Exp(-mean), 0, 1.0
+	for {
+		p *= r.Float64()
+		if p <= l {
+			return k
+		}
+		k++
+	}
+}
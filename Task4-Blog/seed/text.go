@@ -0,0 +1,223 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// 英文语料
+var (
+	enTopics = []string{
+		"Go concurrency", "smart contracts", "Gin middleware", "GORM migrations", "Solidity gas optimization",
+		"ERC-20 tokens", "NFT metadata", "MySQL indexing", "JWT authentication", "rate limiting",
+		"zero-knowledge proofs", "Ethereum wallets", "event sourcing", "Docker deployments", "unit testing",
+		"context cancellation", "channel patterns", "Merkle trees", "layer 2 rollups", "API design",
+	}
+	enTitleTemplates = []string{
+		"A practical guide to %s", "Understanding %s in depth", "%s: lessons learned in production",
+		"Why %s matters more than you think", "Getting started with %s", "Five mistakes I made with %s",
+		"Notes on %s", "How we scaled %s", "%s explained with examples", "Debugging %s the hard way",
+	}
+	enSentences = []string{
+		"This approach keeps the hot path free of allocations.",
+		"In practice the difference only shows up under sustained load.",
+		"We measured a noticeable drop in p99 latency after the change.",
+		"The trade-off is slightly more code to maintain.",
+		"Most tutorials skip this step, but it is where the bugs hide.",
+		"Remember that the network is never reliable.",
+		"A small benchmark is worth a thousand opinions.",
+		"Keep the interface small and let the implementation evolve.",
+		"Every transaction on chain costs gas, so batching pays off quickly.",
+		"The compiler catches many mistakes, but not the logical ones.",
+		"Logging the request ID made tracing incidents much easier.",
+		"We eventually rewrote the module with clearer ownership boundaries.",
+		"Reading the source code answered questions the docs could not.",
+		"Indexes speed up reads but every write pays for them.",
+		"Start simple, measure, and only then optimise.",
+		"Tests gave us the confidence to refactor aggressively.",
+		"Immutability makes reasoning about state far easier.",
+		"The wallet signs the message, and the server only verifies it.",
+	}
+	enHeadings = []string{"Background", "The problem", "Our approach", "Implementation", "Results", "Pitfalls", "Wrapping up"}
+	enComments = []string{
+		"Great write-up, thanks for sharing!", "Does this still apply with the latest version?",
+		"We hit exactly the same issue last month.", "Could you share the benchmark code?",
+		"I disagree with the conclusion, but the analysis is solid.", "This saved me hours of debugging.",
+		"What about the edge case when the context is cancelled?", "Bookmarked for later.",
+		"Nice, but I think the second example has a typo.", "How does this compare to the approach in the docs?",
+	}
+)
+
+// 中文语料
+var (
+	zhTopics = []string{
+		"Go 并发编程", "智能合约开发", "Gin 中间件", "数据库迁移", "Gas 优化",
+		"ERC-20 代币", "NFT 元数据", "MySQL 索引", "JWT 认证", "接口限流",
+		"零知识证明", "以太坊钱包", "分布式事务", "容器化部署", "单元测试",
+		"上下文取消", "通道模式", "默克尔树", "二层扩容", "接口设计",
+	}
+	zhTitleTemplates = []string{
+		"%s实践指南", "深入理解%s", "%s踩坑记录", "从零开始学习%s", "关于%s的几点思考",
+		"%s的五个常见误区", "%s学习笔记", "我们是如何优化%s的", "图解%s", "一次%s的排查经历",
+	}
+	zhSentences = []string{
+		"这种做法可以避免在热点路径上分配内存。",
+		"只有在持续高负载下才能看出明显差异。",
+		"改动上线后，p99 延迟明显下降。",
+		"代价是需要维护更多的代码。",
+		"大部分教程都会跳过这一步，但问题往往就出在这里。",
+		"永远不要假设网络是可靠的。",
+		"一次小小的基准测试胜过千言万语。",
+		"保持接口精简，让实现逐步演进。",
+		"链上的每笔交易都需要消耗 Gas，批量处理很快就能回本。",
+		"编译器能发现很多错误，但发现不了逻辑问题。",
+		"在日志中记录请求 ID 之后，排查问题变得容易多了。",
+		"最终我们按照更清晰的职责边界重写了这个模块。",
+		"阅读源码解答了文档没有说明的问题。",
+		"索引加快了读取，但每次写入都要为此付出代价。",
+		"先从简单的方案开始，测量之后再做优化。",
+		"完善的测试让我们敢于大胆重构。",
+		"不可变数据让状态推理变得简单得多。",
+		"钱包负责签名，服务端只需要验证签名。",
+	}
+	zhHeadings = []string{"背景", "问题描述", "解决思路", "具体实现", "效果", "注意事项", "总结"}
+	zhComments = []string{
+		"写得很好，感谢分享！", "新版本还适用吗？", "上个月我们也遇到了同样的问题。",
+		"能分享一下测试代码吗？", "结论我不太认同，不过分析很到位。", "帮我省了好几个小时的排查时间。",
+		"如果上下文被取消了会怎样？", "收藏了，回头细看。", "第二个示例好像有个笔误。", "和官方文档的做法相比有什么优势？",
+	}
+)
+
+// 其它语料
+var (
+	firstNames = []string{
+		"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy",
+		"mallory", "niaj", "olivia", "peggy", "rupert", "sybil", "trent", "victor", "walter", "wendy",
+		"xiaoming", "lihua", "zhangwei", "wangfang", "liuyang", "chenjing", "yangfan", "zhaolei", "huangli", "zhoujie",
+	}
+	lastNames = []string{"chen", "wang", "li", "zhang", "liu", "smith", "johnson", "lee", "brown", "garcia", "kim", "nguyen"}
+	enBios    = []string{
+		"Backend engineer who loves Go.", "Solidity developer and security enthusiast.", "Writing about distributed systems.",
+		"Full-stack developer, coffee addict.", "Exploring web3 one block at a time.", "",
+	}
+	zhBios = []string{
+		"Go 后端开发，热爱开源。", "智能合约工程师，关注链上安全。", "分布式系统爱好者。",
+		"全栈开发，喜欢折腾。", "Web3 探索中。", "",
+	}
+	tagNames = []string{
+		"Go", "Solidity", "Ethereum", "Web3", "区块链", "数据库", "MySQL", "Gin", "GORM", "安全",
+		"性能优化", "DeFi", "NFT", "微服务", "Docker", "Kubernetes", "测试", "架构", "算法", "Rust",
+		"Redis", "读书笔记", "随笔", "面试", "Linux", "网络", "密码学", "前端", "工具", "开源",
+	}
+	tagColors    = []string{"#00ADD8", "#363636", "#627EEA", "#F16822", "#F7931A", "#4479A1", "#2496ED", "#DEA584", "#DC382D", "#6DB33F"}
+	codeSnippets = []struct{ lang, code string }{
+		{"go", "func worker(ctx context.Context, jobs <-chan Job) {\n\tfor {\n\t\tselect {\n\t\tcase <-ctx.Done():\n\t\t\treturn\n\t\tcase job := <-jobs:\n\t\t\tjob.Run()\n\t\t}\n\t}\n}"},
+		{"go", "r := gin.Default()\nr.Use(middleware.Logger())\nr.GET(\"/ping\", func(c *gin.Context) {\n\tc.JSON(200, gin.H{\"message\": \"pong\"})\n})"},
+		{"solidity", "function transfer(address to, uint256 amount) external returns (bool) {\n    require(balanceOf[msg.sender] >= amount, \"insufficient balance\");\n    balanceOf[msg.sender] -= amount;\n    balanceOf[to] += amount;\n    emit Transfer(msg.sender, to, amount);\n    return true;\n}"},
+		{"sql", "SELECT p.id, p.title, COUNT(c.id) AS comments\nFROM posts p\nLEFT JOIN comments c ON c.post_id = p.id\nGROUP BY p.id\nORDER BY comments DESC\nLIMIT 10;"},
+		{"bash", "go build -o blog-system\n./blog-system migrate up\n./blog-system serve"},
+	}
+)
+
+// language 内容语言
+type language int
+
+const (
+	langEnglish language = iota
+	langChinese
+)
+
+// pick 随机选择一个元素
+func pick(r *rand.Rand, items []string) string {
+	return items[r.Intn(len(items))]
+}
+
+// sentences 生成 n 个句子组成的段落
+func sentences(r *rand.Rand, lang language, n int) string {
+	pool, sep := enSentences, " "
+	if lang == langChinese {
+		pool, sep = zhSentences, ""
+	}
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = pick(r, pool)
+	}
+	return strings.Join(parts, sep)
+}
+
+// title 生成文章标题，返回标题和主题
+func title(r *rand.Rand, lang language) string {
+	if lang == langChinese {
+		return fmt.Sprintf(pick(r, zhTitleTemplates), pick(r, zhTopics))
+	}
+	t := fmt.Sprintf(pick(r, enTitleTemplates), pick(r, enTopics))
+	return strings.ToUpper(t[:1]) + t[1:]
+}
+
+// markdown 生成 Markdown 格式的文章正文
+func markdown(r *rand.Rand, lang language) string {
+	headings := enHeadings
+	if lang == langChinese {
+		headings = zhHeadings
+	}
+
+	var b strings.Builder
+	b.WriteString(sentences(r, lang, 2+r.Intn(3)))
+	b.WriteString("\n\n")
+
+	sections := 2 + r.Intn(4)
+	for i := 0; i < sections; i++ {
+		fmt.Fprintf(&b, "## %s\n\n", headings[(i+r.Intn(2))%len(headings)])
+
+		for p := 0; p < 1+r.Intn(3); p++ {
+			b.WriteString(sentences(r, lang, 2+r.Intn(4)))
+			b.WriteString("\n\n")
+		}
+
+		switch r.Intn(5) {
+		case 0:
+			for j := 0; j < 3+r.Intn(3); j++ {
+				fmt.Fprintf(&b, "- %s\n", sentences(r, lang, 1))
+			}
+			b.WriteString("\n")
+		case 1:
+			snippet := codeSnippets[r.Intn(len(codeSnippets))]
+			fmt.Fprintf(&b, "```%s\n%s\n```\n\n", snippet.lang, snippet.code)
+		case 2:
+			fmt.Fprintf(&b, "> %s\n\n", sentences(r, lang, 1))
+		case 3:
+			fmt.Fprintf(&b, "**%s** %s\n\n", sentences(r, lang, 1), sentences(r, lang, 1))
+		}
+	}
+
+	if r.Intn(3) == 0 {
+		b.WriteString("[Go](https://go.dev) · [Ethereum](https://ethereum.org)\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// comment 生成评论内容
+func comment(r *rand.Rand, lang language) string {
+	if lang == langChinese {
+		text := pick(r, zhComments)
+		if r.Intn(3) == 0 {
+			text += sentences(r, lang, 1)
+		}
+		return text
+	}
+	text := pick(r, enComments)
+	if r.Intn(3) == 0 {
+		text += " " + sentences(r, lang, 1)
+	}
+	return text
+}
+
+// summarize 按字符（而非字节）截取摘要
+func summarize(content string, limit int) string {
+	runes := []rune(content)
+	if len(runes) <= limit {
+		return content
+	}
+	return string(runes[:limit]) + "..."
+}
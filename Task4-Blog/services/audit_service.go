@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/models"
+
+	"gorm.io/gorm"
+)
+
+// Actor 发起操作的用户和请求，由 HTTP 中间件、gRPC 拦截器或命令行写入 context
+type Actor struct {
+	UserID    uint   // 未登录或命令行操作时为 0
+	Username  string // 命令行操作时为 cli
+	IP        string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor 将操作者写入 context
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 取出 context 中的操作者，不存在时返回零值
+func ActorFrom(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// AuditLogFilter 审计日志查询条件
+type AuditLogFilter struct {
+	ActorID    uint
+	Action     models.AuditAction
+	TargetType string
+	TargetID   uint
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditService 审计日志服务
+type AuditService struct {
+	db *gorm.DB
+}
+
+// NewAuditService 创建审计日志服务实例
+func NewAuditService() *AuditService {
+	return &AuditService{
+		db: database.GetDB(),
+	}
+}
+
+// Record 在 tx 中记录一次操作，操作者取自 context。before/after 为操作前后的对象快照，可以为 nil。
+// 与被记录的修改在同一个事务中调用，写入失败时返回错误，修改随事务一起回滚
+func (as *AuditService) Record(ctx context.Context, tx *gorm.DB, action models.AuditAction, targetType string, targetID uint, before, after interface{}) error {
+	actor := ActorFrom(ctx)
+	entry := &models.AuditLog{
+		ActorName:  actor.Username,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+	}
+	if actor.UserID != 0 {
+		entry.ActorID = &actor.UserID
+	}
+
+	var err error
+	if entry.Before, err = snapshot(before); err == nil {
+		entry.After, err = snapshot(after)
+	}
+	if err == nil {
+		err = tx.Create(entry).Error
+	}
+	if err != nil {
+		return fmt.Errorf("记录审计日志失败 (%s %s:%d): %v", action, targetType, targetID, err)
+	}
+	return nil
+}
+
+// snapshot 将快照序列化为 JSON
+func snapshot(v interface{}) (models.AuditSnapshot, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ListLogs 分页查询审计日志，最新的在前
+func (as *AuditService) ListLogs(filter AuditLogFilter, page, pageSize int) ([]models.AuditLog, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	query := as.db.Model(&models.AuditLog{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// CleanupLogs 删除超过保留期的审计日志，audit.retention_days 为 0 时永久保留
+func (as *AuditService) CleanupLogs(ctx context.Context) error {
+	days := config.GetConfig().Audit.RetentionDays
+	if days <= 0 {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	return as.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.AuditLog{}).Error
+}
+
+// postSnapshot 文章的审计快照
+func postSnapshot(post *models.Post) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":   post.UserID,
+		"title":     post.Title,
+		"slug":      post.Slug,
+		"summary":   post.Summary,
+		"content":   post.Content,
+		"status":    post.Status,
+		"is_public": post.IsPublic,
+	}
+}
+
+// commentSnapshot 评论的审计快照
+func commentSnapshot(comment *models.Comment) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":     comment.UserID,
+		"post_id":     comment.PostID,
+		"parent_id":   comment.ParentID,
+		"content":     comment.Content,
+		"is_approved": comment.IsApproved,
+	}
+}
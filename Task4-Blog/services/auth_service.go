@@ -4,7 +4,13 @@ import (
 	"blog-system/config"
 	"blog-system/database"
 	"blog-system/models"
+	"blog-system/siwe"
 	"blog-system/utils"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -13,16 +19,26 @@ import (
 
 // AuthService 认证服务
 type AuthService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	attempts *LoginAttemptService
+	keys     *SigningKeyService
 }
 
 // NewAuthService 创建认证服务实例
 func NewAuthService() *AuthService {
 	return &AuthService{
-		db: database.GetDB(),
+		db:       database.GetDB(),
+		attempts: NewLoginAttemptService(),
+		keys:     NewSigningKeyService(),
 	}
 }
 
+// dummyPasswordHash 用户不存在时用于比对的哈希，使响应时间与密码错误时一致
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := utils.HashPassword("dummy-password")
+	return hash
+})
+
 // Register 用户注册
 func (as *AuthService) Register(username, email, password, bio string) (*models.User, error) {
 	// 加密密码
@@ -49,60 +65,89 @@ func (as *AuthService) Register(username, email, password, bio string) (*models.
 }
 
 // Login 用户登录
-func (as *AuthService) Login(username, password string) (*models.User, error) {
+// 每次尝试都会记录；账号或 IP 失败次数过多时返回 *LoginBlockedError，不再验证密码；
+// 用户不存在、已停用和密码错误都返回 ErrInvalidCredentials
+func (as *AuthService) Login(username, password, ip string) (*models.User, error) {
 	var user models.User
-	
+	now := time.Now()
+
 	// 根据用户名或邮箱查找用户
+	found := true
 	if err := as.db.Where("username = ? OR email = ?", username, username).First(&user).Error; err != nil {
-		return nil, err
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		found = false
 	}
 
-	// 检查用户是否激活
-	if !user.IsActive {
-		return nil, gorm.ErrRecordNotFound
+	attempt := &models.LoginAttempt{Identifier: strings.ToLower(username), IP: ip}
+	if found {
+		attempt.UserID = &user.ID
 	}
 
-	// 验证密码
-	if !utils.CheckPasswordHash(password, user.Password) {
-		return nil, gorm.ErrRecordNotFound
+	// 检查账号和 IP 是否需要等待
+	wait, reason, err := as.attempts.Check(attempt.UserID, attempt.Identifier, ip, now)
+	if err != nil {
+		return nil, err
 	}
+	if wait > 0 {
+		attempt.Result, attempt.Reason = models.LoginResultBlocked, reason
+		as.recordAttempt(attempt)
+		return nil, &LoginBlockedError{RetryAfter: wait}
+	}
+
+	// 验证密码，用户不存在时同样比对一次哈希
+	switch {
+	case !found:
+		utils.CheckPasswordHash(password, dummyPasswordHash())
+		attempt.Result, attempt.Reason = models.LoginResultFailed, models.LoginReasonUnknownUser
+	case !utils.CheckPasswordHash(password, user.Password):
+		attempt.Result, attempt.Reason = models.LoginResultFailed, models.LoginReasonWrongPassword
+	case !user.IsActive:
+		attempt.Result, attempt.Reason = models.LoginResultFailed, models.LoginReasonInactive
+	default:
+		attempt.Result = models.LoginResultSuccess
+	}
+	as.recordAttempt(attempt)
 
+	if attempt.Result != models.LoginResultSuccess {
+		return nil, ErrInvalidCredentials
+	}
 	return &user, nil
 }
 
-// GenerateToken 生成 JWT token
-func (as *AuthService) GenerateToken(user *models.User) (string, error) {
+// recordAttempt 保存登录记录，失败时只记录日志，不影响登录
+func (as *AuthService) recordAttempt(attempt *models.LoginAttempt) {
+	if err := as.attempts.Record(attempt); err != nil {
+		log.Printf("保存登录记录失败: %v", err)
+	}
+}
+
+// GenerateToken 生成 JWT token。mfa 表示本次登录是否刚刚通过了两步验证（验证码或恢复码），
+// 不能根据账号是否开启了两步验证推断
+func (as *AuthService) GenerateToken(user *models.User, mfa bool) (string, error) {
 	cfg := config.GetConfig()
-	
+
 	// 创建 token 声明
 	claims := jwt.MapClaims{
 		"user_id":  user.ID,
 		"username": user.Username,
 		"email":    user.Email,
 		"role":     user.Role,
+		"mfa":      mfa,
 		"exp":      time.Now().Add(time.Hour * time.Duration(cfg.JWT.Expire)).Unix(),
 		"iat":      time.Now().Unix(),
 		"iss":      cfg.JWT.Issuer,
 	}
 
-	// 创建 token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// 签名 token
-	return token.SignedString([]byte(cfg.JWT.Secret))
+	// 按 jwt.algorithm 签名 token
+	return as.keys.Sign(claims)
 }
 
 // ValidateToken 验证 JWT token
 func (as *AuthService) ValidateToken(tokenString string) (*jwt.Token, error) {
-	cfg := config.GetConfig()
-	
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// 验证签名方法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
-		return []byte(cfg.JWT.Secret), nil
-	})
+	// 按 kid 查找验证密钥并检查签名方法
+	token, err := jwt.Parse(tokenString, as.keys.Keyfunc)
 
 	if err != nil {
 		return nil, err
@@ -111,23 +156,284 @@ func (as *AuthService) ValidateToken(tokenString string) (*jwt.Token, error) {
 	return token, nil
 }
 
+// JWKS 验证本服务签发的 token 使用的公钥，jwt.algorithm 为 HS256 时为空
+func (as *AuthService) JWKS() (*JWKS, error) {
+	return as.keys.JWKS()
+}
+
+// TokenUser token 中携带的用户信息
+type TokenUser struct {
+	ID        uint
+	Username  string
+	Email     string
+	Role      string
+	TwoFactor bool     // 登录时是否通过了两步验证
+	Scopes    []string // 个人访问令牌的权限范围，登录 token 为 nil（不限范围）
+}
+
+// HasRole 检查用户是否可以行使角色的权限。
+// 角色要求两步验证（two_factor.required_roles）而登录时未通过两步验证的，不能行使该角色的权限；
+// 个人访问令牌不能行使任何角色的权限
+func (tu *TokenUser) HasRole(role string) bool {
+	if tu.IsAccessToken() {
+		return false
+	}
+	return tu.Role == role && (tu.TwoFactor || !TwoFactorRequired(role))
+}
+
+// IsAccessToken 是否通过个人访问令牌认证
+func (tu *TokenUser) IsAccessToken() bool {
+	return tu.Scopes != nil
+}
+
+// HasScope 检查是否拥有权限范围，登录 token 拥有全部权限
+func (tu *TokenUser) HasScope(scope string) bool {
+	if !tu.IsAccessToken() {
+		return true
+	}
+	for _, s := range tu.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticate 验证 token 并取出用户信息，HTTP 中间件和 gRPC 拦截器共用
+func (as *AuthService) Authenticate(tokenString string) (*TokenUser, error) {
+	token, err := as.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	// 两步验证的挑战 token 不能用于访问接口
+	if _, isChallenge := claims["typ"]; isChallenge {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	userID, ok1 := claims["user_id"].(float64)
+	username, ok2 := claims["username"].(string)
+	email, ok3 := claims["email"].(string)
+	role, ok4 := claims["role"].(string)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	twoFactor, _ := claims["mfa"].(bool)
+
+	return &TokenUser{ID: uint(userID), Username: username, Email: email, Role: role, TwoFactor: twoFactor}, nil
+}
+
 // GetUserFromToken 从 token 中获取用户信息
 func (as *AuthService) GetUserFromToken(tokenString string) (*models.User, error) {
+	tokenUser, err := as.Authenticate(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := as.db.First(&user, tokenUser.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// challengeType 两步验证挑战 token 的 typ 声明
+const challengeType = "2fa"
+
+// GenerateChallengeToken 生成两步验证的挑战 token：密码（或钱包）验证通过后签发，
+// 只能用于提交验证码，有效期为 two_factor.challenge_ttl 秒
+func (as *AuthService) GenerateChallengeToken(user *models.User) (string, time.Time, error) {
+	cfg := config.GetConfig()
+	expiresAt := time.Now().Add(time.Duration(cfg.TwoFactor.ChallengeTTL) * time.Second)
+
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"typ":     challengeType,
+		"exp":     expiresAt.Unix(),
+		"iat":     time.Now().Unix(),
+		"iss":     cfg.JWT.Issuer,
+	}
+	token, err := as.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// ParseChallengeToken 验证挑战 token，返回用户ID
+func (as *AuthService) ParseChallengeToken(tokenString string) (uint, error) {
 	token, err := as.ValidateToken(tokenString)
 	if err != nil {
+		return 0, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["typ"] != challengeType {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, jwt.ErrTokenInvalidClaims
+	}
+	return uint(userID), nil
+}
+
+// 钱包登录错误
+var (
+	ErrSIWEDisabled     = errors.New("钱包登录未开启")
+	ErrSIWENonce        = siwe.ErrNonce
+	ErrWalletLinked     = errors.New("该钱包已绑定其他账号")
+	ErrAccountHasWallet = errors.New("当前账号已绑定其他钱包")
+)
+
+// CreateNonce 生成钱包登录使用的一次性 nonce
+func (as *AuthService) CreateNonce() (*models.AuthNonce, error) {
+	cfg := config.GetConfig().SIWE
+	if !cfg.Enabled {
+		return nil, ErrSIWEDisabled
+	}
+
+	value, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	nonce := &models.AuthNonce{
+		Nonce:     value,
+		ExpiresAt: time.Now().Add(time.Duration(cfg.NonceTTL) * time.Second),
+	}
+	if err := as.db.Create(nonce).Error; err != nil {
 		return nil, err
 	}
+	return nonce, nil
+}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := uint(claims["user_id"].(float64))
-		
-		var user models.User
-		if err := as.db.First(&user, userID).Error; err != nil {
-			return nil, err
+// SignInWithEthereum 验证 EIP-4361 消息和 personal_sign 签名，返回钱包绑定的用户。
+// 钱包尚未绑定时，已登录（currentUserID 非 0）则绑定到当前用户，否则创建新用户，created 为 true
+func (as *AuthService) SignInWithEthereum(message, signature string, currentUserID uint) (user *models.User, created bool, err error) {
+	cfg := config.GetConfig().SIWE
+	if !cfg.Enabled {
+		return nil, false, ErrSIWEDisabled
+	}
+
+	msg, err := siwe.ParseMessage(message)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := msg.Verify(signature, siwe.VerifyOptions{
+		Domain:   cfg.Domain,
+		ChainIDs: cfg.ChainIDs,
+		Nonces:   nonceStore{db: as.db},
+	}); err != nil {
+		return nil, false, err
+	}
+
+	address := siwe.ChecksumAddress(msg.Address)
+	user, err = as.findByWallet(address)
+	switch {
+	case err == nil:
+		if currentUserID != 0 && user.ID != currentUserID {
+			return nil, false, ErrWalletLinked
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, false, err
+	case currentUserID != 0:
+		user, err = as.linkWallet(currentUserID, address)
+	default:
+		user, created, err = as.createWalletUser(address)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 与密码登录一致，停用的账号视为不存在
+	if !user.IsActive {
+		return nil, false, gorm.ErrRecordNotFound
+	}
+	return user, created, nil
+}
+
+// nonceStore 数据库中的 nonce，删除成功说明 nonce 由本站签发、未过期且未被使用
+type nonceStore struct {
+	db *gorm.DB
+}
+
+// Consume 实现 siwe.NonceStore
+func (s nonceStore) Consume(nonce string) (bool, error) {
+	result := s.db.Where("nonce = ? AND expires_at > ?", nonce, time.Now()).Delete(&models.AuthNonce{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CleanupNonces 删除过期的 nonce
+func (as *AuthService) CleanupNonces(ctx context.Context) error {
+	return as.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&models.AuthNonce{}).Error
+}
+
+// findByWallet 根据钱包地址查找用户
+func (as *AuthService) findByWallet(address string) (*models.User, error) {
+	var user models.User
+	if err := as.db.Where("wallet_address = ?", address).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// linkWallet 将钱包绑定到已登录的用户，每个用户只能绑定一个钱包
+func (as *AuthService) linkWallet(userID uint, address string) (*models.User, error) {
+	result := as.db.Model(&models.User{}).
+		Where("id = ? AND wallet_address IS NULL", userID).
+		Update("wallet_address", address)
+	if result.Error != nil {
+		// 并发绑定时唯一索引冲突
+		if _, err := as.findByWallet(address); err == nil {
+			return nil, ErrWalletLinked
 		}
+		return nil, result.Error
+	}
+
+	var user models.User
+	if err := as.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if user.WalletAddress == nil || *user.WalletAddress != address {
+		return nil, ErrAccountHasWallet
+	}
+	return &user, nil
+}
 
-		return &user, nil
+// createWalletUser 为首次登录的钱包创建用户，用户名为小写地址，邮箱为不可投递的占位地址，密码随机
+func (as *AuthService) createWalletUser(address string) (*models.User, bool, error) {
+	password, err := utils.GenerateRandomPassword(32)
+	if err != nil {
+		return nil, false, err
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return nil, jwt.ErrInvalidKey
-}
\ No newline at end of file
+	lower := strings.ToLower(address)
+	user := &models.User{
+		Username:      lower,
+		Email:         lower + "@wallet.invalid",
+		Password:      hashedPassword,
+		Role:          "user",
+		IsActive:      true,
+		WalletAddress: &address,
+	}
+	if err := as.db.Create(user).Error; err != nil {
+		// 同一钱包并发首次登录时，另一个请求已创建用户
+		if existing, findErr := as.findByWallet(address); findErr == nil {
+			return existing, false, nil
+		}
+		return nil, false, err
+	}
+	return user, true, nil
+}
@@ -1,52 +1,135 @@
 package services
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"blog-system/config"
 	"blog-system/database"
 	"blog-system/models"
 
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrCommentParentNotFound 回复的父评论不存在或未审核通过
+	ErrCommentParentNotFound = errors.New("父评论不存在")
+	// ErrCommentParentMismatch 回复的父评论属于其他文章
+	ErrCommentParentMismatch = errors.New("父评论不属于该文章")
+	// ErrCommentSort 不支持的排序方式
+	ErrCommentSort = errors.New("排序方式只能是 oldest、newest 或 top")
+	// ErrCommentCursor 回复游标无效或与排序方式不符
+	ErrCommentCursor = errors.New("游标格式不正确")
+	// ErrCommentEditWindow 评论发表已超过 comment.edit_window 分钟
+	ErrCommentEditWindow = errors.New("评论发表时间过久，已不能修改")
+)
+
+// commentRepliesBatch 加载回复时每条 UNION ALL 查询包含的父评论数
+const commentRepliesBatch = 100
+
+// CommentThreadOptions 评论树的展开方式
+type CommentThreadOptions struct {
+	Sort      models.CommentSort // 顶级评论的排序，默认 newest
+	ReplySort models.CommentSort // 回复的排序，默认 oldest
+	Depth     *int               // 展开的回复层数，为空或超过 comment.max_depth 时使用 comment.max_depth
+}
+
+// CommentReplyPage 一页回复
+type CommentReplyPage struct {
+	Replies    []models.CommentResponse `json:"replies"`
+	NextCursor string                   `json:"next_cursor,omitempty"` // 为空表示没有更多回复
+	ReplyCount int                      `json:"reply_count"`           // 父评论审核通过的直接回复数
+}
+
+// CommentHistory 评论及其修改记录
+type CommentHistory struct {
+	Comment   models.CommentResponse   `json:"comment"`
+	Revisions []models.CommentRevision `json:"revisions"` // 每次修改前的版本，最近修改的在前
+}
+
 // CommentService 评论服务
 type CommentService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	webhooks *WebhookService
+	audit    *AuditService
 }
 
 // NewCommentService 创建评论服务实例
 func NewCommentService() *CommentService {
 	return &CommentService{
-		db: database.GetDB(),
+		db:       database.GetDB(),
+		webhooks: NewWebhookService(),
+		audit:    NewAuditService(),
 	}
 }
 
-// CreateComment 创建评论
-func (cs *CommentService) CreateComment(userID, postID uint, content string, parentID *uint) (*models.Comment, error) {
+// CreateComment 创建评论，操作记入审计日志
+func (cs *CommentService) CreateComment(ctx context.Context, userID, postID uint, content string, parentID *uint) (*models.Comment, error) {
 	// 检查文章是否存在
 	var post models.Post
 	if err := cs.db.First(&post, postID).Error; err != nil {
 		return nil, err
 	}
 
+	// 回复只能针对同一篇文章下审核通过的评论
+	if parentID != nil {
+		var parent models.Comment
+		if err := cs.db.Select("id", "post_id").Where("is_approved = ?", true).First(&parent, *parentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrCommentParentNotFound
+			}
+			return nil, err
+		}
+		if parent.PostID != postID {
+			return nil, ErrCommentParentMismatch
+		}
+	}
+
 	comment := &models.Comment{
 		Content:    content,
 		UserID:     userID,
 		PostID:     postID,
 		ParentID:   parentID,
-		IsApproved: true, // 默认审核通过
+		IsApproved: moderateComment(content), // 不含屏蔽词时直接审核通过
 	}
 
-	if err := cs.db.Create(comment).Error; err != nil {
+	if err := cs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+
+		// 预加载关联数据
+		if err := tx.Preload("User").Preload("Post").First(comment, comment.ID).Error; err != nil {
+			return err
+		}
+
+		return cs.audit.Record(ctx, tx, models.AuditCommentCreate, models.AuditTargetComment, comment.ID, nil, commentSnapshot(comment))
+	}); err != nil {
 		return nil, err
 	}
 
-	// 预加载关联数据
-	if err := cs.db.Preload("User").Preload("Post").First(comment, comment.ID).Error; err != nil {
-		return nil, err
+	if comment.IsApproved {
+		cs.publishApproved(comment)
 	}
 
 	return comment, nil
 }
 
+// publishApproved 通知订阅了文章新评论的客户端和 Webhook 端点。
+// 评论发表时、修改后或经管理员审核变为审核通过时调用，评论需要预加载 User 和 Post
+func (cs *CommentService) publishApproved(comment *models.Comment) {
+	feed.publish(*comment)
+	response := comment.ToResponse()
+	cs.webhooks.Emit(models.WebhookEventCommentCreated, comment.Post.UserID, WebhookData{Comment: &response})
+}
+
 // GetCommentByID 根据ID获取评论
 func (cs *CommentService) GetCommentByID(commentID uint) (*models.Comment, error) {
 	var comment models.Comment
@@ -57,11 +140,21 @@ func (cs *CommentService) GetCommentByID(commentID uint) (*models.Comment, error
 	return &comment, nil
 }
 
-// GetPostComments 获取文章的评论列表
-func (cs *CommentService) GetPostComments(postID uint, page, pageSize int) ([]models.CommentResponse, int64, error) {
+// GetPostComments 获取文章的评论树：顶级评论分页，回复按 opts 逐层展开。
+// 每条评论最多内嵌 comment.inline_replies 条回复，其余回复和超出层数的回复通过 RepliesCursor 继续加载
+func (cs *CommentService) GetPostComments(postID uint, page, pageSize int, opts CommentThreadOptions) ([]models.CommentResponse, int64, error) {
 	var comments []models.Comment
 	var total int64
 
+	sortBy, err := commentSortOr(opts.Sort, models.CommentSortNewest)
+	if err != nil {
+		return nil, 0, err
+	}
+	replySort, err := commentSortOr(opts.ReplySort, models.CommentSortOldest)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// 计算偏移量
 	offset := (page - 1) * pageSize
 
@@ -72,10 +165,10 @@ func (cs *CommentService) GetPostComments(postID uint, page, pageSize int) ([]mo
 		return nil, 0, err
 	}
 
-	// 获取评论列表（只获取顶级评论，预加载回复）
-	if err := cs.db.Preload("User").Preload("Replies").Preload("Replies.User").
+	// 获取评论列表（只获取顶级评论，回复逐层加载）
+	if err := cs.db.Preload("User").
 		Where("post_id = ? AND parent_id IS NULL AND is_approved = ?", postID, true).
-		Order("created_at DESC").
+		Order(commentOrder(sortBy)).
 		Offset(offset).
 		Limit(pageSize).
 		Find(&comments).Error; err != nil {
@@ -83,7 +176,38 @@ func (cs *CommentService) GetPostComments(postID uint, page, pageSize int) ([]mo
 	}
 
 	// 转换为响应格式
-	var commentResponses []models.CommentResponse
+	commentResponses := make([]models.CommentResponse, len(comments))
+	for i := range comments {
+		commentResponses[i] = comments[i].ToResponse()
+	}
+
+	if err := cs.expandReplies(commentResponses, replySort, commentDepth(opts.Depth)); err != nil {
+		return nil, 0, err
+	}
+	return commentResponses, total, nil
+}
+
+// GetUserComments 获取用户发表的评论列表，包含未审核通过的评论
+func (cs *CommentService) GetUserComments(userID uint, page, pageSize int) ([]models.CommentResponse, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	offset := (page - 1) * pageSize
+
+	if err := cs.db.Model(&models.Comment{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := cs.db.Preload("User").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	commentResponses := make([]models.CommentResponse, 0, len(comments))
 	for _, comment := range comments {
 		commentResponses = append(commentResponses, comment.ToResponse())
 	}
@@ -91,9 +215,84 @@ func (cs *CommentService) GetPostComments(postID uint, page, pageSize int) ([]mo
 	return commentResponses, total, nil
 }
 
-// DeleteComment 删除评论
-func (cs *CommentService) DeleteComment(commentID uint) error {
-	return cs.db.Delete(&models.Comment{}, commentID).Error
+// UpdateComment 修改评论内容，修改前的版本保存到修改记录中，操作记入审计日志。
+// 只能在发表后 comment.edit_window 分钟内修改；修改后的内容重新审核，审核状态随之更新，
+// 但管理员驳回的评论仍然待审核。修改后变为审核通过时与发表时一样通知订阅者和 Webhook
+func (cs *CommentService) UpdateComment(ctx context.Context, commentID uint, content string) (*models.Comment, error) {
+	var comment models.Comment
+	if err := cs.db.First(&comment, commentID).Error; err != nil {
+		return nil, err
+	}
+
+	if window := config.GetConfig().Comment.EditWindow; window > 0 &&
+		time.Since(comment.CreatedAt) > time.Duration(window)*time.Minute {
+		return nil, ErrCommentEditWindow
+	}
+
+	wasApproved := comment.IsApproved
+	if content != comment.Content {
+		before := commentSnapshot(&comment)
+		// 管理员驳回的评论只能由管理员重新通过
+		approved := moderateComment(content) && !comment.RejectedByModerator()
+		if err := cs.db.Transaction(func(tx *gorm.DB) error {
+			if err := comment.Edit(tx, content, approved); err != nil {
+				return err
+			}
+			return cs.audit.Record(ctx, tx, models.AuditCommentUpdate, models.AuditTargetComment, comment.ID, before, commentSnapshot(&comment))
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	// 预加载关联数据
+	if err := cs.db.Preload("User").Preload("Post").First(&comment, comment.ID).Error; err != nil {
+		return nil, err
+	}
+
+	if !wasApproved && comment.IsApproved {
+		cs.publishApproved(&comment)
+	}
+	return &comment, nil
+}
+
+// GetCommentHistory 获取评论的修改记录（管理员功能），包括回收站中的评论
+func (cs *CommentService) GetCommentHistory(commentID uint) (*CommentHistory, error) {
+	var comment models.Comment
+	if err := cs.db.Unscoped().Preload("User").First(&comment, commentID).Error; err != nil {
+		return nil, err
+	}
+
+	var revisions []models.CommentRevision
+	if err := cs.db.Where("comment_id = ?", commentID).Order("id DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return &CommentHistory{Comment: comment.ToResponse(), Revisions: revisions}, nil
+}
+
+// moderateComment 审核评论内容，包含 comment.blocked_words 中的词时返回 false（待审核）
+func moderateComment(content string) bool {
+	lower := strings.ToLower(content)
+	for _, word := range config.GetConfig().Comment.BlockedWords {
+		if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteComment 删除评论（移入回收站），操作记入审计日志
+func (cs *CommentService) DeleteComment(ctx context.Context, commentID uint) error {
+	var comment models.Comment
+	if err := cs.db.First(&comment, commentID).Error; err != nil {
+		return err
+	}
+
+	return cs.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&comment).Error; err != nil {
+			return err
+		}
+		return cs.audit.Record(ctx, tx, models.AuditCommentDelete, models.AuditTargetComment, comment.ID, commentSnapshot(&comment), nil)
+	})
 }
 
 // IsCommentOwner 检查用户是否是评论的作者
@@ -105,30 +304,376 @@ func (cs *CommentService) IsCommentOwner(commentID, userID uint) bool {
 	return comment.UserID == userID
 }
 
-// GetCommentReplies 获取评论的回复
-func (cs *CommentService) GetCommentReplies(commentID uint) ([]models.Comment, error) {
-	var replies []models.Comment
-	if err := cs.db.Preload("User").
-		Where("parent_id = ? AND is_approved = ?", commentID, true).
-		Order("created_at ASC").
-		Find(&replies).Error; err != nil {
+// GetCommentReplies 分页获取评论的回复，用于"加载更多"。
+// cursor 为评论树中的 RepliesCursor 或上一页的 NextCursor，为空时从第一条开始；cursor 中记录了排序方式，
+// sortBy 为空时沿用，不一致时返回 ErrCommentCursor。depth 为展开的回复层数（含本页回复），最少 1 层
+func (cs *CommentService) GetCommentReplies(commentID uint, sortBy models.CommentSort, cursor string, limit int, depth *int) (*CommentReplyPage, error) {
+	var parent models.Comment
+	if err := cs.db.Select("id", "reply_count").Where("is_approved = ?", true).First(&parent, commentID).Error; err != nil {
+		return nil, err
+	}
+
+	var after *replyCursor
+	if cursor != "" {
+		c, err := decodeReplyCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if sortBy != "" && sortBy != c.Sort {
+			return nil, ErrCommentCursor
+		}
+		sortBy, after = c.Sort, c
+	}
+	sortBy, err := commentSortOr(sortBy, models.CommentSortOldest)
+	if err != nil {
 		return nil, err
 	}
-	return replies, nil
+
+	replies, err := cs.fetchReplies([]uint{commentID}, sortBy, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	rows := replies[commentID]
+
+	result := &CommentReplyPage{ReplyCount: parent.ReplyCount}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		result.NextCursor = newReplyCursor(sortBy, &rows[limit-1]).encode()
+	}
+	result.Replies = make([]models.CommentResponse, len(rows))
+	for i := range rows {
+		result.Replies[i] = rows[i].ToResponse()
+	}
+
+	levels := commentDepth(depth)
+	if levels < 1 {
+		levels = 1
+	}
+	if err := cs.expandReplies(result.Replies, sortBy, levels-1); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// expandReplies 逐层为评论加载回复，每层按 commentRepliesBatch 个父评论一条查询。
+// depth 为还能展开的层数；回复超过 comment.inline_replies 条或到达层数上限时设置 RepliesCursor
+func (cs *CommentService) expandReplies(comments []models.CommentResponse, sortBy models.CommentSort, depth int) error {
+	limit := config.GetConfig().Comment.InlineReplies
+
+	level := make([]*models.CommentResponse, len(comments))
+	for i := range comments {
+		level[i] = &comments[i]
+	}
+
+	for ; len(level) > 0; depth-- {
+		var parentIDs []uint
+		for _, c := range level {
+			if c.ReplyCount == 0 {
+				continue
+			}
+			if depth <= 0 {
+				c.RepliesCursor = newReplyCursor(sortBy, nil).encode()
+				continue
+			}
+			parentIDs = append(parentIDs, c.ID)
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+
+		replies, err := cs.fetchReplies(parentIDs, sortBy, nil, limit)
+		if err != nil {
+			return err
+		}
+
+		var next []*models.CommentResponse
+		for _, c := range level {
+			rows := replies[c.ID]
+			if len(rows) > limit {
+				rows = rows[:limit]
+				c.RepliesCursor = newReplyCursor(sortBy, &rows[limit-1]).encode()
+			}
+			c.Replies = make([]models.CommentResponse, len(rows))
+			for i := range rows {
+				c.Replies[i] = rows[i].ToResponse()
+				next = append(next, &c.Replies[i])
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// fetchReplies 为每个父评论取 after 之后的 limit+1 条审核通过的回复（多取一条用于判断是否还有更多），按父评论分组。
+// 不使用窗口函数，以兼容 MySQL 5.7：先用 UNION ALL 合并各父评论的分页查询取出 ID，再批量加载评论和作者
+func (cs *CommentService) fetchReplies(parentIDs []uint, sortBy models.CommentSort, after *replyCursor, limit int) (map[uint][]models.Comment, error) {
+	var ids []uint
+	for start := 0; start < len(parentIDs); start += commentRepliesBatch {
+		end := start + commentRepliesBatch
+		if end > len(parentIDs) {
+			end = len(parentIDs)
+		}
+
+		parts := make([]string, 0, end-start)
+		args := make([]interface{}, 0, end-start)
+		for i, parentID := range parentIDs[start:end] {
+			q := cs.db.Model(&models.Comment{}).Select("id").
+				Where("parent_id = ? AND is_approved = ?", parentID, true)
+			parts = append(parts, fmt.Sprintf("SELECT * FROM (?) AS replies_%d", i))
+			args = append(args, after.apply(q).Order(commentOrder(sortBy)).Limit(limit+1))
+		}
+
+		var batch []uint
+		if err := cs.db.Raw(strings.Join(parts, " UNION ALL "), args...).Scan(&batch).Error; err != nil {
+			return nil, err
+		}
+		ids = append(ids, batch...)
+	}
+
+	result := make(map[uint][]models.Comment, len(parentIDs))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var comments []models.Comment
+	if err := cs.db.Preload("User").Where("id IN ?", ids).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	// UNION 不保证顺序，按排序方式重新排列
+	sort.SliceStable(comments, func(i, j int) bool {
+		return commentBefore(sortBy, &comments[i], &comments[j])
+	})
+	for _, comment := range comments {
+		result[*comment.ParentID] = append(result[*comment.ParentID], comment)
+	}
+	return result, nil
+}
+
+// commentSortOr 校验排序方式，为空时使用 fallback
+func commentSortOr(sortBy, fallback models.CommentSort) (models.CommentSort, error) {
+	if sortBy == "" {
+		return fallback, nil
+	}
+	for _, s := range models.CommentSortList {
+		if s == sortBy {
+			return sortBy, nil
+		}
+	}
+	return "", ErrCommentSort
+}
+
+// commentDepth 计算展开的回复层数，不超过 comment.max_depth
+func commentDepth(depth *int) int {
+	maxDepth := config.GetConfig().Comment.MaxDepth
+	switch {
+	case depth == nil || *depth > maxDepth:
+		return maxDepth
+	case *depth < 0:
+		return 0
+	}
+	return *depth
+}
+
+// commentOrder 排序方式对应的排序子句，以 ID 保证顺序稳定
+func commentOrder(sortBy models.CommentSort) string {
+	switch sortBy {
+	case models.CommentSortNewest:
+		return "created_at DESC, id DESC"
+	case models.CommentSortTop:
+		return "reply_count DESC, id DESC"
+	}
+	return "created_at ASC, id ASC"
+}
+
+// commentBefore 按排序方式比较两条评论，与 commentOrder 一致
+func commentBefore(sortBy models.CommentSort, a, b *models.Comment) bool {
+	switch sortBy {
+	case models.CommentSortNewest:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	case models.CommentSortTop:
+		if a.ReplyCount != b.ReplyCount {
+			return a.ReplyCount > b.ReplyCount
+		}
+		return a.ID > b.ID
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// replyCursor 回复分页的位置：排序方式和最后一条回复的排序键，ID 为 0 时表示从第一条开始
+type replyCursor struct {
+	Sort models.CommentSort
+	Key  int64 // oldest/newest 为创建时间（纳秒），top 为回复数
+	ID   uint
+}
+
+// newReplyCursor 返回 last 之后的位置，last 为空时表示从第一条开始
+func newReplyCursor(sortBy models.CommentSort, last *models.Comment) *replyCursor {
+	c := &replyCursor{Sort: sortBy}
+	if last == nil {
+		return c
+	}
+	c.ID = last.ID
+	if sortBy == models.CommentSortTop {
+		c.Key = int64(last.ReplyCount)
+	} else {
+		c.Key = last.CreatedAt.UnixNano()
+	}
+	return c
 }
 
-// ToggleCommentApproval 切换评论审核状态（管理员功能）
-func (cs *CommentService) ToggleCommentApproval(commentID uint) (*models.Comment, error) {
+// encode 将位置编码为不透明的游标字符串
+func (c *replyCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", c.Sort, c.Key, c.ID)))
+}
+
+// decodeReplyCursor 解析游标字符串
+func decodeReplyCursor(s string) (*replyCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrCommentCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, ErrCommentCursor
+	}
+	sortBy, err := commentSortOr(models.CommentSort(parts[0]), "")
+	if err != nil || sortBy == "" {
+		return nil, ErrCommentCursor
+	}
+	key, err1 := strconv.ParseInt(parts[1], 10, 64)
+	id, err2 := strconv.ParseUint(parts[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, ErrCommentCursor
+	}
+	return &replyCursor{Sort: sortBy, Key: key, ID: uint(id)}, nil
+}
+
+// apply 为查询加上位置条件，c 为空或从第一条开始时不加条件
+func (c *replyCursor) apply(q *gorm.DB) *gorm.DB {
+	if c == nil || c.ID == 0 {
+		return q
+	}
+	switch c.Sort {
+	case models.CommentSortNewest:
+		t := time.Unix(0, c.Key)
+		return q.Where("(created_at < ? OR (created_at = ? AND id < ?))", t, t, c.ID)
+	case models.CommentSortTop:
+		return q.Where("(reply_count < ? OR (reply_count = ? AND id < ?))", c.Key, c.Key, c.ID)
+	}
+	t := time.Unix(0, c.Key)
+	return q.Where("(created_at > ? OR (created_at = ? AND id > ?))", t, t, c.ID)
+}
+
+// ModerateComment 通过或驳回评论（管理员功能），操作记入审计日志。
+// 驳回的评论在作者修改后仍然待审核；变为审核通过时与发表时一样通知订阅者和 Webhook
+func (cs *CommentService) ModerateComment(ctx context.Context, commentID uint, approved bool) (*models.Comment, error) {
 	var comment models.Comment
 	if err := cs.db.First(&comment, commentID).Error; err != nil {
 		return nil, err
 	}
 
-	// 切换审核状态
-	comment.IsApproved = !comment.IsApproved
-	if err := cs.db.Save(&comment).Error; err != nil {
+	wasApproved := comment.IsApproved
+	before := commentSnapshot(&comment)
+	if err := cs.db.Transaction(func(tx *gorm.DB) error {
+		if err := comment.Moderate(tx, approved); err != nil {
+			return err
+		}
+		return cs.audit.Record(ctx, tx, models.AuditCommentModerate, models.AuditTargetComment, comment.ID, before, commentSnapshot(&comment))
+	}); err != nil {
 		return nil, err
 	}
 
+	// 预加载关联数据
+	if err := cs.db.Preload("User").Preload("Post").First(&comment, comment.ID).Error; err != nil {
+		return nil, err
+	}
+
+	if !wasApproved && comment.IsApproved {
+		cs.publishApproved(&comment)
+	}
 	return &comment, nil
-}
\ No newline at end of file
+}
+
+// GetCommentsAfter 获取文章中 ID 大于 afterID 的已审核评论（包括回复），按 ID 正序
+func (cs *CommentService) GetCommentsAfter(postID, afterID uint, limit int) ([]models.Comment, error) {
+	var comments []models.Comment
+	if err := cs.db.Preload("User").
+		Where("post_id = ? AND id > ? AND is_approved = ?", postID, afterID, true).
+		Order("id ASC").
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// SubscribeComments 订阅文章的新评论（只推送审核通过的评论）。
+// 广播只在当前进程内进行；订阅者消费过慢时通道会被关闭，调用方需要重新订阅。
+// 不再需要时必须调用返回的 cancel
+func (cs *CommentService) SubscribeComments(postID uint) (<-chan models.Comment, func()) {
+	return feed.subscribe(postID)
+}
+
+// commentFeedBuffer 每个订阅者可积压的评论数
+const commentFeedBuffer = 64
+
+// commentFeed 新评论的进程内广播，所有 CommentService 实例共享
+type commentFeed struct {
+	mu   sync.Mutex
+	subs map[uint]map[chan models.Comment]struct{} // 文章ID -> 订阅者
+}
+
+var feed = &commentFeed{subs: make(map[uint]map[chan models.Comment]struct{})}
+
+// subscribe 注册订阅者，cancel 可重复调用
+func (f *commentFeed) subscribe(postID uint) (<-chan models.Comment, func()) {
+	ch := make(chan models.Comment, commentFeedBuffer)
+
+	f.mu.Lock()
+	if f.subs[postID] == nil {
+		f.subs[postID] = make(map[chan models.Comment]struct{})
+	}
+	f.subs[postID][ch] = struct{}{}
+	f.mu.Unlock()
+
+	cancel := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.remove(postID, ch)
+	}
+	return ch, cancel
+}
+
+// publish 向文章的所有订阅者推送评论，不会阻塞评论的创建
+func (f *commentFeed) publish(comment models.Comment) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subs[comment.PostID] {
+		select {
+		case ch <- comment:
+		default:
+			// 积压已满，断开该订阅者，由其补齐后重新订阅
+			f.remove(comment.PostID, ch)
+		}
+	}
+}
+
+// remove 移除并关闭订阅者通道，调用方需持有锁
+func (f *commentFeed) remove(postID uint, ch chan models.Comment) {
+	subs := f.subs[postID]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(f.subs, postID)
+	}
+}
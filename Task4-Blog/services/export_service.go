@@ -0,0 +1,470 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/jobs"
+	"blog-system/models"
+	"blog-system/utils"
+
+	"gorm.io/gorm"
+)
+
+// ExportService 导出服务
+type ExportService struct {
+	db *gorm.DB
+}
+
+// NewExportService 创建导出服务实例
+func NewExportService() *ExportService {
+	return &ExportService{
+		db: database.GetDB(),
+	}
+}
+
+// PostFrontMatter 导出文章的 YAML front matter
+type PostFrontMatter struct {
+	Title       string            `yaml:"title"`
+	Slug        string            `yaml:"slug,omitempty"`
+	Status      models.PostStatus `yaml:"status"`
+	Public      bool              `yaml:"public"`
+	Summary     string            `yaml:"summary,omitempty"`
+	Author      string            `yaml:"author"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Date        time.Time         `yaml:"date"`
+	Updated     time.Time         `yaml:"updated"`
+	PublishedAt *time.Time        `yaml:"published_at,omitempty"`
+	ViewCount   int               `yaml:"view_count"`
+	LikeCount   int               `yaml:"like_count"`
+}
+
+// ExportedComment 导出的评论
+type ExportedComment struct {
+	ID         uint      `json:"id"`
+	PostID     uint      `json:"post_id"`
+	PostSlug   string    `json:"post_slug"`
+	ParentID   *uint     `json:"parent_id,omitempty"`
+	Author     string    `json:"author"`
+	Content    string    `json:"content"`
+	IsApproved bool      `json:"is_approved"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// exportBatchSize 每批读取的文章数量
+const exportBatchSize = 100
+
+// ShouldExportAsync 判断用户的内容是否需要异步导出
+func (es *ExportService) ShouldExportAsync(userID uint) (bool, error) {
+	var count int64
+	if err := es.db.Model(&models.Post{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > int64(config.GetConfig().Export.SyncMaxPosts), nil
+}
+
+// WriteUserArchive 将用户的博客写入 ZIP 归档
+func (es *ExportService) WriteUserArchive(ctx context.Context, w io.Writer, userID uint) error {
+	var user models.User
+	if err := es.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeJSON(zw, "profile.json", user.ToResponse()); err != nil {
+		return err
+	}
+
+	// 文章
+	query := es.db.WithContext(ctx).Where("user_id = ?", userID)
+	if err := es.writePosts(ctx, zw, query, func(models.Post) string { return "posts" }); err != nil {
+		return err
+	}
+
+	// 用户文章下的评论
+	postIDs := es.db.Model(&models.Post{}).Select("id").Where("user_id = ?", userID)
+	comments, err := es.collectComments(ctx, es.db.Where("comments.post_id IN (?)", postIDs))
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "comments.json", comments); err != nil {
+		return err
+	}
+
+	// 用户在其它文章下发表的评论
+	myComments, err := es.collectComments(ctx, es.db.Where("comments.user_id = ?", userID))
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "my-comments.json", myComments); err != nil {
+		return err
+	}
+
+	// 上传的媒体文件
+	mediaDir := filepath.Join(config.GetConfig().Storage.UploadDir, "users", fmt.Sprint(userID))
+	if err := addDir(ctx, zw, mediaDir, "media"); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// WriteSiteArchive 将全站内容写入 ZIP 归档（管理员功能）
+func (es *ExportService) WriteSiteArchive(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	// 用户（不含密码）
+	var users []models.User
+	if err := es.db.WithContext(ctx).Order("id").Find(&users).Error; err != nil {
+		return err
+	}
+	usernames := make(map[uint]string, len(users))
+	responses := make([]models.UserResponse, 0, len(users))
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+		responses = append(responses, u.ToResponse())
+	}
+	if err := writeJSON(zw, "users.json", responses); err != nil {
+		return err
+	}
+
+	// 标签
+	var tags []models.Tag
+	if err := es.db.WithContext(ctx).Order("id").Find(&tags).Error; err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "tags.json", tags); err != nil {
+		return err
+	}
+
+	// 按作者分目录的文章
+	err := es.writePosts(ctx, zw, es.db.WithContext(ctx), func(p models.Post) string {
+		return path.Join("posts", usernames[p.UserID])
+	})
+	if err != nil {
+		return err
+	}
+
+	// 全部评论
+	comments, err := es.collectComments(ctx, es.db)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "comments.json", comments); err != nil {
+		return err
+	}
+
+	// 全部上传文件
+	if err := addDir(ctx, zw, config.GetConfig().Storage.UploadDir, "media"); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// writePosts 分批读取文章并写入 Markdown 文件，dirOf 决定文章所在目录
+func (es *ExportService) writePosts(ctx context.Context, zw *zip.Writer, query *gorm.DB, dirOf func(models.Post) string) error {
+	var posts []models.Post
+	var writeErr error
+
+	result := query.Preload("User").Preload("Tags").Order("id").
+		FindInBatches(&posts, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, post := range posts {
+				if err := ctx.Err(); err != nil {
+					writeErr = err
+					return err
+				}
+				if err := writePost(zw, dirOf(post), post); err != nil {
+					writeErr = err
+					return err
+				}
+			}
+			return nil
+		})
+	if writeErr != nil {
+		return writeErr
+	}
+	return result.Error
+}
+
+// writePost 将单篇文章写为带 front matter 的 Markdown
+func writePost(zw *zip.Writer, dir string, post models.Post) error {
+	meta := PostFrontMatter{
+		Title:       post.Title,
+		Slug:        post.Slug,
+		Status:      post.Status,
+		Public:      post.IsPublic,
+		Summary:     post.Summary,
+		Author:      post.User.Username,
+		Date:        post.CreatedAt,
+		Updated:     post.UpdatedAt,
+		PublishedAt: post.PublishedAt,
+		ViewCount:   post.ViewCount,
+		LikeCount:   post.LikeCount,
+	}
+	for _, tag := range post.Tags {
+		meta.Tags = append(meta.Tags, tag.Name)
+	}
+
+	content, err := utils.FormatFrontMatter(meta, post.Content)
+	if err != nil {
+		return err
+	}
+
+	name := post.Slug
+	if name == "" {
+		name = fmt.Sprintf("post-%d", post.ID)
+	}
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     path.Join(dir, name+".md"),
+		Method:   zip.Deflate,
+		Modified: post.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
+
+// collectComments 按条件读取评论并转换为导出格式
+func (es *ExportService) collectComments(ctx context.Context, query *gorm.DB) ([]ExportedComment, error) {
+	var rows []struct {
+		ID         uint
+		PostID     uint
+		ParentID   *uint
+		Content    string
+		IsApproved bool
+		CreatedAt  time.Time
+		Author     string
+		PostSlug   string
+	}
+	err := query.WithContext(ctx).Model(&models.Comment{}).
+		Select("comments.id, comments.post_id, comments.parent_id, comments.content, comments.is_approved, " +
+			"comments.created_at, users.username AS author, posts.slug AS post_slug").
+		Joins("LEFT JOIN users ON users.id = comments.user_id").
+		Joins("LEFT JOIN posts ON posts.id = comments.post_id").
+		Order("comments.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]ExportedComment, 0, len(rows))
+	for _, row := range rows {
+		comments = append(comments, ExportedComment{
+			ID:         row.ID,
+			PostID:     row.PostID,
+			PostSlug:   row.PostSlug,
+			ParentID:   row.ParentID,
+			Author:     row.Author,
+			Content:    row.Content,
+			IsApproved: row.IsApproved,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+	return comments, nil
+}
+
+// writeJSON 将数据以 JSON 格式写入归档
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// addDir 将本地目录中的文件加入归档，目录不存在时忽略
+func addDir(ctx context.Context, zw *zip.Writer, dir, prefix string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(prefix, filepath.ToSlash(rel))
+		header.Method = zip.Deflate
+
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(dst, src)
+		return err
+	})
+}
+
+// CreateJob 创建异步导出任务并在后台执行
+func (es *ExportService) CreateJob(scope models.ExportScope, userID uint) (*models.ExportJob, error) {
+	job := &models.ExportJob{
+		Scope:  scope,
+		UserID: userID,
+		Status: models.ExportStatusPending,
+	}
+	if err := es.db.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	jobs.Go(fmt.Sprintf("export-%d", job.ID), func(ctx context.Context) error {
+		return es.runJob(ctx, job.ID)
+	})
+	return job, nil
+}
+
+// GetJob 获取导出任务
+func (es *ExportService) GetJob(jobID uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := es.db.First(&job, jobID).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// runJob 执行导出任务，将归档写入导出目录
+func (es *ExportService) runJob(ctx context.Context, jobID uint) error {
+	job, err := es.GetJob(jobID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	es.db.Model(job).Updates(map[string]interface{}{
+		"status":     models.ExportStatusRunning,
+		"started_at": &now,
+	})
+
+	filePath, size, err := es.writeJobArchive(ctx, job)
+	if err != nil {
+		es.db.Model(job).Updates(map[string]interface{}{
+			"status": models.ExportStatusFailed,
+			"error":  err.Error(),
+		})
+		return err
+	}
+
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(time.Duration(config.GetConfig().Export.RetentionHours) * time.Hour)
+	return es.db.Model(job).Updates(map[string]interface{}{
+		"status":       models.ExportStatusCompleted,
+		"file_path":    filePath,
+		"file_size":    size,
+		"completed_at": &completedAt,
+		"expires_at":   &expiresAt,
+	}).Error
+}
+
+// writeJobArchive 生成归档文件，先写临时文件成功后再重命名
+func (es *ExportService) writeJobArchive(ctx context.Context, job *models.ExportJob) (string, int64, error) {
+	dir := config.GetConfig().Storage.ExportDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", 0, fmt.Errorf("创建导出目录失败: %v", err)
+	}
+
+	// 文件名带随机后缀，避免被猜测
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", 0, err
+	}
+	finalPath := filepath.Join(dir, fmt.Sprintf("export-%d-%s.zip", job.ID, hex.EncodeToString(suffix)))
+
+	tmp, err := os.CreateTemp(dir, ".export-*.zip")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if job.Scope == models.ExportScopeSite {
+		err = es.WriteSiteArchive(ctx, tmp)
+	} else {
+		err = es.WriteUserArchive(ctx, tmp, job.UserID)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), finalPath); err != nil {
+		return "", 0, err
+	}
+	return finalPath, info.Size(), nil
+}
+
+// CleanupExpired 清理过期的归档文件，并将中断的任务标记为失败
+func (es *ExportService) CleanupExpired(ctx context.Context) error {
+	var expired []models.ExportJob
+	if err := es.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", models.ExportStatusCompleted, time.Now()).
+		Find(&expired).Error; err != nil {
+		return err
+	}
+
+	for _, job := range expired {
+		if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("删除过期归档 %s 失败: %v", job.FilePath, err)
+			continue
+		}
+		es.db.Model(&job).Updates(map[string]interface{}{
+			"status":    models.ExportStatusExpired,
+			"file_path": "",
+		})
+	}
+
+	// 服务重启等原因会导致任务中断，长时间未更新的任务视为失败
+	return es.db.WithContext(ctx).Model(&models.ExportJob{}).
+		Where("status IN ? AND updated_at < ?",
+			[]models.ExportStatus{models.ExportStatusPending, models.ExportStatusRunning},
+			time.Now().Add(-6*time.Hour)).
+		Updates(map[string]interface{}{
+			"status": models.ExportStatusFailed,
+			"error":  "任务中断",
+		}).Error
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+
+	"blog-system/database"
+	"blog-system/importer"
+
+	"gorm.io/gorm"
+)
+
+// ImportService 导入服务
+type ImportService struct {
+	db *gorm.DB
+}
+
+// NewImportService 创建导入服务实例
+func NewImportService() *ImportService {
+	return &ImportService{
+		db: database.GetDB(),
+	}
+}
+
+// Import 导入站点内容（管理员功能），作者按映射匹配本地用户
+func (is *ImportService) Import(doc *importer.Document, opts importer.Options) (*importer.Report, error) {
+	return importer.Import(is.db, doc, opts)
+}
+
+// ImportForUser 将内容导入到指定用户名下。来源标识按用户隔离，不同用户导入同名文件互不影响
+func (is *ImportService) ImportForUser(userID uint, doc *importer.Document, dryRun bool) (*importer.Report, error) {
+	doc.Source = fmt.Sprintf("user:%d/%s", userID, doc.Source)
+	return importer.Import(is.db, doc, importer.Options{
+		DryRun:        dryRun,
+		ForceAuthorID: userID,
+	})
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCredentials 用户名或密码错误。用户不存在、已停用和密码错误都返回这个错误，不暴露用户名是否存在
+var ErrInvalidCredentials = errors.New("用户名或密码错误")
+
+// LoginBlockedError 失败次数过多，需要等待 RetryAfter 后才能再次尝试
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请 %d 秒后再试", int(e.RetryAfter.Round(time.Second)/time.Second))
+}
+
+// LoginAttemptFilter 登录记录查询条件，零值表示不限制
+type LoginAttemptFilter struct {
+	UserID uint
+	IP     string
+	Result models.LoginResult
+}
+
+// LoginAttemptService 登录尝试记录服务：按账号和 IP 统计失败次数，计算需要等待的时间
+type LoginAttemptService struct {
+	db *gorm.DB
+}
+
+// blockedAttemptBuffer 待写入的被拒绝登录记录。被拒绝的尝试不参与失败次数统计，
+// 攻击时数量最多，批量写入；成功和失败的记录决定锁定状态，仍然直接写入
+var blockedAttemptBuffer = newRecordBuffer[*models.LoginAttempt]("登录记录", "login-attempt-flush")
+
+// NewLoginAttemptService 创建登录尝试记录服务实例
+func NewLoginAttemptService() *LoginAttemptService {
+	return &LoginAttemptService{
+		db: database.GetDB(),
+	}
+}
+
+// Check 返回还需等待的时间和原因，0 表示可以尝试登录。
+// 已知用户按用户ID统计（用户名和邮箱登录合并计算），否则按提交的标识统计，两者的限制方式相同
+func (ls *LoginAttemptService) Check(userID *uint, identifier, ip string, now time.Time) (time.Duration, string, error) {
+	cfg := config.GetConfig().Login
+
+	account := func(q *gorm.DB) *gorm.DB {
+		if userID != nil {
+			return q.Where("user_id = ?", *userID)
+		}
+		return q.Where("user_id IS NULL AND identifier = ?", identifier)
+	}
+	failures, last, err := ls.failures(account, true, now, max(cfg.FailureWindow, cfg.LockoutMinutes))
+	if err != nil {
+		return 0, "", err
+	}
+
+	var until time.Time
+	switch {
+	case cfg.MaxFailures > 0 && failures >= cfg.MaxFailures:
+		until = last.Add(time.Duration(cfg.LockoutMinutes) * time.Minute)
+	case cfg.DelayAfter > 0 && failures >= cfg.DelayAfter:
+		until = last.Add(progressiveDelay(cfg.DelaySeconds, failures-cfg.DelayAfter))
+	}
+	if wait := until.Sub(now); wait > 0 {
+		return wait, models.LoginReasonAccountLocked, nil
+	}
+
+	if cfg.IPMaxFailures > 0 && ip != "" {
+		byIP := func(q *gorm.DB) *gorm.DB {
+			return q.Where("ip = ?", ip)
+		}
+		failures, last, err := ls.failures(byIP, false, now, max(cfg.FailureWindow, cfg.IPLockoutMinutes))
+		if err != nil {
+			return 0, "", err
+		}
+		if failures >= cfg.IPMaxFailures {
+			if wait := last.Add(time.Duration(cfg.IPLockoutMinutes) * time.Minute).Sub(now); wait > 0 {
+				return wait, models.LoginReasonIPLocked, nil
+			}
+		}
+	}
+	return 0, "", nil
+}
+
+// failures 统计时间窗口内的失败次数和最近一次失败的时间。
+// resettable 为 true 时只统计最近一次登录成功或解除锁定之后的失败
+func (ls *LoginAttemptService) failures(scope func(*gorm.DB) *gorm.DB, resettable bool, now time.Time, windowMinutes int) (int, time.Time, error) {
+	since := now.Add(-time.Duration(windowMinutes) * time.Minute)
+
+	if resettable {
+		var reset models.LoginAttempt
+		err := scope(ls.db.Model(&models.LoginAttempt{})).
+			Where("result IN ? AND created_at > ?", []models.LoginResult{models.LoginResultSuccess, models.LoginResultUnlocked}, since).
+			Order("created_at DESC").Select("created_at").Take(&reset).Error
+		if err == nil {
+			since = reset.CreatedAt
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, time.Time{}, err
+		}
+	}
+
+	query := func() *gorm.DB {
+		return scope(ls.db.Model(&models.LoginAttempt{})).
+			Where("result = ? AND created_at > ?", models.LoginResultFailed, since)
+	}
+	var count int64
+	if err := query().Count(&count).Error; err != nil || count == 0 {
+		return 0, time.Time{}, err
+	}
+	var last models.LoginAttempt
+	if err := query().Order("created_at DESC").Select("created_at").Take(&last).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(count), last.CreatedAt, nil
+}
+
+// progressiveDelay 第 n 次（从 0 开始）需要等待的时间，每次翻倍，最长 1 小时
+func progressiveDelay(baseSeconds, n int) time.Duration {
+	delay := time.Duration(baseSeconds) * time.Second
+	for i := 0; i < n && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
+
+// Record 保存登录尝试记录
+func (ls *LoginAttemptService) Record(attempt *models.LoginAttempt) error {
+	if attempt.Result == models.LoginResultBlocked {
+		attempt.CreatedAt = time.Now()
+		blockedAttemptBuffer.Add(attempt)
+		return nil
+	}
+	return ls.db.Create(attempt).Error
+}
+
+// Unlock 解除账号的登录锁定和两步验证锁定（管理员功能），解除记录同样写入登录记录
+func (ls *LoginAttemptService) Unlock(userID, adminID uint, ip string) error {
+	var user models.User
+	if err := ls.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	return ls.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_failures":     0,
+			"two_factor_locked_until": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.LoginAttempt{
+			UserID:     &user.ID,
+			Identifier: strings.ToLower(user.Username),
+			IP:         ip,
+			Result:     models.LoginResultUnlocked,
+			Reason:     fmt.Sprintf("admin:%d", adminID),
+		}).Error
+	})
+}
+
+// ListAttempts 分页查询登录记录，最新的在前
+func (ls *LoginAttemptService) ListAttempts(filter LoginAttemptFilter, page, pageSize int) ([]models.LoginAttempt, int64, error) {
+	var attempts []models.LoginAttempt
+	var total int64
+
+	if err := blockedAttemptBuffer.Flush(context.Background()); err != nil {
+		log.Println(err)
+	}
+
+	query := ls.db.Model(&models.LoginAttempt{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+	return attempts, total, nil
+}
+
+// CleanupAttempts 删除超过保留期的登录记录
+func (ls *LoginAttemptService) CleanupAttempts(ctx context.Context) error {
+	cutoff := time.Now().AddDate(0, 0, -config.GetConfig().Login.RetentionDays)
+	return ls.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.LoginAttempt{}).Error
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAccessTokenInvalid 令牌不存在、已撤销、已过期或所属用户已停用
+	ErrAccessTokenInvalid = errors.New("访问令牌无效、已过期或已撤销")
+	// ErrAccessTokenLimit 有效令牌数量达到上限
+	ErrAccessTokenLimit = errors.New("访问令牌数量已达上限")
+	// ErrAccessTokenExpiry 有效期不合法
+	ErrAccessTokenExpiry = errors.New("过期时间不合法")
+)
+
+const (
+	accessTokenPrefix       = "pat_"
+	accessTokenDisplayLen   = 12          // 保存用于辨认的令牌开头长度（含前缀）
+	accessTokenUsedInterval = time.Minute // 最近使用时间的更新间隔，避免每个请求都写库
+)
+
+// IsPersonalAccessToken 判断 Bearer 凭证是否为个人访问令牌
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, accessTokenPrefix)
+}
+
+// PersonalAccessTokenService 个人访问令牌服务
+type PersonalAccessTokenService struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenService 创建个人访问令牌服务实例
+func NewPersonalAccessTokenService() *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		db: database.GetDB(),
+	}
+}
+
+// CreateToken 创建令牌，返回令牌记录和明文令牌，明文不落库，只能在此时获取。
+// expiresAt 为空表示永不过期（access_token.max_expire_days 不为 0 时必须设置）
+func (ts *PersonalAccessTokenService) CreateToken(userID uint, name string, scopes models.TokenScopes, expiresAt *time.Time) (*models.PersonalAccessToken, string, error) {
+	cfg := config.GetConfig().AccessToken
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", ErrAccessTokenExpiry
+	}
+	if cfg.MaxExpireDays > 0 {
+		limit := now.AddDate(0, 0, cfg.MaxExpireDays)
+		if expiresAt == nil || expiresAt.After(limit) {
+			return nil, "", ErrAccessTokenExpiry
+		}
+	}
+
+	var count int64
+	err := ts.db.Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Count(&count).Error
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= int64(cfg.MaxPerUser) {
+		return nil, "", ErrAccessTokenLimit
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := accessTokenPrefix + secret
+
+	token := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashAccessToken(plain),
+		Prefix:    plain[:accessTokenDisplayLen],
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := ts.db.Create(token).Error; err != nil {
+		return nil, "", err
+	}
+	return token, plain, nil
+}
+
+// GetUserTokens 获取用户的令牌列表，包含已撤销和已过期的令牌
+func (ts *PersonalAccessTokenService) GetUserTokens(userID uint) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	if err := ts.db.Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeToken 撤销用户的令牌，已撤销的令牌保持原撤销时间
+func (ts *PersonalAccessTokenService) RevokeToken(userID, tokenID uint) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := ts.db.Where("id = ? AND user_id = ?", tokenID, userID).First(&token).Error; err != nil {
+		return nil, err
+	}
+	if token.RevokedAt != nil {
+		return &token, nil
+	}
+
+	now := time.Now()
+	if err := ts.db.Model(&token).Update("revoked_at", now).Error; err != nil {
+		return nil, err
+	}
+	token.RevokedAt = &now
+	return &token, nil
+}
+
+// Authenticate 验证令牌并返回所属用户，用户信息取自数据库而不是令牌。
+// 令牌不能行使管理员等角色的权限，只能访问其权限范围内的接口
+func (ts *PersonalAccessTokenService) Authenticate(plain, ip string) (*TokenUser, error) {
+	var token models.PersonalAccessToken
+	if err := ts.db.Where("token_hash = ?", hashAccessToken(plain)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccessTokenInvalid
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if !token.Active(now) {
+		return nil, ErrAccessTokenInvalid
+	}
+
+	var user models.User
+	if err := ts.db.First(&user, token.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccessTokenInvalid
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrAccessTokenInvalid
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenUsedInterval || token.LastUsedIP != ip {
+		err := ts.db.Model(&token).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+		if err != nil {
+			log.Printf("更新访问令牌 %d 的使用时间失败: %v", token.ID, err)
+		}
+	}
+
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+	return &TokenUser{ID: user.ID, Username: user.Username, Email: user.Email, Role: user.Role, Scopes: scopes}, nil
+}
+
+// hashAccessToken 令牌的 SHA-256 摘要。令牌本身是 256 位随机数，不需要加盐或慢哈希
+func hashAccessToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"blog-system/database"
 	"blog-system/models"
 
@@ -9,18 +11,22 @@ import (
 
 // PostService 文章服务
 type PostService struct {
-	db *gorm.DB
+	db       *gorm.DB
+	webhooks *WebhookService
+	audit    *AuditService
 }
 
 // NewPostService 创建文章服务实例
 func NewPostService() *PostService {
 	return &PostService{
-		db: database.GetDB(),
+		db:       database.GetDB(),
+		webhooks: NewWebhookService(),
+		audit:    NewAuditService(),
 	}
 }
 
-// CreatePost 创建文章
-func (ps *PostService) CreatePost(userID uint, title, content, summary, slug string, status models.PostStatus, isPublic bool) (*models.Post, error) {
+// CreatePost 创建文章，操作记入审计日志
+func (ps *PostService) CreatePost(ctx context.Context, userID uint, title, content, summary, slug string, status models.PostStatus, isPublic bool) (*models.Post, error) {
 	post := &models.Post{
 		Title:    title,
 		Content:  content,
@@ -31,13 +37,23 @@ func (ps *PostService) CreatePost(userID uint, title, content, summary, slug str
 		UserID:   userID,
 	}
 
-	if err := ps.db.Create(post).Error; err != nil {
+	if err := ps.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+
+		// 预加载用户信息
+		if err := tx.Preload("User").First(post, post.ID).Error; err != nil {
+			return err
+		}
+
+		return ps.audit.Record(ctx, tx, models.AuditPostCreate, models.AuditTargetPost, post.ID, nil, postSnapshot(post))
+	}); err != nil {
 		return nil, err
 	}
 
-	// 预加载用户信息
-	if err := ps.db.Preload("User").First(post, post.ID).Error; err != nil {
-		return nil, err
+	if post.Status == models.PostStatusPublished {
+		ps.emit(models.WebhookEventPostPublished, post)
 	}
 
 	return post, nil
@@ -90,12 +106,13 @@ func (ps *PostService) GetPosts(page, pageSize int, status models.PostStatus) ([
 	return postResponses, total, nil
 }
 
-// UpdatePost 更新文章
-func (ps *PostService) UpdatePost(postID uint, title, content, summary, slug string, status models.PostStatus, isPublic *bool) (*models.Post, error) {
+// UpdatePost 更新文章，有修改时记入审计日志
+func (ps *PostService) UpdatePost(ctx context.Context, postID uint, title, content, summary, slug string, status models.PostStatus, isPublic *bool) (*models.Post, error) {
 	var post models.Post
 	if err := ps.db.First(&post, postID).Error; err != nil {
 		return nil, err
 	}
+	before := postSnapshot(&post)
 
 	updates := make(map[string]interface{})
 	if title != "" {
@@ -117,23 +134,62 @@ func (ps *PostService) UpdatePost(postID uint, title, content, summary, slug str
 		updates["is_public"] = *isPublic
 	}
 
-	if len(updates) > 0 {
-		if err := ps.db.Model(&post).Updates(updates).Error; err != nil {
-			return nil, err
+	var previous models.PostStatus
+	if err := ps.db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&post).Updates(updates).Error; err != nil {
+				return err
+			}
 		}
-	}
 
-	// 重新加载关联数据
-	if err := ps.db.Preload("User").First(&post, postID).Error; err != nil {
+		// 重新加载关联数据
+		previous = post.Status
+		if err := tx.Preload("User").First(&post, postID).Error; err != nil {
+			return err
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+		return ps.audit.Record(ctx, tx, models.AuditPostUpdate, models.AuditTargetPost, post.ID, before, postSnapshot(&post))
+	}); err != nil {
 		return nil, err
 	}
 
+	// 首次变为已发布时触发 post.published，其余修改触发 post.updated
+	if post.Status == models.PostStatusPublished && previous != models.PostStatusPublished {
+		ps.emit(models.WebhookEventPostPublished, &post)
+	} else if len(updates) > 0 {
+		ps.emit(models.WebhookEventPostUpdated, &post)
+	}
+
 	return &post, nil
 }
 
-// DeletePost 删除文章
-func (ps *PostService) DeletePost(postID uint) error {
-	return ps.db.Delete(&models.Post{}, postID).Error
+// DeletePost 删除文章（移入回收站），操作记入审计日志
+func (ps *PostService) DeletePost(ctx context.Context, postID uint) error {
+	var post models.Post
+	if err := ps.db.Preload("User").First(&post, postID).Error; err != nil {
+		return err
+	}
+
+	if err := ps.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&post).Error; err != nil {
+			return err
+		}
+		return ps.audit.Record(ctx, tx, models.AuditPostDelete, models.AuditTargetPost, post.ID, postSnapshot(&post), nil)
+	}); err != nil {
+		return err
+	}
+
+	ps.emit(models.WebhookEventPostDeleted, &post)
+	return nil
+}
+
+// emit 触发文章相关的 Webhook 事件
+func (ps *PostService) emit(event models.WebhookEvent, post *models.Post) {
+	response := post.ToResponse()
+	ps.webhooks.Emit(event, post.UserID, WebhookData{Post: &response})
 }
 
 // IsPostOwner 检查用户是否是文章的作者
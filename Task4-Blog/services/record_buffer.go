@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"blog-system/database"
+	"blog-system/jobs"
+)
+
+const (
+	recordBufferBatch    = 100         // 攒满一批后立即写入
+	recordBufferInterval = time.Second // 定时写入的间隔
+)
+
+// recordBuffer 缓存丢失少量也无妨的只追加记录（如被拒绝的登录尝试），定时或攒满一批后批量写入。
+// 由后台任务定时刷新，服务停止时通过停止回调写入剩余记录
+type recordBuffer[T any] struct {
+	name    string
+	mu      sync.Mutex
+	pending []T
+	flushMu sync.Mutex // 保证同一时间只有一次写入，避免记录乱序
+}
+
+// newRecordBuffer 创建记录缓冲区并注册定时写入任务和停止回调，name 用于错误信息。
+// 命令行不启动调度器，退出前由停止回调写入
+func newRecordBuffer[T any](name, jobName string) *recordBuffer[T] {
+	b := &recordBuffer[T]{name: name}
+	jobs.Register(jobs.Job{Name: jobName, Interval: recordBufferInterval, Run: b.Flush})
+	jobs.OnStop(b.Flush)
+	return b
+}
+
+// Add 加入一条记录，攒满一批时在当前 goroutine 中写入
+func (b *recordBuffer[T]) Add(record T) {
+	b.mu.Lock()
+	b.pending = append(b.pending, record)
+	full := len(b.pending) >= recordBufferBatch
+	b.mu.Unlock()
+
+	if full {
+		if err := b.Flush(context.Background()); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// Flush 写入所有缓存的记录。与直接写入时一样，写入失败的记录不再重试
+func (b *recordBuffer[T]) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	records := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := database.GetDB().WithContext(ctx).CreateInBatches(records, recordBufferBatch).Error; err != nil {
+		return fmt.Errorf("写入 %d 条%s失败: %v", len(records), b.name, err)
+	}
+	return nil
+}
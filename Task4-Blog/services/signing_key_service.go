@@ -0,0 +1,480 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/models"
+
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+// 签名算法
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+const (
+	// signingKeyCacheTTL 缓存的密钥列表多久从数据库刷新一次，以获取其他实例轮换的密钥
+	signingKeyCacheTTL = time.Minute
+	// signingKeyReloadInterval 遇到未知 kid 时强制刷新的最小间隔
+	signingKeyReloadInterval = 5 * time.Second
+	// privateKeyPrefix 加密后的私钥前缀，其后为 base64 编码的 AES-256-GCM 密文
+	privateKeyPrefix = "enc:v1:"
+)
+
+// ErrUnknownSigningKey token 的 kid 不存在或已过宽限期
+var ErrUnknownSigningKey = errors.New("未知的签名密钥")
+
+// JWK JSON Web Key（RFC 7517），只包含公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // OKP 曲线
+	X   string `json:"x,omitempty"`   // Ed25519 公钥
+}
+
+// JWKS JSON Web Key Set，其他服务用其中的公钥验证本服务签发的 token
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// signingKey 解析后的签名密钥
+type signingKey struct {
+	models.SigningKey
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// signingKeyCache 签名密钥缓存
+type signingKeyCache struct {
+	mu       sync.Mutex
+	keys     []*signingKey // 按 ActivatesAt 升序
+	loadedAt time.Time
+}
+
+// snapshot 返回缓存的密钥列表，不刷新
+func (c *signingKeyCache) snapshot() []*signingKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.keys
+}
+
+// signingKeys 所有 SigningKeyService 实例共享的密钥缓存
+var signingKeys = &signingKeyCache{}
+
+// signingKeyCreateMu 避免并发请求同时生成密钥
+var signingKeyCreateMu sync.Mutex
+
+// SigningKeyService JWT 签名密钥服务：签名、验证、轮换和 JWKS。
+// jwt.algorithm 为 HS256 时使用共享密钥 jwt.secret；为 RS256 / EdDSA 时使用数据库中的密钥对，
+// 同一时间只有最新生效的密钥用于签名，被替换的密钥在 jwt.grace_hours 内仍可验证
+type SigningKeyService struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyService 创建签名密钥服务实例
+func NewSigningKeyService() *SigningKeyService {
+	return &SigningKeyService{
+		db: database.GetDB(),
+	}
+}
+
+// Sign 签名 token，非对称签名时在 header 中写入 kid
+func (ks *SigningKeyService) Sign(claims jwt.Claims) (string, error) {
+	cfg := config.GetConfig().JWT
+	if cfg.Algorithm == AlgorithmHS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Secret))
+	}
+
+	key, err := ks.activeKey(cfg.Algorithm)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// Keyfunc 供 jwt.Parse 使用，按 token 的 kid 查找验证密钥并检查签名算法
+func (ks *SigningKeyService) Keyfunc(token *jwt.Token) (interface{}, error) {
+	cfg := config.GetConfig().JWT
+	if cfg.Algorithm == AlgorithmHS256 {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(cfg.Secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrUnknownSigningKey
+	}
+	key, err := ks.verificationKey(kid, signingKeyCacheTTL)
+	if errors.Is(err, ErrUnknownSigningKey) {
+		// 可能是其他实例刚生成的密钥
+		key, err = ks.verificationKey(kid, signingKeyReloadInterval)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key.public, nil
+}
+
+// JWKS 当前发布的公钥：即将生效的、正在使用的和宽限期内的密钥
+func (ks *SigningKeyService) JWKS() (*JWKS, error) {
+	set := &JWKS{Keys: []JWK{}}
+	algorithm := config.GetConfig().JWT.Algorithm
+	if algorithm == AlgorithmHS256 {
+		return set, nil
+	}
+
+	// 尚未签发过 token 时也要发布当前密钥
+	if _, err := ks.activeKey(algorithm); err != nil {
+		return nil, err
+	}
+	for _, key := range publishedKeys(signingKeys.snapshot(), time.Now()) {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	return set, nil
+}
+
+// RotateKeys 轮换签名密钥并删除宽限期已过的密钥，由后台任务定期调用。
+// 当前密钥使用满 jwt.rotation_days 天前 jwt.publish_ahead 分钟生成新密钥，新密钥先发布到 JWKS，到期后开始用于签名
+func (ks *SigningKeyService) RotateKeys(ctx context.Context) error {
+	cfg := config.GetConfig().JWT
+	if cfg.Algorithm == AlgorithmHS256 {
+		return nil
+	}
+
+	keys, err := ks.loadKeys(0)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	publishAhead := time.Duration(cfg.PublishAhead) * time.Minute
+
+	var newest *signingKey
+	for _, key := range keys {
+		if key.Algorithm == cfg.Algorithm {
+			newest = key
+		}
+	}
+	switch {
+	case newest == nil:
+		// 尚无该算法的密钥（首次启用或更换了算法），立即生效
+		if _, err := ks.createKey(ctx, cfg.Algorithm, now); err != nil {
+			return err
+		}
+	case cfg.RotationDays > 0 && !newest.ActivatesAt.AddDate(0, 0, cfg.RotationDays).After(now.Add(publishAhead)):
+		if _, err := ks.createKey(ctx, cfg.Algorithm, now.Add(publishAhead)); err != nil {
+			return err
+		}
+	}
+
+	// 删除宽限期已过的密钥
+	published := make(map[string]bool)
+	for _, key := range publishedKeys(keys, now) {
+		published[key.ID] = true
+	}
+	var expired []string
+	for _, key := range keys {
+		if !published[key.ID] {
+			expired = append(expired, key.ID)
+		}
+	}
+	if len(expired) > 0 {
+		if err := ks.db.WithContext(ctx).Where("id IN ?", expired).Delete(&models.SigningKey{}).Error; err != nil {
+			return err
+		}
+	}
+
+	_, err = ks.loadKeys(0)
+	return err
+}
+
+// activeKey 返回用于签名的密钥，即最新生效的密钥；没有可用密钥时立即生成一个
+func (ks *SigningKeyService) activeKey(algorithm string) (*signingKey, error) {
+	keys, err := ks.loadKeys(signingKeyCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	if key := currentKey(keys, time.Now()); key != nil && key.Algorithm == algorithm {
+		return key, nil
+	}
+
+	signingKeyCreateMu.Lock()
+	defer signingKeyCreateMu.Unlock()
+	// 其他请求可能已经生成
+	if key := currentKey(signingKeys.snapshot(), time.Now()); key != nil && key.Algorithm == algorithm {
+		return key, nil
+	}
+	return ks.createKey(context.Background(), algorithm, time.Now())
+}
+
+// verificationKey 按 kid 查找可用于验证的密钥，缓存时间超过 maxAge 时先从数据库刷新
+func (ks *SigningKeyService) verificationKey(kid string, maxAge time.Duration) (*signingKey, error) {
+	keys, err := ks.loadKeys(maxAge)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range publishedKeys(keys, time.Now()) {
+		if key.ID == kid {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+// currentKey 最新生效的密钥
+func currentKey(keys []*signingKey, now time.Time) *signingKey {
+	var current *signingKey
+	for _, key := range keys {
+		if !key.ActivatesAt.After(now) {
+			current = key
+		}
+	}
+	return current
+}
+
+// publishedKeys 可用于验证的密钥：尚未生效的、当前使用的，以及被替换后仍在宽限期内的
+func publishedKeys(keys []*signingKey, now time.Time) []*signingKey {
+	grace := time.Duration(config.GetConfig().JWT.GraceHours) * time.Hour
+	var published []*signingKey
+	for i, key := range keys {
+		// 被下一个已生效的密钥替换的时间
+		var retiredAt time.Time
+		for _, next := range keys[i+1:] {
+			if !next.ActivatesAt.After(now) {
+				retiredAt = next.ActivatesAt
+				break
+			}
+		}
+		if retiredAt.IsZero() || now.Before(retiredAt.Add(grace)) {
+			published = append(published, key)
+		}
+	}
+	return published
+}
+
+// loadKeys 返回缓存的密钥列表，缓存时间超过 maxAge 时从数据库刷新
+func (ks *SigningKeyService) loadKeys(maxAge time.Duration) ([]*signingKey, error) {
+	signingKeys.mu.Lock()
+	defer signingKeys.mu.Unlock()
+
+	if time.Since(signingKeys.loadedAt) < maxAge {
+		return signingKeys.keys, nil
+	}
+
+	var rows []models.SigningKey
+	if err := ks.db.Order("activates_at ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// 已解析过的密钥直接复用
+	parsed := make(map[string]*signingKey, len(signingKeys.keys))
+	for _, key := range signingKeys.keys {
+		parsed[key.ID] = key
+	}
+	keys := make([]*signingKey, 0, len(rows))
+	for _, row := range rows {
+		if key, ok := parsed[row.ID]; ok {
+			keys = append(keys, &signingKey{SigningKey: row, private: key.private, public: key.public})
+			continue
+		}
+		key, err := parseSigningKey(row)
+		if err != nil {
+			return nil, fmt.Errorf("签名密钥 %s 无效: %v", row.ID, err)
+		}
+		if !strings.HasPrefix(row.PrivateKey, privateKeyPrefix) {
+			// 旧版本以明文保存的私钥，加载时加密
+			if err := ks.encryptStoredKey(key); err != nil {
+				return nil, fmt.Errorf("加密签名密钥 %s 失败: %v", row.ID, err)
+			}
+			log.Printf("签名密钥 %s 的私钥已加密保存", row.ID)
+		}
+		keys = append(keys, key)
+	}
+
+	signingKeys.keys = keys
+	signingKeys.loadedAt = time.Now()
+	return keys, nil
+}
+
+// createKey 生成并保存新的密钥对，activatesAt 之后用于签名
+func (ks *SigningKeyService) createKey(ctx context.Context, algorithm string, activatesAt time.Time) (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, config.GetConfig().JWT.RSABits)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("不支持的签名算法: %s", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	kid := make([]byte, 16)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+
+	row := models.SigningKey{
+		ID:          base64.RawURLEncoding.EncodeToString(kid),
+		Algorithm:   algorithm,
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		ActivatesAt: activatesAt,
+	}
+	if row.PrivateKey, err = sealPrivateKey(row, privateDER); err != nil {
+		return nil, err
+	}
+	if err := ks.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return nil, err
+	}
+
+	// 缓存的列表可能正在被读取，复制后再修改
+	key := &signingKey{SigningKey: row, private: private, public: private.Public()}
+	signingKeys.mu.Lock()
+	keys := append(append([]*signingKey(nil), signingKeys.keys...), key)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].ActivatesAt.Before(keys[j].ActivatesAt)
+	})
+	signingKeys.keys = keys
+	signingKeys.mu.Unlock()
+	return key, nil
+}
+
+// encryptStoredKey 加密保存旧版本的明文私钥
+func (ks *SigningKeyService) encryptStoredKey(key *signingKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealPrivateKey(key.SigningKey, der)
+	if err != nil {
+		return err
+	}
+	if err := ks.db.Model(&models.SigningKey{}).Where("id = ?", key.ID).
+		UpdateColumn("private_key", sealed).Error; err != nil {
+		return err
+	}
+	key.PrivateKey = sealed
+	return nil
+}
+
+// signingKeyAAD 密文绑定 kid 和算法，防止数据库中的私钥被调换到其他记录
+func signingKeyAAD(row models.SigningKey) []byte {
+	return []byte(row.ID + ":" + row.Algorithm)
+}
+
+// sealPrivateKey 使用 jwt.key_secret 加密 PKCS#8 私钥
+func sealPrivateKey(row models.SigningKey, der []byte) (string, error) {
+	sealed, err := config.Seal(der, signingKeyAAD(row), config.GetConfig().JWT.KeySecret)
+	if err != nil {
+		return "", fmt.Errorf("加密私钥失败: %v", err)
+	}
+	return privateKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openPrivateKey 返回数据库中私钥的 PKCS#8 数据，兼容旧版本的明文 PEM
+func openPrivateKey(row models.SigningKey) ([]byte, error) {
+	if encoded, ok := strings.CutPrefix(row.PrivateKey, privateKeyPrefix); ok {
+		sealed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.New("私钥密文格式错误")
+		}
+		der, err := config.Open(sealed, signingKeyAAD(row), config.GetConfig().JWT.KeySecret)
+		if err != nil {
+			return nil, fmt.Errorf("%v（jwt.key_secret 与加密时不同）", err)
+		}
+		return der, nil
+	}
+	block, _ := pem.Decode([]byte(row.PrivateKey))
+	if block == nil {
+		return nil, errors.New("私钥不是 PEM 格式")
+	}
+	return block.Bytes, nil
+}
+
+// parseSigningKey 解析数据库中保存的密钥
+func parseSigningKey(row models.SigningKey) (*signingKey, error) {
+	der, err := openPrivateKey(row)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("不支持的私钥类型")
+	}
+
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		if row.Algorithm != AlgorithmRS256 {
+			return nil, errors.New("私钥类型与算法不符")
+		}
+	case ed25519.PrivateKey:
+		if row.Algorithm != AlgorithmEdDSA {
+			return nil, errors.New("私钥类型与算法不符")
+		}
+	default:
+		return nil, errors.New("不支持的私钥类型")
+	}
+	return &signingKey{SigningKey: row, private: private, public: private.Public()}, nil
+}
+
+// jwk 转换为 JWK
+func (k *signingKey) jwk() JWK {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Algorithm}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
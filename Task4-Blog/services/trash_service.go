@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTrashPostDeleted 评论所属的文章也在回收站中
+	ErrTrashPostDeleted = errors.New("评论所属的文章已删除，请先恢复文章")
+	// ErrTrashParentDeleted 回复的父评论也在回收站中
+	ErrTrashParentDeleted = errors.New("父评论已删除，请先恢复父评论")
+)
+
+// trashPurgeBatch 后台清理每批永久删除的记录数
+const trashPurgeBatch = 100
+
+// TrashedPost 回收站中的文章
+type TrashedPost struct {
+	models.PostResponse
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"` // 自动永久删除的时间，trash.retention_days 为 0 时为空
+}
+
+// TrashedComment 回收站中的评论
+type TrashedComment struct {
+	models.CommentResponse
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"` // 自动永久删除的时间，trash.retention_days 为 0 时为空
+}
+
+// TrashService 回收站服务：查看、恢复和永久删除已删除的文章和评论。
+// userID 参数为 0 时不限制作者（管理员），否则只能操作该用户自己的内容
+type TrashService struct {
+	db    *gorm.DB
+	audit *AuditService
+}
+
+// NewTrashService 创建回收站服务实例
+func NewTrashService() *TrashService {
+	return &TrashService{
+		db:    database.GetDB(),
+		audit: NewAuditService(),
+	}
+}
+
+// GetDeletedPosts 分页获取回收站中的文章，最近删除的在前
+func (ts *TrashService) GetDeletedPosts(userID uint, page, pageSize int) ([]TrashedPost, int64, error) {
+	var posts []models.Post
+	var total int64
+
+	query := ts.db.Unscoped().Model(&models.Post{}).Where("deleted_at IS NOT NULL")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("User").
+		Order("deleted_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&posts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	trashed := make([]TrashedPost, len(posts))
+	for i := range posts {
+		deletedAt := posts[i].DeletedAt.Time
+		trashed[i] = TrashedPost{PostResponse: posts[i].ToResponse(), DeletedAt: deletedAt, PurgeAt: purgeTime(deletedAt)}
+	}
+	return trashed, total, nil
+}
+
+// GetDeletedComments 分页获取回收站中的评论，最近删除的在前
+func (ts *TrashService) GetDeletedComments(userID uint, page, pageSize int) ([]TrashedComment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	query := ts.db.Unscoped().Model(&models.Comment{}).Where("deleted_at IS NOT NULL")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("User").
+		Order("deleted_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	trashed := make([]TrashedComment, len(comments))
+	for i := range comments {
+		deletedAt := comments[i].DeletedAt.Time
+		trashed[i] = TrashedComment{CommentResponse: comments[i].ToResponse(), DeletedAt: deletedAt, PurgeAt: purgeTime(deletedAt)}
+	}
+	return trashed, total, nil
+}
+
+// RestorePost 从回收站恢复文章，作者的文章数量随之恢复
+func (ts *TrashService) RestorePost(ctx context.Context, userID, postID uint) (*models.Post, error) {
+	post, err := ts.deletedPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.db.Transaction(func(tx *gorm.DB) error {
+		if err := post.Restore(tx); err != nil {
+			return err
+		}
+		return ts.audit.Record(ctx, tx, models.AuditPostRestore, models.AuditTargetPost, post.ID, nil, postSnapshot(post))
+	}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// RestoreComment 从回收站恢复评论，文章的评论数量随之恢复。
+// 所属文章或父评论仍在回收站中时不能恢复
+func (ts *TrashService) RestoreComment(ctx context.Context, userID, commentID uint) (*models.Comment, error) {
+	comment, err := ts.deletedComment(userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := ts.db.Model(&models.Post{}).Where("id = ?", comment.PostID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrTrashPostDeleted
+	}
+	if comment.ParentID != nil {
+		if err := ts.db.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrTrashParentDeleted
+		}
+	}
+
+	if err := ts.db.Transaction(func(tx *gorm.DB) error {
+		if err := comment.Restore(tx); err != nil {
+			return err
+		}
+		return ts.audit.Record(ctx, tx, models.AuditCommentRestore, models.AuditTargetComment, comment.ID, nil, commentSnapshot(comment))
+	}); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// PurgePost 永久删除回收站中的文章，连同文章下的所有评论和标签关联
+func (ts *TrashService) PurgePost(ctx context.Context, userID, postID uint) error {
+	post, err := ts.deletedPost(userID, postID)
+	if err != nil {
+		return err
+	}
+
+	return ts.db.Transaction(func(tx *gorm.DB) error {
+		if err := purgePosts(tx, []uint{post.ID}); err != nil {
+			return err
+		}
+		return ts.audit.Record(ctx, tx, models.AuditPostPurge, models.AuditTargetPost, post.ID, postSnapshot(post), nil)
+	})
+}
+
+// PurgeComment 永久删除回收站中的评论。管理员（userID 为 0）连同它的所有回复一并删除；
+// 作者只能删除自己的评论，回复可能属于其他用户，保留并上移一层
+func (ts *TrashService) PurgeComment(ctx context.Context, userID, commentID uint) error {
+	comment, err := ts.deletedComment(userID, commentID)
+	if err != nil {
+		return err
+	}
+
+	purge := purgeComments
+	if userID != 0 {
+		purge = purgeCommentsKeepReplies
+	}
+	return ts.db.Transaction(func(tx *gorm.DB) error {
+		if err := purge(tx, []uint{comment.ID}); err != nil {
+			return err
+		}
+		return ts.audit.Record(ctx, tx, models.AuditCommentPurge, models.AuditTargetComment, comment.ID, commentSnapshot(comment), nil)
+	})
+}
+
+// PurgeExpired 永久删除在回收站中超过 trash.retention_days 天的文章和评论，为 0 时不清除。
+// 与作者永久删除一样，过期评论的回复保留并上移一层
+func (ts *TrashService) PurgeExpired(ctx context.Context) error {
+	days := config.GetConfig().Trash.RetentionDays
+	if days <= 0 {
+		return nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	db := ts.db.WithContext(ctx)
+
+	for _, purge := range []struct {
+		model interface{}
+		run   func(tx *gorm.DB, ids []uint) error
+	}{
+		{&models.Post{}, purgePosts},
+		{&models.Comment{}, purgeCommentsKeepReplies},
+	} {
+		for {
+			var ids []uint
+			if err := db.Unscoped().Model(purge.model).
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+				Order("id").
+				Limit(trashPurgeBatch).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				break
+			}
+			if err := db.Transaction(func(tx *gorm.DB) error {
+				return purge.run(tx, ids)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deletedPost 获取回收站中的文章
+func (ts *TrashService) deletedPost(userID, postID uint) (*models.Post, error) {
+	var post models.Post
+	query := ts.db.Unscoped().Preload("User").Where("id = ? AND deleted_at IS NOT NULL", postID)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.First(&post).Error; err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// deletedComment 获取回收站中的评论
+func (ts *TrashService) deletedComment(userID, commentID uint) (*models.Comment, error) {
+	var comment models.Comment
+	query := ts.db.Unscoped().Preload("User").Where("id = ? AND deleted_at IS NOT NULL", commentID)
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.First(&comment).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// purgeTime 删除时间对应的自动永久删除时间
+func purgeTime(deletedAt time.Time) *time.Time {
+	days := config.GetConfig().Trash.RetentionDays
+	if days <= 0 {
+		return nil
+	}
+	t := deletedAt.AddDate(0, 0, days)
+	return &t
+}
+
+// purgePosts 永久删除文章及其评论（含修改记录）和标签关联。文章移入回收站时已经更新过作者的文章数量，这里跳过删除钩子
+func purgePosts(tx *gorm.DB, postIDs []uint) error {
+	tx = tx.Unscoped().Session(&gorm.Session{SkipHooks: true})
+	// 先断开回复关系，避免逐行删除时违反 parent_id 外键
+	if err := tx.Model(&models.Comment{}).Where("post_id IN ?", postIDs).Update("parent_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("comment_id IN (?)", tx.Model(&models.Comment{}).Select("id").Where("post_id IN ?", postIDs)).
+		Delete(&models.CommentRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id IN ?", postIDs).Delete(&models.Comment{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("post_id IN ?", postIDs).Delete(&models.PostTag{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", postIDs).Delete(&models.Post{}).Error
+}
+
+// purgeComments 永久删除评论及其所有层级的回复，跳过删除钩子。
+// 回收站中的评论已经减过文章的评论数量，仍然可见的回复在这里减去
+func purgeComments(tx *gorm.DB, commentIDs []uint) error {
+	tx = tx.Unscoped().Session(&gorm.Session{SkipHooks: true})
+
+	var roots []models.Comment
+	if err := tx.Select("id", "post_id", "path").Where("id IN ?", commentIDs).Find(&roots).Error; err != nil {
+		return err
+	}
+	// 所有层级的回复的路径都以 祖先路径/评论ID/ 开头
+	ids := append([]uint{}, commentIDs...)
+	for _, root := range roots {
+		var replies []uint
+		if err := tx.Model(&models.Comment{}).
+			Where("post_id = ? AND path LIKE ?", root.PostID, fmt.Sprintf("%s%d/%%", root.Path, root.ID)).
+			Pluck("id", &replies).Error; err != nil {
+			return err
+		}
+		ids = append(ids, replies...)
+	}
+
+	var visible []struct {
+		PostID uint
+		Count  int
+	}
+	if err := tx.Model(&models.Comment{}).
+		Select("post_id, COUNT(*) AS count").
+		Where("id IN ? AND deleted_at IS NULL AND is_approved = ?", ids, true).
+		Group("post_id").
+		Scan(&visible).Error; err != nil {
+		return err
+	}
+	for _, v := range visible {
+		if err := tx.Model(&models.Post{}).Where("id = ?", v.PostID).
+			Update("comment_count", gorm.Expr("comment_count - ?", v.Count)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.Comment{}).Where("id IN ?", ids).Update("parent_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("comment_id IN ?", ids).Delete(&models.CommentRevision{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", ids).Delete(&models.Comment{}).Error
+}
+
+// purgeCommentsKeepReplies 只永久删除回收站中的评论本身，跳过删除钩子。
+// 直接回复改挂到被删除评论的父评论下（顶级评论的回复成为顶级评论），所有层级回复的路径随之缩短；
+// 回收站中的评论已经减过父评论的回复数量，上移的可见回复计入新的父评论
+func purgeCommentsKeepReplies(tx *gorm.DB, commentIDs []uint) error {
+	tx = tx.Unscoped().Session(&gorm.Session{SkipHooks: true})
+
+	// 逐条处理，同一批中有祖先和后代时，后代的路径已经被前面的处理更新
+	for _, id := range commentIDs {
+		var comment models.Comment
+		if err := tx.Select("id", "post_id", "parent_id", "path").First(&comment, id).Error; err != nil {
+			return err
+		}
+
+		prefix := fmt.Sprintf("%s%d/", comment.Path, comment.ID)
+		var paths []string
+		if err := tx.Model(&models.Comment{}).
+			Where("post_id = ? AND path LIKE ?", comment.PostID, prefix+"%").
+			Distinct().Pluck("path", &paths).Error; err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if err := tx.Model(&models.Comment{}).Where("post_id = ? AND path = ?", comment.PostID, path).
+				UpdateColumn("path", comment.Path+strings.TrimPrefix(path, prefix)).Error; err != nil {
+				return err
+			}
+		}
+
+		if comment.ParentID != nil {
+			var visible int64
+			if err := tx.Model(&models.Comment{}).
+				Where("parent_id = ? AND deleted_at IS NULL AND is_approved = ?", comment.ID, true).
+				Count(&visible).Error; err != nil {
+				return err
+			}
+			if visible > 0 {
+				if err := tx.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+					UpdateColumn("reply_count", gorm.Expr("reply_count + ?", visible)).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Model(&models.Comment{}).Where("parent_id = ?", comment.ID).
+			UpdateColumn("parent_id", comment.ParentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", comment.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
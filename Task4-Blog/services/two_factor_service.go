@@ -0,0 +1,344 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"blog-system/config"
+	"blog-system/database"
+	"blog-system/models"
+	"blog-system/utils"
+
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// 两步验证错误
+var (
+	ErrTwoFactorEnabled    = errors.New("两步验证已开启")
+	ErrTwoFactorNotEnabled = errors.New("两步验证未开启")
+	ErrTwoFactorNotPending = errors.New("请先获取验证器密钥")
+	ErrTwoFactorCode       = errors.New("验证码错误")
+	ErrTwoFactorLocked     = errors.New("验证失败次数过多，请稍后再试")
+	ErrTwoFactorRequired   = errors.New("当前角色必须开启两步验证")
+)
+
+// recoveryCodeAlphabet 恢复码字符集（小写，去掉了易混淆的字符）
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"` // 当前角色是否必须开启
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollment 开启两步验证时返回的验证器信息
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`      // base32 密钥，无法扫码时手动输入
+	URI    string `json:"otpauth_uri"` // otpauth:// 地址
+	QRCode string `json:"qr_code"`     // otpauth 地址的二维码（PNG data URI）
+}
+
+// TwoFactorService 两步验证服务
+type TwoFactorService struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorService 创建两步验证服务实例
+func NewTwoFactorService() *TwoFactorService {
+	return &TwoFactorService{
+		db: database.GetDB(),
+	}
+}
+
+// TwoFactorRequired 检查角色是否必须开启两步验证
+func TwoFactorRequired(role string) bool {
+	for _, r := range config.GetConfig().TwoFactor.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStatus 获取用户的两步验证状态
+func (ts *TwoFactorService) GetStatus(userID uint) (*TwoFactorStatus, error) {
+	user, err := ts.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Enabled: user.TwoFactorEnabled, Required: TwoFactorRequired(user.Role)}
+	if user.TwoFactorEnabled {
+		if err := ts.db.Model(&models.RecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Count(&status.RecoveryCodesRemaining).Error; err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginEnrollment 生成新的验证器密钥，确认前两步验证不生效；重复调用会替换未确认的密钥
+func (ts *TwoFactorService) BeginEnrollment(userID uint) (*TwoFactorEnrollment, error) {
+	user, err := ts.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.db.Model(user).Update("totp_secret", secret).Error; err != nil {
+		return nil, err
+	}
+
+	uri := utils.TOTPURI(config.GetConfig().TwoFactor.Issuer, user.Username, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorEnrollment{
+		Secret: secret,
+		URI:    uri,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// ConfirmEnrollment 用验证器生成的验证码确认并开启两步验证，返回恢复码（只返回这一次）
+func (ts *TwoFactorService) ConfirmEnrollment(userID uint, code string) (*models.User, []string, error) {
+	user, err := ts.getUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, nil, ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, nil, ErrTwoFactorNotPending
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now(), 1)
+	if !ok {
+		return nil, nil, ErrTwoFactorCode
+	}
+
+	var codes []string
+	err = ts.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"two_factor_enabled":      true,
+			"totp_last_step":          step,
+			"two_factor_failures":     0,
+			"two_factor_locked_until": nil,
+		}).Error; err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err = ts.getUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, codes, nil
+}
+
+// Disable 关闭两步验证，需要提供验证码或恢复码。角色要求两步验证时不能关闭
+func (ts *TwoFactorService) Disable(userID uint, code string) error {
+	user, err := ts.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if TwoFactorRequired(user.Role) {
+		return ErrTwoFactorRequired
+	}
+	if err := ts.verifyCode(user, code); err != nil {
+		return err
+	}
+
+	return ts.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"two_factor_enabled": false,
+			"totp_secret":        "",
+			"totp_last_step":     0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的恢复码全部失效
+func (ts *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	user, err := ts.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err := ts.verifyCode(user, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = ts.db.Transaction(func(tx *gorm.DB) error {
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	return codes, err
+}
+
+// Verify 登录第二步：校验验证码或恢复码，通过后返回用户
+func (ts *TwoFactorService) Verify(userID uint, code string) (*models.User, error) {
+	user, err := ts.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if !user.TwoFactorEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if err := ts.verifyCode(user, code); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// verifyCode 校验 TOTP 验证码或恢复码。每个时间步的验证码只能使用一次，
+// 连续失败 max_attempts 次后锁定 lockout_minutes 分钟
+func (ts *TwoFactorService) verifyCode(user *models.User, code string) error {
+	cfg := config.GetConfig().TwoFactor
+	now := time.Now()
+	if user.TwoFactorLockedUntil != nil && now.Before(*user.TwoFactorLockedUntil) {
+		return ErrTwoFactorLocked
+	}
+
+	ok, err := ts.useTOTP(user, code, now)
+	if err == nil && !ok {
+		ok, err = ts.useRecoveryCode(user.ID, code, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		return ts.db.Model(&models.User{}).Where("id = ? AND two_factor_failures > 0", user.ID).
+			Update("two_factor_failures", 0).Error
+	}
+
+	if err := ts.db.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("two_factor_failures", gorm.Expr("two_factor_failures + ?", 1)).Error; err != nil {
+		return err
+	}
+	if err := ts.db.Model(&models.User{}).
+		Where("id = ? AND two_factor_failures >= ?", user.ID, cfg.MaxAttempts).
+		Updates(map[string]interface{}{
+			"two_factor_failures":     0,
+			"two_factor_locked_until": now.Add(time.Duration(cfg.LockoutMinutes) * time.Minute),
+		}).Error; err != nil {
+		return err
+	}
+	return ErrTwoFactorCode
+}
+
+// useTOTP 校验验证码，并原子地记录已使用的时间步
+func (ts *TwoFactorService) useTOTP(user *models.User, code string, now time.Time) (bool, error) {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, now, 1)
+	if !ok {
+		return false, nil
+	}
+	result := ts.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", user.ID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// useRecoveryCode 使用一个未用过的恢复码
+func (ts *TwoFactorService) useRecoveryCode(userID uint, code string, now time.Time) (bool, error) {
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != 10 {
+		return false, nil
+	}
+	result := ts.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRecoveryCode(normalized)).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (ts *TwoFactorService) getUser(userID uint) (*models.User, error) {
+	var user models.User
+	if err := ts.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// replaceRecoveryCodes 删除用户的旧恢复码并生成新的，数据库只保存哈希
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	n := config.GetConfig().TwoFactor.RecoveryCodes
+	codes := make([]string, n)
+	records := make([]models.RecoveryCode, n)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = models.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(normalizeRecoveryCode(code))}
+	}
+	if n > 0 {
+		if err := tx.Create(&records).Error; err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+// newRecoveryCode 生成 xxxxx-xxxxx 格式的恢复码
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = recoveryCodeAlphabet[n.Int64()]
+	}
+	return string(buf[:5]) + "-" + string(buf[5:]), nil
+}
+
+// normalizeRecoveryCode 去掉分隔符和空白并转为小写
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
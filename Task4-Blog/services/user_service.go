@@ -4,6 +4,8 @@ import (
 	"blog-system/database"
 	"blog-system/models"
 	"blog-system/utils"
+	"context"
+	"log"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,13 +13,15 @@ import (
 
 // UserService 用户服务
 type UserService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	audit *AuditService
 }
 
 // NewUserService 创建用户服务实例
 func NewUserService() *UserService {
 	return &UserService{
-		db: database.GetDB(),
+		db:    database.GetDB(),
+		audit: NewAuditService(),
 	}
 }
 
@@ -91,6 +95,24 @@ func (us *UserService) UpdateLastLogin(userID uint, loginTime *time.Time) error
 	return us.db.Model(&models.User{}).Where("id = ?", userID).Update("last_login", loginTime).Error
 }
 
+// RecordLogin 登录完成（签发 token）时调用：更新最后登录时间并记入审计日志，method 为登录方式。
+// 登录已经完成，写入失败只记录日志
+func (us *UserService) RecordLogin(ctx context.Context, user *models.User, method string) {
+	actor := ActorFrom(ctx)
+	actor.UserID = user.ID
+	actor.Username = user.Username
+
+	now := time.Now()
+	if err := us.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Update("last_login", &now).Error; err != nil {
+			return err
+		}
+		return us.audit.Record(WithActor(ctx, actor), tx, models.AuditUserLogin, models.AuditTargetUser, user.ID, nil, map[string]string{"method": method})
+	}); err != nil {
+		log.Printf("记录用户 %d 的登录失败: %v", user.ID, err)
+	}
+}
+
 // GetUserPosts 获取用户的文章列表
 func (us *UserService) GetUserPosts(userID uint, page, pageSize int) ([]models.PostResponse, int64, error) {
 	var posts []models.Post
@@ -168,4 +190,64 @@ func (us *UserService) ChangePassword(userID uint, oldPassword, newPassword stri
 
 	// 更新密码
 	return us.db.Model(&user).Update("password", hashedPassword).Error
-}
\ No newline at end of file
+}
+
+// SetUserRole 修改用户角色，有变化时记入审计日志
+func (us *UserService) SetUserRole(ctx context.Context, userID uint, role string) error {
+	var user models.User
+	if err := us.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	previous := user.Role
+	if previous == role {
+		return nil
+	}
+
+	return us.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("role", role).Error; err != nil {
+			return err
+		}
+		return us.audit.Record(ctx, tx, models.AuditUserRole, models.AuditTargetUser, user.ID,
+			map[string]string{"role": previous}, map[string]string{"role": role})
+	})
+}
+
+// SetUserActive 封禁或解封用户，有变化时记入审计日志
+func (us *UserService) SetUserActive(ctx context.Context, userID uint, active bool) error {
+	var user models.User
+	if err := us.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.IsActive == active {
+		return nil
+	}
+
+	action := models.AuditUserBan
+	if active {
+		action = models.AuditUserUnban
+	}
+	return us.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("is_active", active).Error; err != nil {
+			return err
+		}
+		return us.audit.Record(ctx, tx, action, models.AuditTargetUser, user.ID,
+			map[string]bool{"is_active": !active}, map[string]bool{"is_active": active})
+	})
+}
+
+// ResetPassword 重置密码（管理员功能，无需旧密码）
+func (us *UserService) ResetPassword(userID uint, newPassword string) error {
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	result := us.db.Model(&models.User{}).Where("id = ?", userID).Update("password", hashedPassword)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
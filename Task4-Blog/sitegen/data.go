@@ -0,0 +1,237 @@
+package sitegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"blog-system/models"
+
+	"gorm.io/gorm"
+)
+
+// Author 作者页数据
+type Author struct {
+	ID       uint
+	Username string
+	Bio      string
+	Avatar   string
+	URL      string
+	Posts    []*Post
+}
+
+// Tag 标签页数据
+type Tag struct {
+	Name  string
+	Slug  string
+	Color string
+	URL   string
+	Posts []*Post
+}
+
+// Comment 文章页中的评论
+type Comment struct {
+	ID        uint
+	Author    string
+	Content   string
+	CreatedAt time.Time
+	Replies   []*Comment
+}
+
+// Post 文章页数据
+type Post struct {
+	ID           uint
+	Title        string
+	Slug         string
+	Summary      string
+	Content      string
+	HTML         template.HTML
+	URL          string
+	PublishedAt  time.Time
+	UpdatedAt    time.Time
+	Author       *Author
+	Tags         []*Tag
+	Comments     []*Comment
+	CommentCount int
+
+	// fingerprint 影响页面输出的全部内容的摘要，用于增量构建
+	fingerprint string
+}
+
+// site 构建所需的全部数据
+type site struct {
+	Posts   []*Post
+	Authors []*Author
+	Tags    []*Tag
+}
+
+// loadSite 读取所有已发布的公开文章及其作者、标签和已审核评论
+func loadSite(db *gorm.DB) (*site, error) {
+	var posts []models.Post
+	if err := db.Preload("User").Preload("Tags").
+		Where("status = ? AND is_public = ?", models.PostStatusPublished, true).
+		Order("published_at DESC, id DESC").
+		Find(&posts).Error; err != nil {
+		return nil, fmt.Errorf("查询文章失败: %v", err)
+	}
+
+	s := &site{}
+	authors := make(map[uint]*Author)
+	tags := make(map[uint]*Tag)
+	byID := make(map[uint]*Post, len(posts))
+	ids := make([]uint, 0, len(posts))
+
+	for i := range posts {
+		p := &posts[i]
+		post := &Post{
+			ID:        p.ID,
+			Title:     p.Title,
+			Slug:      p.Slug,
+			Summary:   p.Summary,
+			Content:   p.Content,
+			URL:       postURL(p),
+			UpdatedAt: p.UpdatedAt,
+		}
+		post.PublishedAt = p.CreatedAt
+		if p.PublishedAt != nil {
+			post.PublishedAt = *p.PublishedAt
+		}
+
+		author, ok := authors[p.UserID]
+		if !ok {
+			author = &Author{
+				ID:       p.User.ID,
+				Username: p.User.Username,
+				Bio:      p.User.Bio,
+				Avatar:   p.User.Avatar,
+				URL:      "/authors/" + url.PathEscape(pathSegment(p.User.Username)) + "/",
+			}
+			authors[p.UserID] = author
+			s.Authors = append(s.Authors, author)
+		}
+		post.Author = author
+		author.Posts = append(author.Posts, post)
+
+		for _, t := range p.Tags {
+			tag, ok := tags[t.ID]
+			if !ok {
+				tag = &Tag{Name: t.Name, Slug: t.Slug, Color: t.Color, URL: "/tags/" + url.PathEscape(pathSegment(t.Slug)) + "/"}
+				tags[t.ID] = tag
+				s.Tags = append(s.Tags, tag)
+			}
+			post.Tags = append(post.Tags, tag)
+			tag.Posts = append(tag.Posts, post)
+		}
+
+		s.Posts = append(s.Posts, post)
+		byID[p.ID] = post
+		ids = append(ids, p.ID)
+	}
+
+	if err := loadComments(db, ids, byID); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(s.Authors, func(i, j int) bool { return s.Authors[i].Username < s.Authors[j].Username })
+	sort.Slice(s.Tags, func(i, j int) bool { return s.Tags[i].Slug < s.Tags[j].Slug })
+
+	for _, post := range s.Posts {
+		post.fingerprint = postFingerprint(post)
+	}
+	return s, nil
+}
+
+// loadComments 分批读取已审核评论并组装为评论树
+func loadComments(db *gorm.DB, ids []uint, posts map[uint]*Post) error {
+	const batch = 500
+	for start := 0; start < len(ids); start += batch {
+		end := start + batch
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		var rows []models.Comment
+		if err := db.Preload("User").
+			Where("post_id IN ? AND is_approved = ?", ids[start:end], true).
+			Order("created_at ASC, id ASC").
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("查询评论失败: %v", err)
+		}
+
+		nodes := make(map[uint]*Comment, len(rows))
+		for _, row := range rows {
+			nodes[row.ID] = &Comment{
+				ID:        row.ID,
+				Author:    row.User.Username,
+				Content:   row.Content,
+				CreatedAt: row.CreatedAt,
+			}
+		}
+		for _, row := range rows {
+			post := posts[row.PostID]
+			node := nodes[row.ID]
+			post.CommentCount++
+			if row.ParentID != nil {
+				if parent, ok := nodes[*row.ParentID]; ok {
+					parent.Replies = append(parent.Replies, node)
+					continue
+				}
+			}
+			post.Comments = append(post.Comments, node)
+		}
+	}
+	return nil
+}
+
+// postFingerprint 计算文章页内容摘要：标题、正文、作者、标签或评论变化时摘要随之变化
+func postFingerprint(p *Post) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%s\x00%s\x00%s\x00%s\x00%d\x00%d\x00%d\x00%s\x00%s\x00%s\x00%s",
+		p.ID, p.Title, p.Slug, p.Summary, p.Content,
+		p.PublishedAt.Unix(), p.UpdatedAt.Unix(), p.CommentCount,
+		p.Author.Username, p.Author.Bio, p.Author.Avatar, p.Author.URL)
+	for _, t := range p.Tags {
+		fmt.Fprintf(h, "\x00tag:%s:%s:%s", t.Name, t.Slug, t.Color)
+	}
+	var walk func(comments []*Comment)
+	walk = func(comments []*Comment) {
+		for _, c := range comments {
+			fmt.Fprintf(h, "\x00comment:%d:%s:%s:%d", c.ID, c.Author, c.Content, len(c.Replies))
+			walk(c.Replies)
+		}
+	}
+	walk(p.Comments)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// postURL 文章页路径，slug 为空时使用 ID
+func postURL(p *models.Post) string {
+	if p.Slug == "" {
+		return fmt.Sprintf("/posts/%d/", p.ID)
+	}
+	return "/posts/" + url.PathEscape(pathSegment(p.Slug)) + "/"
+}
+
+// pathSegment 将 slug 或用户名转换为安全的路径段，避免出现目录穿越或隐藏文件
+func pathSegment(s string) string {
+	var b strings.Builder
+	for _, ch := range s {
+		switch {
+		case unicode.IsLetter(ch), unicode.IsDigit(ch), ch == '-', ch == '_', ch == '.':
+			b.WriteRune(ch)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	seg := strings.TrimLeft(b.String(), ".")
+	if seg == "" {
+		return "-"
+	}
+	return seg
+}
@@ -0,0 +1,213 @@
+package sitegen
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// RSS 2.0 结构
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description"`
+}
+
+// Atom 结构
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string       `xml:"title"`
+	ID        string       `xml:"id"`
+	Link      atomLink     `xml:"link"`
+	Published string       `xml:"published"`
+	Updated   string       `xml:"updated"`
+	Author    atomAuthor   `xml:"author"`
+	Category  []atomCat    `xml:"category"`
+	Summary   atomTextNode `xml:"summary"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCat struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomTextNode struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// sitemap 结构
+type urlSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// feedPages 生成 RSS、Atom 和 sitemap
+func (b *builder) feedPages(s *site) []page {
+	latest := s.Posts
+	if len(latest) > b.opts.FeedSize {
+		latest = latest[:b.opts.FeedSize]
+	}
+
+	feedKeys := []string{"feed"}
+	for _, post := range latest {
+		feedKeys = append(feedKeys, post.fingerprint)
+	}
+	feedFingerprint := b.fingerprint(feedKeys...)
+
+	// sitemap 覆盖所有文章、作者和标签页
+	sitemapKeys := []string{"sitemap"}
+	for _, post := range s.Posts {
+		sitemapKeys = append(sitemapKeys, post.URL, fmt.Sprint(post.UpdatedAt.Unix()))
+	}
+	for _, author := range s.Authors {
+		sitemapKeys = append(sitemapKeys, author.URL)
+	}
+	for _, tag := range s.Tags {
+		sitemapKeys = append(sitemapKeys, tag.URL)
+	}
+
+	return []page{
+		{path: "feed.xml", fingerprint: feedFingerprint, render: func(w io.Writer) error { return b.writeRSS(w, latest) }},
+		{path: "atom.xml", fingerprint: feedFingerprint, render: func(w io.Writer) error { return b.writeAtom(w, latest) }},
+		{path: "sitemap.xml", fingerprint: b.fingerprint(sitemapKeys...), render: func(w io.Writer) error { return b.writeSitemap(w, s) }},
+	}
+}
+
+// writeRSS 输出 RSS 2.0
+func (b *builder) writeRSS(w io.Writer, posts []*Post) error {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       b.opts.Title,
+			Link:        b.abs("/"),
+			Description: b.opts.Description,
+		},
+	}
+	if len(posts) > 0 {
+		feed.Channel.LastBuildDate = posts[0].PublishedAt.UTC().Format(time.RFC1123Z)
+	}
+	for _, post := range posts {
+		link := b.abs(post.URL)
+		item := rssItem{
+			Title:       post.Title,
+			Link:        link,
+			GUID:        link,
+			PubDate:     post.PublishedAt.UTC().Format(time.RFC1123Z),
+			Description: post.Summary,
+		}
+		for _, tag := range post.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return writeXML(w, feed)
+}
+
+// writeAtom 输出 Atom
+func (b *builder) writeAtom(w io.Writer, posts []*Post) error {
+	home := b.abs("/")
+	feed := atomFeed{
+		Title: b.opts.Title,
+		ID:    home,
+		Links: []atomLink{{Href: home}, {Href: b.abs("/atom.xml"), Rel: "self"}},
+	}
+
+	var updated time.Time
+	for _, post := range posts {
+		if post.UpdatedAt.After(updated) {
+			updated = post.UpdatedAt
+		}
+		link := b.abs(post.URL)
+		entry := atomEntry{
+			Title:     post.Title,
+			ID:        link,
+			Link:      atomLink{Href: link},
+			Published: post.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:   post.UpdatedAt.UTC().Format(time.RFC3339),
+			Author:    atomAuthor{Name: post.Author.Username, URI: b.abs(post.Author.URL)},
+			Summary:   atomTextNode{Type: "text", Body: post.Summary},
+		}
+		for _, tag := range post.Tags {
+			entry.Category = append(entry.Category, atomCat{Term: tag.Name})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+	return writeXML(w, feed)
+}
+
+// writeSitemap 输出 sitemap.xml
+func (b *builder) writeSitemap(w io.Writer, s *site) error {
+	set := urlSet{}
+	set.URLs = append(set.URLs, sitemapURL{Loc: b.abs("/")})
+	for _, post := range s.Posts {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     b.abs(post.URL),
+			LastMod: post.UpdatedAt.UTC().Format("2006-01-02"),
+		})
+	}
+	for _, author := range s.Authors {
+		set.URLs = append(set.URLs, sitemapURL{Loc: b.abs(author.URL)})
+	}
+	set.URLs = append(set.URLs, sitemapURL{Loc: b.abs("/tags/")})
+	for _, tag := range s.Tags {
+		set.URLs = append(set.URLs, sitemapURL{Loc: b.abs(tag.URL)})
+	}
+	return writeXML(w, set)
+}
+
+// writeXML 输出带 XML 声明的缩进文档
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
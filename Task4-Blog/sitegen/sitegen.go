@@ -0,0 +1,504 @@
+package sitegen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"gorm.io/gorm"
+)
+
+// generatorVersion 生成逻辑变化时递增，使旧的构建清单全部失效
+const generatorVersion = 1
+
+// manifestName 构建清单文件名，记录每个页面的内容摘要
+const manifestName = ".build-manifest.json"
+
+//go:embed themes/default
+var defaultTheme embed.FS
+
+// Options 构建选项
+type Options struct {
+	OutputDir   string
+	BaseURL     string
+	Title       string
+	Description string
+	Theme       string // 主题目录，为空时使用内置主题
+	PageSize    int
+	FeedSize    int
+	Full        bool // 忽略构建清单，重新生成全部页面
+}
+
+// Stats 构建结果统计
+type Stats struct {
+	Posts    int
+	Pages    int // 本次构建的页面总数
+	Rendered int // 重新生成的页面数
+	Skipped  int // 内容未变化而跳过的页面数
+	Removed  int // 删除的过期页面数
+	Duration time.Duration
+}
+
+// SiteInfo 模板中可用的站点信息
+type SiteInfo struct {
+	Title       string
+	Description string
+	BaseURL     string
+}
+
+// Pagination 分页信息
+type Pagination struct {
+	Page       int
+	TotalPages int
+	PrevURL    string
+	NextURL    string
+}
+
+// PageData 模板数据
+type PageData struct {
+	Site       SiteInfo
+	Title      string
+	URL        string
+	Post       *Post
+	Posts      []*Post
+	Author     *Author
+	Tag        *Tag
+	Tags       []*Tag
+	Pagination *Pagination
+}
+
+// page 一个待输出的文件
+type page struct {
+	path        string // 相对输出目录的文件路径
+	fingerprint string
+	render      func(w io.Writer) error
+}
+
+// manifest 构建清单
+type manifest struct {
+	Version int               `json:"version"`
+	BuiltAt time.Time         `json:"built_at"`
+	Pages   map[string]string `json:"pages"`
+}
+
+// builder 一次构建的上下文
+type builder struct {
+	opts      Options
+	theme     fs.FS
+	templates map[string]*template.Template
+	origin    string // 站点协议和域名，如 https://example.com
+	basePath  string // 站点部署的子路径，如 /blog
+	global    string // 主题和站点配置的摘要，变化时所有页面重新生成
+	markdown  goldmark.Markdown
+	policy    *bluemonday.Policy
+}
+
+// Build 从数据库生成静态站点。默认增量构建：只重新生成内容摘要与上次构建不同的页面，
+// 并删除已不存在的文章、作者和标签对应的页面
+func Build(db *gorm.DB, opts Options) (*Stats, error) {
+	start := time.Now()
+	if opts.OutputDir == "" {
+		return nil, errors.New("未指定输出目录")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 10
+	}
+	if opts.FeedSize <= 0 {
+		opts.FeedSize = 20
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
+	b, err := newBuilder(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := loadSite(db)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := b.pages(s)
+	if err != nil {
+		return nil, err
+	}
+
+	old := b.readManifest()
+	next := manifest{Version: generatorVersion, BuiltAt: time.Now(), Pages: make(map[string]string, len(pages))}
+	stats := &Stats{Posts: len(s.Posts), Pages: len(pages)}
+
+	for _, p := range pages {
+		next.Pages[p.path] = p.fingerprint
+		if !opts.Full && old.Pages[p.path] == p.fingerprint && b.exists(p.path) {
+			stats.Skipped++
+			continue
+		}
+		if err := b.write(p); err != nil {
+			return nil, fmt.Errorf("生成 %s 失败: %v", p.path, err)
+		}
+		stats.Rendered++
+	}
+
+	for p := range old.Pages {
+		if _, ok := next.Pages[p]; ok {
+			continue
+		}
+		if err := b.remove(p); err != nil {
+			log.Printf("删除过期页面 %s 失败: %v", p, err)
+			continue
+		}
+		stats.Removed++
+	}
+
+	if err := b.writeManifest(next); err != nil {
+		return nil, err
+	}
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
+
+// newBuilder 加载主题模板
+func newBuilder(opts Options) (*builder, error) {
+	var theme fs.FS
+	if opts.Theme != "" {
+		info, err := os.Stat(opts.Theme)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("主题目录不存在: %s", opts.Theme)
+		}
+		theme = os.DirFS(opts.Theme)
+	} else {
+		sub, err := fs.Sub(defaultTheme, "themes/default")
+		if err != nil {
+			return nil, err
+		}
+		theme = sub
+	}
+
+	b := &builder{
+		opts:      opts,
+		theme:     theme,
+		templates: make(map[string]*template.Template),
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			// 允许原始 HTML（WordPress 导入的文章为 HTML），输出前统一过滤
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+		policy: bluemonday.UGCPolicy(),
+	}
+	u, err := url.Parse(opts.BaseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("站点地址不正确: %q，应为 https://example.com 形式", opts.BaseURL)
+	}
+	b.origin = u.Scheme + "://" + u.Host
+	b.basePath = strings.TrimRight(u.Path, "/")
+
+	funcs := template.FuncMap{
+		"url":  b.url,
+		"abs":  b.abs,
+		"date": func(t time.Time) string { return t.Format("2006-01-02") },
+	}
+	for _, name := range []string{"index.html", "post.html", "author.html", "tag.html", "tags.html"} {
+		tmpl, err := template.New(name).Funcs(funcs).ParseFS(theme, "layout.html", name)
+		if err != nil {
+			return nil, fmt.Errorf("加载主题模板 %s 失败: %v", name, err)
+		}
+		b.templates[name] = tmpl
+	}
+
+	// 主题文件和站点配置共同决定所有页面的输出
+	h := sha256.New()
+	fmt.Fprintf(h, "v%d\x00%s\x00%s\x00%s\x00%d\x00%d", generatorVersion,
+		opts.BaseURL, opts.Title, opts.Description, opts.PageSize, opts.FeedSize)
+	err = fs.WalkDir(theme, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(theme, p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "\x00%s\x00", p)
+		h.Write(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.global = hex.EncodeToString(h.Sum(nil))
+	return b, nil
+}
+
+// pages 列出所有需要输出的文件
+func (b *builder) pages(s *site) ([]page, error) {
+	var pages []page
+
+	// 文章页
+	for _, post := range s.Posts {
+		pages = append(pages, page{
+			path:        b.filePath(post.URL),
+			fingerprint: b.fingerprint("post", post.fingerprint),
+			render: func(w io.Writer) error {
+				b.renderContent(post)
+				return b.execute(w, "post.html", PageData{Title: post.Title, URL: post.URL, Post: post})
+			},
+		})
+	}
+
+	// 首页及分页
+	pages = append(pages, b.listPages("/", "index.html", s.Posts, func(data *PageData) {})...)
+
+	// 作者页
+	for _, author := range s.Authors {
+		pages = append(pages, b.listPages(author.URL, "author.html", author.Posts, func(data *PageData) {
+			data.Title = author.Username
+			data.Author = author
+		}, author.Username, author.Bio, author.Avatar)...)
+	}
+
+	// 标签页
+	for _, tag := range s.Tags {
+		pages = append(pages, b.listPages(tag.URL, "tag.html", tag.Posts, func(data *PageData) {
+			data.Title = tag.Name
+			data.Tag = tag
+		}, tag.Name, tag.Color)...)
+	}
+
+	// 标签索引
+	tagKeys := make([]string, 0, len(s.Tags))
+	for _, tag := range s.Tags {
+		tagKeys = append(tagKeys, fmt.Sprintf("%s:%s:%d", tag.Name, tag.URL, len(tag.Posts)))
+	}
+	pages = append(pages, page{
+		path:        "tags/index.html",
+		fingerprint: b.fingerprint(append([]string{"tags"}, tagKeys...)...),
+		render: func(w io.Writer) error {
+			return b.execute(w, "tags.html", PageData{Title: "标签", URL: "/tags/", Tags: s.Tags})
+		},
+	})
+
+	// feed 和 sitemap
+	pages = append(pages, b.feedPages(s)...)
+
+	// 主题静态资源
+	assets, err := b.assetPages()
+	if err != nil {
+		return nil, err
+	}
+	pages = append(pages, assets...)
+
+	sort.Slice(pages, func(i, j int) bool { return pages[i].path < pages[j].path })
+	return pages, nil
+}
+
+// listPages 生成分页的文章列表页，第一页位于 base，之后位于 base/page/N/
+func (b *builder) listPages(base, tmpl string, posts []*Post, fill func(*PageData), extra ...string) []page {
+	total := (len(posts) + b.opts.PageSize - 1) / b.opts.PageSize
+	if total == 0 {
+		total = 1
+	}
+
+	pageURL := func(n int) string {
+		if n == 1 {
+			return base
+		}
+		return fmt.Sprintf("%spage/%d/", base, n)
+	}
+
+	pages := make([]page, 0, total)
+	for n := 1; n <= total; n++ {
+		from := (n - 1) * b.opts.PageSize
+		to := from + b.opts.PageSize
+		if to > len(posts) {
+			to = len(posts)
+		}
+		items := posts[from:to]
+
+		pagination := &Pagination{Page: n, TotalPages: total}
+		if n > 1 {
+			pagination.PrevURL = pageURL(n - 1)
+		}
+		if n < total {
+			pagination.NextURL = pageURL(n + 1)
+		}
+
+		keys := append([]string{tmpl, base, fmt.Sprint(n, "/", total)}, extra...)
+		for _, post := range items {
+			keys = append(keys, post.fingerprint)
+		}
+
+		u := pageURL(n)
+		pages = append(pages, page{
+			path:        b.filePath(u),
+			fingerprint: b.fingerprint(keys...),
+			render: func(w io.Writer) error {
+				data := PageData{URL: u, Posts: items, Pagination: pagination}
+				fill(&data)
+				return b.execute(w, tmpl, data)
+			},
+		})
+	}
+	return pages
+}
+
+// assetPages 复制主题 assets 目录下的静态资源
+func (b *builder) assetPages() ([]page, error) {
+	var pages []page
+	err := fs.WalkDir(b.theme, "assets", func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fs.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(b.theme, p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		pages = append(pages, page{
+			path:        p,
+			fingerprint: hex.EncodeToString(sum[:]),
+			render: func(w io.Writer) error {
+				_, err := w.Write(content)
+				return err
+			},
+		})
+		return nil
+	})
+	return pages, err
+}
+
+// renderContent 将 Markdown 正文渲染为过滤后的 HTML
+func (b *builder) renderContent(post *Post) {
+	var buf bytes.Buffer
+	if err := b.markdown.Convert([]byte(post.Content), &buf); err != nil {
+		post.HTML = template.HTML(template.HTMLEscapeString(post.Content))
+		return
+	}
+	post.HTML = template.HTML(b.policy.SanitizeBytes(buf.Bytes()))
+}
+
+// execute 执行主题模板
+func (b *builder) execute(w io.Writer, name string, data PageData) error {
+	data.Site = SiteInfo{Title: b.opts.Title, Description: b.opts.Description, BaseURL: b.opts.BaseURL}
+	return b.templates[name].ExecuteTemplate(w, "layout", data)
+}
+
+// fingerprint 页面摘要
+func (b *builder) fingerprint(keys ...string) string {
+	h := sha256.New()
+	io.WriteString(h, b.global)
+	for _, k := range keys {
+		io.WriteString(h, "\x00")
+		io.WriteString(h, k)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// url 站内链接，站点部署在子路径下时加上路径前缀
+func (b *builder) url(p string) string {
+	return b.basePath + p
+}
+
+// abs 绝对链接
+func (b *builder) abs(p string) string {
+	return b.origin + b.basePath + p
+}
+
+// filePath 将页面 URL 转换为输出文件路径
+func (b *builder) filePath(u string) string {
+	p, err := url.PathUnescape(u)
+	if err != nil {
+		p = u
+	}
+	return strings.TrimPrefix(path.Join(p, "index.html"), "/")
+}
+
+// exists 判断输出文件是否存在
+func (b *builder) exists(p string) bool {
+	_, err := os.Stat(filepath.Join(b.opts.OutputDir, filepath.FromSlash(p)))
+	return err == nil
+}
+
+// write 渲染页面并原子地写入输出目录
+func (b *builder) write(p page) error {
+	target := filepath.Join(b.opts.OutputDir, filepath.FromSlash(p.path))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := p.render(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// remove 删除过期页面及随之变空的目录
+func (b *builder) remove(p string) error {
+	target := filepath.Join(b.opts.OutputDir, filepath.FromSlash(p))
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	root := filepath.Clean(b.opts.OutputDir)
+	for dir := filepath.Dir(target); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// readManifest 读取上次构建的清单，不存在或版本不同时返回空清单
+func (b *builder) readManifest() manifest {
+	empty := manifest{Pages: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(b.opts.OutputDir, manifestName))
+	if err != nil {
+		return empty
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil || m.Version != generatorVersion || m.Pages == nil {
+		return empty
+	}
+	return m
+}
+
+// writeManifest 保存本次构建的清单
+func (b *builder) writeManifest(m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.opts.OutputDir, manifestName), data, 0644)
+}
@@ -0,0 +1,213 @@
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessage 消息不符合 EIP-4361 格式
+var ErrInvalidMessage = errors.New("不是合法的 EIP-4361 消息")
+
+const headerSuffix = " wants you to sign in with your Ethereum account:"
+
+var (
+	addressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	nonceRe   = regexp.MustCompile(`^[A-Za-z0-9]{8,}$`)
+)
+
+// Message EIP-4361 登录消息
+type Message struct {
+	Scheme         string // 可选，如 https
+	Domain         string
+	Address        string
+	Statement      string // 可选
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+
+	raw string // 解析时的原文，签名针对原文验证
+}
+
+// ParseMessage 解析 EIP-4361 消息，字段顺序和格式必须与规范一致
+func ParseMessage(text string) (*Message, error) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	p := &parser{lines: lines}
+	m := &Message{raw: text}
+
+	header, ok := strings.CutSuffix(p.next(), headerSuffix)
+	if !ok || header == "" {
+		return nil, p.fail("应以 \"%s\" 结尾", headerSuffix)
+	}
+	if scheme, domain, found := strings.Cut(header, "://"); found {
+		m.Scheme, m.Domain = scheme, domain
+	} else {
+		m.Domain = header
+	}
+	if m.Domain == "" || strings.ContainsAny(m.Domain, " /") {
+		return nil, p.fail("domain 格式错误")
+	}
+
+	m.Address = p.next()
+	if !addressRe.MatchString(m.Address) {
+		return nil, p.fail("地址格式错误")
+	}
+
+	// 地址之后是空行，然后是可选的 statement（后跟空行），或者直接再一个空行
+	if p.next() != "" {
+		return nil, p.fail("地址后缺少空行")
+	}
+	if line := p.next(); line != "" {
+		m.Statement = line
+		if p.next() != "" {
+			return nil, p.fail("statement 后缺少空行")
+		}
+	}
+
+	var err error
+	if m.URI, err = p.field("URI", true); err != nil {
+		return nil, err
+	}
+	if m.Version, err = p.field("Version", true); err != nil {
+		return nil, err
+	}
+	if m.Version != "1" {
+		return nil, p.fail("不支持的版本 %q", m.Version)
+	}
+	chainID, err := p.field("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil || m.ChainID <= 0 {
+		return nil, p.fail("Chain ID 格式错误")
+	}
+	if m.Nonce, err = p.field("Nonce", true); err != nil {
+		return nil, err
+	}
+	if !nonceRe.MatchString(m.Nonce) {
+		return nil, p.fail("Nonce 必须是至少 8 位的字母或数字")
+	}
+	issuedAt, err := p.field("Issued At", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.IssuedAt, err = time.Parse(time.RFC3339, issuedAt); err != nil {
+		return nil, p.fail("Issued At 格式错误")
+	}
+	if m.ExpirationTime, err = p.optionalTime("Expiration Time"); err != nil {
+		return nil, err
+	}
+	if m.NotBefore, err = p.optionalTime("Not Before"); err != nil {
+		return nil, err
+	}
+	if m.RequestID, err = p.field("Request ID", false); err != nil {
+		return nil, err
+	}
+	if p.peek() == "Resources:" {
+		p.next()
+		for p.more() {
+			resource, ok := strings.CutPrefix(p.next(), "- ")
+			if !ok || resource == "" {
+				return nil, p.fail("Resources 格式错误")
+			}
+			m.Resources = append(m.Resources, resource)
+		}
+	}
+	if p.more() {
+		return nil, p.fail("无法识别的内容 %q", p.peek())
+	}
+	return m, nil
+}
+
+// String 按规范格式生成消息文本，即钱包需要签名的内容
+func (m *Message) String() string {
+	var b strings.Builder
+	if m.Scheme != "" {
+		b.WriteString(m.Scheme + "://")
+	}
+	b.WriteString(m.Domain + headerSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "URI: %s\nVersion: %s\nChain ID: %d\nNonce: %s\nIssued At: %s", m.URI, m.Version, m.ChainID, m.Nonce, m.IssuedAt.Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\nNot Before: " + m.NotBefore.Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// parser 按行读取消息
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) more() bool {
+	return p.pos < len(p.lines)
+}
+
+func (p *parser) peek() string {
+	if !p.more() {
+		return ""
+	}
+	return p.lines[p.pos]
+}
+
+func (p *parser) next() string {
+	line := p.peek()
+	p.pos++
+	return line
+}
+
+// field 读取 "Name: value" 行，可选字段不存在时返回空字符串
+func (p *parser) field(name string, required bool) (string, error) {
+	value, ok := strings.CutPrefix(p.peek(), name+": ")
+	if !ok {
+		if required {
+			return "", p.fail("缺少 %s", name)
+		}
+		return "", nil
+	}
+	p.next()
+	return value, nil
+}
+
+// optionalTime 读取可选的时间字段
+func (p *parser) optionalTime(name string) (*time.Time, error) {
+	value, err := p.field(name, false)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, p.fail("%s 格式错误", name)
+	}
+	return &t, nil
+}
+
+func (p *parser) fail(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: 第 %d 行%s", ErrInvalidMessage, p.pos, fmt.Sprintf(format, args...))
+}
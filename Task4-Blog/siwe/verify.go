@@ -0,0 +1,156 @@
+package siwe
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// 校验失败的原因
+var (
+	ErrDomainMismatch   = errors.New("消息中的 domain 与本站不符")
+	ErrChainNotAllowed  = errors.New("不支持该链")
+	ErrExpired          = errors.New("消息已过期")
+	ErrNotYetValid      = errors.New("消息尚未生效")
+	ErrInvalidSignature = errors.New("签名无效")
+	ErrAddressMismatch  = errors.New("签名地址与消息中的地址不符")
+	ErrNonce            = errors.New("nonce 无效、已使用或已过期")
+)
+
+// NonceStore 本站签发的一次性 nonce
+type NonceStore interface {
+	// Consume 消耗 nonce，nonce 由本站签发、未过期且未被使用时返回 true
+	Consume(nonce string) (bool, error)
+}
+
+// VerifyOptions 校验参数
+type VerifyOptions struct {
+	Domain   string     // 本站域名（可带端口），与消息中的 domain 比较
+	ChainIDs []int64    // 允许的链 ID，为空时不限制
+	Nonces   NonceStore // 签名验证通过后从中消耗消息的 nonce，为 nil 时不检查
+	Now      time.Time  // 当前时间，零值时使用 time.Now()
+}
+
+// Verify 检查消息的 domain、链和有效期，验证 personal_sign 签名确实来自消息中的地址，
+// 签名有效后再消耗 nonce，避免伪造的请求耗尽别人的 nonce
+func (m *Message) Verify(signature string, opts VerifyOptions) error {
+	if !strings.EqualFold(m.Domain, opts.Domain) {
+		return ErrDomainMismatch
+	}
+	if len(opts.ChainIDs) > 0 {
+		allowed := false
+		for _, id := range opts.ChainIDs {
+			allowed = allowed || id == m.ChainID
+		}
+		if !allowed {
+			return ErrChainNotAllowed
+		}
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return ErrExpired
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return ErrNotYetValid
+	}
+
+	// 消息中的地址为全小写或全大写时不带校验和，否则必须是正确的 EIP-55 格式
+	if hasMixedCase(m.Address[2:]) && ChecksumAddress(m.Address) != m.Address {
+		return fmt.Errorf("%w: 地址校验和错误", ErrInvalidMessage)
+	}
+
+	text := m.raw
+	if text == "" {
+		text = m.String()
+	}
+	recovered, err := RecoverAddress(text, signature)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(recovered, m.Address) {
+		return ErrAddressMismatch
+	}
+
+	if opts.Nonces != nil {
+		ok, err := opts.Nonces.Consume(m.Nonce)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNonce
+		}
+	}
+	return nil
+}
+
+// RecoverAddress 从 personal_sign（EIP-191）签名中恢复签名者地址，返回 EIP-55 格式
+func RecoverAddress(message, signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return "", fmt.Errorf("%w: 应为 65 字节的十六进制", ErrInvalidSignature)
+	}
+
+	// 以太坊签名为 r || s || v，v 为 27/28（部分钱包为 0/1）；
+	// secp256k1 库的紧凑格式为 (27 + recid) || r || s
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", fmt.Errorf("%w: v 值错误", ErrInvalidSignature)
+	}
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	pub, _, err := ecdsa.RecoverCompact(compact, PersonalMessageHash(message))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	// 地址为公钥（去掉 0x04 前缀）Keccak-256 哈希的后 20 字节
+	hash := keccak256(pub.SerializeUncompressed()[1:])
+	return ChecksumAddress("0x" + hex.EncodeToString(hash[12:])), nil
+}
+
+// PersonalMessageHash personal_sign 签名的消息哈希：
+// keccak256("\x19Ethereum Signed Message:\n" + 字节长度 + 消息)
+func PersonalMessageHash(message string) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))
+	return keccak256([]byte(prefix), []byte(message))
+}
+
+// ChecksumAddress 返回 EIP-55 格式（大小写校验和）的地址
+func ChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := hex.EncodeToString(keccak256([]byte(lower)))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+func hasMixedCase(s string) bool {
+	return strings.ToLower(s) != s && strings.ToUpper(s) != s
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,265 @@
+package siwe
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// 固定的测试私钥（Hardhat/Anvil 默认账户 0），对应地址为公开已知的值
+const (
+	testPrivateKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	testAddress    = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	otherKey       = "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// memoryNonces 内存中的一次性 nonce
+type memoryNonces map[string]bool
+
+func (n memoryNonces) Consume(nonce string) (bool, error) {
+	if !n[nonce] {
+		return false, nil
+	}
+	delete(n, nonce)
+	return true, nil
+}
+
+// testMessage 生成一条有效期为 testNow 前后各一小时的消息
+func testMessage() *Message {
+	expires := testNow.Add(time.Hour)
+	return &Message{
+		Scheme:         "https",
+		Domain:         "blog.example.com",
+		Address:        testAddress,
+		Statement:      "Sign in to the blog.",
+		URI:            "https://blog.example.com/login",
+		Version:        "1",
+		ChainID:        1,
+		Nonce:          "a1b2c3d4e5f60718",
+		IssuedAt:       testNow.Add(-time.Hour),
+		ExpirationTime: &expires,
+		Resources:      []string{"https://blog.example.com/terms"},
+	}
+}
+
+// sign 用 personal_sign 对消息签名，返回 r || s || v（v 为 27/28）的十六进制
+func sign(t *testing.T, keyHex, message string) string {
+	t.Helper()
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact := ecdsa.SignCompact(secp256k1.PrivKeyFromBytes(key), PersonalMessageHash(message), false)
+	sig := append(append([]byte{}, compact[1:]...), compact[0])
+	return "0x" + hex.EncodeToString(sig)
+}
+
+// parse 解析消息文本，失败时结束测试
+func parse(t *testing.T, text string) *Message {
+	t.Helper()
+	m, err := ParseMessage(text)
+	if err != nil {
+		t.Fatalf("解析消息失败: %v", err)
+	}
+	return m
+}
+
+func TestVerifyValidMessage(t *testing.T) {
+	text := testMessage().String()
+	m := parse(t, text)
+	nonces := memoryNonces{m.Nonce: true}
+
+	err := m.Verify(sign(t, testPrivateKey, text), VerifyOptions{
+		Domain:   "Blog.Example.com",
+		ChainIDs: []int64{1, 10},
+		Nonces:   nonces,
+		Now:      testNow,
+	})
+	if err != nil {
+		t.Fatalf("有效消息校验失败: %v", err)
+	}
+	if nonces[m.Nonce] {
+		t.Error("校验通过后 nonce 应被消耗")
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *Message)
+		opts   VerifyOptions
+		want   error
+	}{
+		{
+			name: "domain 不符",
+			opts: VerifyOptions{Domain: "evil.example.com"},
+			want: ErrDomainMismatch,
+		},
+		{
+			name: "链不允许",
+			opts: VerifyOptions{ChainIDs: []int64{137}},
+			want: ErrChainNotAllowed,
+		},
+		{
+			name: "已过期",
+			opts: VerifyOptions{Now: testNow.Add(time.Hour)},
+			want: ErrExpired,
+		},
+		{
+			name: "尚未生效",
+			modify: func(m *Message) {
+				notBefore := testNow.Add(time.Minute)
+				m.NotBefore = &notBefore
+			},
+			want: ErrNotYetValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMessage()
+			if tt.modify != nil {
+				tt.modify(m)
+			}
+			text := m.String()
+			opts := tt.opts
+			if opts.Domain == "" {
+				opts.Domain = m.Domain
+			}
+			if opts.Now.IsZero() {
+				opts.Now = testNow
+			}
+			err := parse(t, text).Verify(sign(t, testPrivateKey, text), opts)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("期望 %v，实际 %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVerifyNonce(t *testing.T) {
+	text := testMessage().String()
+	signature := sign(t, testPrivateKey, text)
+	opts := func(nonces NonceStore) VerifyOptions {
+		return VerifyOptions{Domain: "blog.example.com", Nonces: nonces, Now: testNow}
+	}
+
+	t.Run("重复使用", func(t *testing.T) {
+		m := parse(t, text)
+		nonces := memoryNonces{m.Nonce: true}
+		if err := m.Verify(signature, opts(nonces)); err != nil {
+			t.Fatalf("首次使用失败: %v", err)
+		}
+		if err := m.Verify(signature, opts(nonces)); !errors.Is(err, ErrNonce) {
+			t.Errorf("重复使用应返回 ErrNonce，实际 %v", err)
+		}
+	})
+
+	t.Run("未签发", func(t *testing.T) {
+		m := parse(t, text)
+		if err := m.Verify(signature, opts(memoryNonces{"ffffffffffffffff": true})); !errors.Is(err, ErrNonce) {
+			t.Errorf("未签发的 nonce 应返回 ErrNonce，实际 %v", err)
+		}
+	})
+
+	t.Run("篡改后不消耗", func(t *testing.T) {
+		// 替换消息中的 nonce 后签名不再匹配，且不能消耗别人的 nonce
+		tampered := strings.Replace(text, "Nonce: a1b2c3d4e5f60718", "Nonce: 9999999999999999", 1)
+		nonces := memoryNonces{"9999999999999999": true}
+		if err := parse(t, tampered).Verify(signature, opts(nonces)); !errors.Is(err, ErrAddressMismatch) {
+			t.Errorf("篡改 nonce 应返回 ErrAddressMismatch，实际 %v", err)
+		}
+		if !nonces["9999999999999999"] {
+			t.Error("签名无效时不应消耗 nonce")
+		}
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	text := testMessage().String()
+	signature := sign(t, testPrivateKey, text)
+	opts := VerifyOptions{Domain: "blog.example.com", Now: testNow}
+
+	// 部分钱包返回的 v 为 0/1
+	raw, _ := hex.DecodeString(signature[2:])
+	raw[64] -= 27
+	if err := parse(t, text).Verify("0x"+hex.EncodeToString(raw), opts); err != nil {
+		t.Errorf("v 为 0/1 的签名校验失败: %v", err)
+	}
+
+	raw[64] = 29
+	if err := parse(t, text).Verify("0x"+hex.EncodeToString(raw), opts); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("v 为 29 应返回 ErrInvalidSignature，实际 %v", err)
+	}
+	if err := parse(t, text).Verify(signature[:len(signature)-2], opts); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("长度不足应返回 ErrInvalidSignature，实际 %v", err)
+	}
+	if err := parse(t, text).Verify(sign(t, otherKey, text), opts); !errors.Is(err, ErrAddressMismatch) {
+		t.Errorf("其他账户的签名应返回 ErrAddressMismatch，实际 %v", err)
+	}
+}
+
+func TestRecoverAddress(t *testing.T) {
+	text := "hello"
+	got, err := RecoverAddress(text, sign(t, testPrivateKey, text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != testAddress {
+		t.Errorf("恢复的地址为 %s，期望 %s", got, testAddress)
+	}
+}
+
+func TestParseMessageRoundTrip(t *testing.T) {
+	want := testMessage()
+	got := parse(t, want.String())
+	if got.String() != want.String() {
+		t.Errorf("重新生成的消息不一致:\n%s\n---\n%s", got.String(), want.String())
+	}
+	if got.Scheme != "https" || got.Domain != want.Domain || got.Nonce != want.Nonce || len(got.Resources) != 1 {
+		t.Errorf("解析结果错误: %+v", got)
+	}
+}
+
+func TestParseMessageMalformed(t *testing.T) {
+	valid := testMessage().String()
+	tests := map[string]string{
+		"缺少标题":        strings.Replace(valid, headerSuffix, " wants you to sign in", 1),
+		"地址格式错误":      strings.Replace(valid, testAddress, "0x1234", 1),
+		"缺少 URI":      strings.Replace(valid, "URI: https://blog.example.com/login\n", "", 1),
+		"版本错误":        strings.Replace(valid, "Version: 1", "Version: 2", 1),
+		"Chain ID 错误": strings.Replace(valid, "Chain ID: 1", "Chain ID: abc", 1),
+		"Nonce 过短":    strings.Replace(valid, "Nonce: a1b2c3d4e5f60718", "Nonce: abc", 1),
+		"时间格式错误":      strings.Replace(valid, testNow.Add(-time.Hour).Format(time.RFC3339), "yesterday", 1),
+		"多余内容":        valid + "\nExtra: field",
+		"空消息":         "",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseMessage(text); !errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("期望 ErrInvalidMessage，实际 %v", err)
+			}
+		})
+	}
+}
+
+func TestChecksumAddress(t *testing.T) {
+	// EIP-55 规范中的示例
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		if got := ChecksumAddress(strings.ToLower(want)); got != want {
+			t.Errorf("ChecksumAddress(%s) = %s", strings.ToLower(want), got)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"bytes"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// FormatFrontMatter 生成带 YAML front matter 的 Markdown 文档
+func FormatFrontMatter(meta interface{}, body string) ([]byte, error) {
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+	buf.WriteString(body)
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -26,9 +28,26 @@ func ValidatePasswordStrength(password string) error {
 	if len(password) < 6 {
 		return fmt.Errorf("密码长度至少6位")
 	}
-	
+
 	// 可以添加更多的密码强度规则
 	// 例如：必须包含数字、字母、特殊字符等
-	
+
 	return nil
-}
\ No newline at end of file
+}
+
+// passwordAlphabet 随机密码使用的字符集（去掉了易混淆的字符）
+const passwordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateRandomPassword 生成指定长度的随机密码
+func GenerateRandomPassword(length int) (string, error) {
+	buf := make([]byte, length)
+	max := big.NewInt(int64(len(passwordAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("生成随机密码失败: %v", err)
+		}
+		buf[i] = passwordAlphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"strings"
+)
+
+// Slugify 将标题转换为 URL 友好标识（小写字母、数字和连字符），非 ASCII 字符会被忽略
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, ch := range strings.ToLower(s) {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9':
+			b.WriteRune(ch)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}